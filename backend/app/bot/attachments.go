@@ -0,0 +1,122 @@
+package bot
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/entities"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/payloads"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/database"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/telegram"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+const lampiranUsage = "Kirim foto atau dokumen dengan caption <code>#ID</code> catatan lembur untuk melampirkan bukti, atau <code>/lampiran ID</code> untuk melihat lampirannya."
+
+// hasAttachment reports whether a message carries a photo or document that /lampiran can store
+func hasAttachment(message *telegram.Message) bool {
+	return len(message.Photo) > 0 || message.Document != nil
+}
+
+// attachmentArgs reads the record ID from the caption of a photo or document: "#12", "12" or "/lampiran 12"
+func attachmentArgs(caption string) string {
+	if name, args, ok := ParseCommand(caption); ok {
+		if name == "lampiran" {
+			return args
+		}
+		return ""
+	}
+	return strings.TrimSpace(caption)
+}
+
+func formatFileSize(size int64) string {
+	if size >= 1<<20 {
+		return fmt.Sprintf("%.1f MB", float64(size)/(1<<20))
+	}
+	return fmt.Sprintf("%.0f KB", float64(size)/(1<<10))
+}
+
+func (b *Bot) cmdLampiran(c *fiber.Ctx, cmd *CommandContext) error {
+	fields := strings.Fields(cmd.Args)
+	if len(fields) == 0 {
+		return b.reply(c, cmd.ChatID(), lampiranUsage)
+	}
+	id, err := strconv.ParseUint(strings.TrimPrefix(fields[0], "#"), 10, 32)
+	if err != nil || id == 0 {
+		return b.reply(c, cmd.ChatID(), lampiranUsage)
+	}
+
+	if hasAttachment(cmd.Message) {
+		return b.saveAttachment(c, cmd, uint(id))
+	}
+
+	response, err := callService(c, func() error {
+		return b.OvertimeAttachmentService.ListAttachments(uint(id), c, database.ClientPostgres)
+	})
+	if err != nil {
+		return err
+	}
+	if !response.OK() {
+		return b.reply(c, cmd.ChatID(), html.EscapeString(response.Message))
+	}
+	var attachments []entities.OvertimeAttachment
+	if err := json.Unmarshal(response.Data, &attachments); err != nil {
+		return err
+	}
+	if len(attachments) == 0 {
+		return b.reply(c, cmd.ChatID(), fmt.Sprintf("Catatan lembur #%d belum punya lampiran.\n\n%s", id, lampiranUsage))
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "📎 <b>Lampiran catatan lembur #%d</b>\n", id)
+	for _, attachment := range attachments {
+		fmt.Fprintf(&sb, "• %s (%s)\n", html.EscapeString(attachment.FileName), formatFileSize(attachment.Size))
+	}
+	return b.reply(c, cmd.ChatID(), sb.String())
+}
+
+// saveAttachment downloads the photo (largest size) or document of the message from Telegram and stores it on the record
+func (b *Bot) saveAttachment(c *fiber.Ctx, cmd *CommandContext, overtimeID uint) error {
+	message := cmd.Message
+	var fileID, fileName string
+	var fileSize int64
+	if message.Document != nil {
+		fileID, fileName, fileSize = message.Document.FileID, message.Document.FileName, message.Document.FileSize
+	} else {
+		photo := message.Photo[len(message.Photo)-1]
+		fileID, fileName, fileSize = photo.FileID, fmt.Sprintf("foto-%d.jpg", message.MessageID), photo.FileSize
+	}
+
+	maxSize := services.AttachmentMaxSize()
+	if maxSize > telegram.MaxDownloadSize {
+		maxSize = telegram.MaxDownloadSize
+	}
+	if fileSize > maxSize {
+		return b.reply(c, cmd.ChatID(), fmt.Sprintf("File terlalu besar, maksimal %s.", formatFileSize(maxSize)))
+	}
+	file, err := b.Client.GetFile(c.Context(), telegram.GetFileParams{FileID: fileID})
+	if err != nil {
+		return err
+	}
+	data, err := b.Client.DownloadFile(c.Context(), file, maxSize)
+	if err != nil {
+		return err
+	}
+
+	tx := database.ClientPostgres.Begin()
+	defer tx.Rollback()
+
+	response, err := callService(c, func() error {
+		return b.OvertimeAttachmentService.UploadAttachments(overtimeID, []payloads.AttachmentUpload{{FileName: fileName, Data: data}}, entities.AttachmentSourceTelegram, c, tx)
+	})
+	if err != nil {
+		return err
+	}
+	if !response.OK() {
+		return b.reply(c, cmd.ChatID(), "Gagal menyimpan lampiran: "+html.EscapeString(response.Message))
+	}
+	return b.reply(c, cmd.ChatID(), fmt.Sprintf("📎 Lampiran disimpan di catatan lembur #%d. Lihat semua dengan <code>/lampiran %d</code>", overtimeID, overtimeID))
+}
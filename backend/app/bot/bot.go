@@ -0,0 +1,101 @@
+package bot
+
+import (
+	"context"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/helpers"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/telegram"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/repositories"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Bot holds the bot logic that used to live in a separate script calling the REST API.
+// It talks to the same services as the HTTP controllers.
+type Bot struct {
+	Client             *telegram.Client
+	OvertimeService    services.OvertimeService
+	TelegramService    services.TelegramService
+	OvertimeRepository repositories.OvertimeRepository
+	TelegramRepository repositories.TelegramRepository
+	UserRepository     repositories.UserRepository
+	CategoryRepository repositories.CategoryRepository
+
+	ConversationStateRepository repositories.ConversationStateRepository
+	OvertimeFeedService         services.OvertimeFeedService
+	OvertimeAttachmentService   services.OvertimeAttachmentService
+
+	commands   []Command
+	dispatcher *Dispatcher
+}
+
+func New(client *telegram.Client) *Bot {
+	b := &Bot{Client: client}
+	b.registerCommands()
+	return b
+}
+
+// Register attaches the bot handlers to the dispatcher
+func (b *Bot) Register(d *Dispatcher) {
+	b.dispatcher = d
+	d.Handle(telegram.UpdateTypeMessage, b.handleMessage)
+	d.Handle(telegram.UpdateTypeCallbackQuery, b.handleCallbackQuery)
+}
+
+// Setup publishes the command list to Telegram and points the bot at the webhook or clears it for polling.
+// TELEGRAM_WEBHOOK_URL is optional, without it the webhook is left as configured in BotFather.
+func (b *Bot) Setup(ctx context.Context, mode string) error {
+	commands := make([]telegram.BotCommand, 0, len(b.commands))
+	for _, command := range b.commands {
+		commands = append(commands, telegram.BotCommand{Command: command.Name, Description: command.Description})
+	}
+	if err := b.Client.SetMyCommands(ctx, telegram.SetMyCommandsParams{Commands: commands}); err != nil {
+		return err
+	}
+
+	if mode == "polling" {
+		return b.Client.DeleteWebhook(ctx, telegram.DeleteWebhookParams{})
+	}
+	if webhookURL := helpers.GetEnv("TELEGRAM_WEBHOOK_URL", ""); webhookURL != "" {
+		return b.Client.SetWebhook(ctx, telegram.SetWebhookParams{
+			URL:         webhookURL,
+			SecretToken: helpers.GetEnv("TELEGRAM_WEBHOOK_SECRET", ""),
+		})
+	}
+	return nil
+}
+
+// reply sends an HTML formatted message to a chat
+func (b *Bot) reply(c *fiber.Ctx, chatID int64, text string) error {
+	_, err := b.send(c, chatID, text, nil)
+	return err
+}
+
+// send sends an HTML formatted message with an optional inline keyboard
+func (b *Bot) send(c *fiber.Ctx, chatID int64, text string, markup *telegram.InlineKeyboardMarkup) (*telegram.Message, error) {
+	return b.Client.SendMessage(c.Context(), telegram.SendMessageParams{
+		ChatID:      chatID,
+		Text:        text,
+		ParseMode:   telegram.ParseModeHTML,
+		ReplyMarkup: markup,
+	})
+}
+
+// edit replaces the text and inline keyboard of a message sent by the bot
+func (b *Bot) edit(c *fiber.Ctx, chatID int64, messageID int64, text string, markup *telegram.InlineKeyboardMarkup) error {
+	return b.Client.EditMessageText(c.Context(), telegram.EditMessageTextParams{
+		ChatID:      chatID,
+		MessageID:   messageID,
+		Text:        text,
+		ParseMode:   telegram.ParseModeHTML,
+		ReplyMarkup: markup,
+	})
+}
+
+// answerCallback stops the loading indicator on the pressed button, text is shown as a toast
+func (b *Bot) answerCallback(c *fiber.Ctx, callbackQueryID string, text string) error {
+	return b.Client.AnswerCallbackQuery(c.Context(), telegram.AnswerCallbackQueryParams{
+		CallbackQueryID: callbackQueryID,
+		Text:            text,
+	})
+}
@@ -0,0 +1,166 @@
+package bot
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/entities"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/helpers"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/telegram"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Command is a declaratively registered bot command. /help is generated from Usage and Description.
+type Command struct {
+	Name        string
+	Usage       string
+	Description string
+	// RequireLinked rejects the command when the sender has no linked TelegramUser
+	RequireLinked bool
+	Handler       CommandHandler
+}
+
+type CommandHandler func(c *fiber.Ctx, cmd *CommandContext) error
+
+// CommandContext is the parsed command passed to a CommandHandler
+type CommandContext struct {
+	Update       *telegram.Update
+	Message      *telegram.Message
+	Command      string
+	Args         string
+	TelegramUser *entities.TelegramUser
+}
+
+func (cmd *CommandContext) ChatID() int64 {
+	return cmd.Message.Chat.ID
+}
+
+func (cmd *CommandContext) SenderID() int64 {
+	if cmd.Message.From == nil {
+		return 0
+	}
+	return cmd.Message.From.ID
+}
+
+// ParseCommand splits "/command@botname args" into command and args. ok is false for non-command text.
+func ParseCommand(text string) (command string, args string, ok bool) {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "/") {
+		return "", "", false
+	}
+	fields := strings.SplitN(text, " ", 2)
+	command = strings.ToLower(strings.TrimPrefix(fields[0], "/"))
+	if at := strings.Index(command, "@"); at >= 0 {
+		command = command[:at]
+	}
+	if len(fields) > 1 {
+		args = strings.TrimSpace(fields[1])
+	}
+	return command, args, command != ""
+}
+
+// registerCommand adds commands to the router, later registrations with the same name win
+func (b *Bot) registerCommand(commands ...Command) {
+	for _, command := range commands {
+		for i := range b.commands {
+			if b.commands[i].Name == command.Name {
+				b.commands = append(b.commands[:i], b.commands[i+1:]...)
+				break
+			}
+		}
+		b.commands = append(b.commands, command)
+	}
+}
+
+func (b *Bot) findCommand(name string) *Command {
+	for i := range b.commands {
+		if b.commands[i].Name == name {
+			return &b.commands[i]
+		}
+	}
+	return nil
+}
+
+// Commands returns the registered commands (used for /help and setMyCommands)
+func (b *Bot) Commands() []Command {
+	return b.commands
+}
+
+func (b *Bot) handleMessage(c *fiber.Ctx, update *telegram.Update) error {
+	message := update.Message
+	if message == nil || message.From == nil {
+		return nil
+	}
+	helpers.LogTelegramMessage(message.Chat.ID, message.From.ID, "text", message.Text, map[string]interface{}{
+		"message_id": message.MessageID,
+	})
+
+	// foto atau dokumen dengan caption "#ID" dilampirkan ke catatan lembur
+	if hasAttachment(message) {
+		return b.runCommand(c, update, "lampiran", attachmentArgs(message.Caption))
+	}
+
+	name, args, ok := ParseCommand(message.Text)
+	if !ok {
+		// "lembur 18:00-21:30 ..." tanpa garis miring diperlakukan sama dengan /lembur
+		fields := strings.SplitN(strings.TrimSpace(message.Text), " ", 2)
+		if len(fields) == 2 && strings.EqualFold(fields[0], "lembur") {
+			return b.runCommand(c, update, "lembur", strings.TrimSpace(fields[1]))
+		}
+		return b.handleConversationText(c, update)
+	}
+	return b.runCommand(c, update, name, args)
+}
+
+func (b *Bot) runCommand(c *fiber.Ctx, update *telegram.Update, name string, args string) error {
+	message := update.Message
+	helpers.LogTelegramCommand(message.Chat.ID, message.From.ID, name, args, map[string]interface{}{
+		"message_id": message.MessageID,
+	})
+
+	command := b.findCommand(name)
+	if command == nil {
+		return b.reply(c, message.Chat.ID, fmt.Sprintf("Perintah /%s tidak dikenal. Ketik /help untuk daftar perintah.", html.EscapeString(name)))
+	}
+
+	telegramUser, err := b.resolveSender(c, message.From)
+	if err != nil {
+		_ = b.reply(c, message.Chat.ID, "Terjadi kesalahan, silakan coba lagi.")
+		return err
+	}
+	if command.RequireLinked && telegramUser == nil {
+		return b.reply(c, message.Chat.ID, "Akun Telegram kamu belum terhubung. Hubungkan akun dari aplikasi lalu coba lagi.")
+	}
+
+	cmd := &CommandContext{
+		Update:       update,
+		Message:      message,
+		Command:      name,
+		Args:         args,
+		TelegramUser: telegramUser,
+	}
+	if err := command.Handler(c, cmd); err != nil {
+		_ = b.reply(c, message.Chat.ID, "Terjadi kesalahan, silakan coba lagi.")
+		return err
+	}
+	return nil
+}
+
+// helpText builds /help from the registered commands
+func (b *Bot) helpText() string {
+	var sb strings.Builder
+	sb.WriteString("<b>Daftar perintah</b>\n")
+	for _, command := range b.commands {
+		usage := "/" + command.Name
+		if command.Usage != "" {
+			usage += " " + command.Usage
+		}
+		sb.WriteString(fmt.Sprintf("\n<code>%s</code>\n%s\n", html.EscapeString(usage), html.EscapeString(command.Description)))
+	}
+	return sb.String()
+}
+
+func (b *Bot) cmdHelp(c *fiber.Ctx, cmd *CommandContext) error {
+	return b.reply(c, cmd.ChatID(), b.helpText())
+}
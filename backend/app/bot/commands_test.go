@@ -0,0 +1,139 @@
+package bot
+
+import (
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/telegram"
+)
+
+func TestParseCommand(t *testing.T) {
+	tests := []struct {
+		text     string
+		wantName string
+		wantArgs string
+		wantOK   bool
+	}{
+		{text: "/help", wantName: "help", wantOK: true},
+		{text: "  /Lembur 18:00-21:00  deploy  ", wantName: "lembur", wantArgs: "18:00-21:00  deploy", wantOK: true},
+		{text: "/rekap@LemburBot minggu", wantName: "rekap", wantArgs: "minggu", wantOK: true},
+		{text: "/", wantOK: false},
+		{text: "lembur 18:00-21:00", wantOK: false},
+		{text: "", wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			name, args, ok := ParseCommand(tt.text)
+			if name != tt.wantName || args != tt.wantArgs || ok != tt.wantOK {
+				t.Errorf("ParseCommand(%q) = %q, %q, %v, want %q, %q, %v", tt.text, name, args, ok, tt.wantName, tt.wantArgs, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestAttachmentArgs(t *testing.T) {
+	tests := []struct {
+		caption string
+		want    string
+	}{
+		{caption: "#12", want: "#12"},
+		{caption: " 12 ", want: "12"},
+		{caption: "/lampiran 12", want: "12"},
+		{caption: "/lampiran@LemburBot #12", want: "#12"},
+		{caption: "/rekap 12", want: ""},
+		{caption: "", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.caption, func(t *testing.T) {
+			if got := attachmentArgs(tt.caption); got != tt.want {
+				t.Errorf("attachmentArgs(%q) = %q, want %q", tt.caption, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommandRouting(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		photo     bool
+		linked    bool
+		dbErr     bool
+		wantReply string // kosong = tidak ada balasan
+		wantSQL   string
+	}{
+		{name: "help", text: "/help", wantReply: "<b>Daftar perintah</b>"},
+		{name: "help addressed to the bot", text: "/HELP@LemburBot", wantReply: "<code>/rekapotomatis [on|off]</code>"},
+		{name: "unknown command", text: "/terbang <b>", wantReply: "Perintah /terbang tidak dikenal. Ketik /help untuk daftar perintah."},
+		{name: "unlinked sender", text: "/rekap", wantReply: "Akun Telegram kamu belum terhubung."},
+		{name: "lembur without slash", text: "Lembur 18:00-21:00 deploy", wantReply: "Akun Telegram kamu belum terhubung."},
+		{name: "photo goes to lampiran", photo: true, wantReply: "Akun Telegram kamu belum terhubung."},
+		{name: "plain text without conversation", text: "halo", wantSQL: `FROM "conversation_states"`},
+		{name: "linked sender", text: "/rekapotomatis", linked: true, wantReply: "Rekap otomatis mingguan dan bulanan: <b>aktif</b>"},
+		{name: "linked sender with args", text: "/rekapotomatis off", linked: true, wantReply: "✅ Rekap otomatis dimatikan.", wantSQL: `SET "recap_enabled"`},
+		{name: "database error", text: "/rekap", dbErr: true, wantReply: "Terjadi kesalahan, silakan coba lagi."},
+		{name: "handler error", text: "/rekapotomatis on", linked: true, dbErr: true, wantReply: "Terjadi kesalahan, silakan coba lagi."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newFakeDB(t)
+			if tt.linked {
+				db.On(`FROM "telegram_users"`, []string{"id", "user_id", "telegram_id", "first_name", "recap_enabled"},
+					[]driver.Value{int64(1), int64(11), int64(42), "Budi", true})
+				db.On(`FROM "users"`, []string{"id", "name"}, []driver.Value{int64(11), "Budi"})
+				if tt.dbErr {
+					db.Fail(`UPDATE "telegram_users"`, errors.New("connection reset"))
+				}
+			} else if tt.dbErr {
+				db.Fail(`FROM "telegram_users"`, errors.New("connection reset"))
+			}
+
+			b, fake := newTestBot(t, sentMessage)
+			update := messageUpdate(10, tt.text)
+			if tt.photo {
+				update.Message.Photo = []telegram.PhotoSize{{FileID: "photo", FileUniqueID: "photo"}}
+				update.Message.Caption = "#12"
+			}
+			if err := b.dispatcher.Dispatch(update); err != nil {
+				t.Fatalf("Dispatch() error = %v", err)
+			}
+
+			messages := fake.Calls("sendMessage")
+			if tt.wantReply == "" {
+				if len(messages) != 0 {
+					t.Errorf("sendMessage calls = %v, want none", messages)
+				}
+			} else {
+				if len(messages) != 1 {
+					t.Fatalf("sendMessage calls = %d, want 1", len(messages))
+				}
+				if chat := messages[0].Params["chat_id"]; chat != float64(42) {
+					t.Errorf("reply sent to %v, want 42", chat)
+				}
+				if text, _ := messages[0].Params["text"].(string); !strings.Contains(text, tt.wantReply) {
+					t.Errorf("reply = %q, want it to contain %q", text, tt.wantReply)
+				}
+			}
+			if tt.wantSQL != "" && len(db.Statements(tt.wantSQL)) == 0 {
+				t.Errorf("no statement containing %q", tt.wantSQL)
+			}
+		})
+	}
+}
+
+func TestUnknownCommandIsEscaped(t *testing.T) {
+	newFakeDB(t)
+	b, fake := newTestBot(t, sentMessage)
+	if err := b.dispatcher.Dispatch(messageUpdate(10, "/<b>")); err != nil {
+		t.Fatalf("Dispatch() error = %v", err)
+	}
+	messages := fake.Calls("sendMessage")
+	if len(messages) != 1 {
+		t.Fatalf("sendMessage calls = %d, want 1", len(messages))
+	}
+	if text, _ := messages[0].Params["text"].(string); !strings.Contains(text, "/&lt;b&gt;") {
+		t.Errorf("reply = %q, want the command HTML escaped", text)
+	}
+}
@@ -0,0 +1,71 @@
+package bot
+
+import (
+	"encoding/json"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/entities"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/database"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/helpers"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/telegram"
+	"github.com/gofiber/fiber/v2"
+)
+
+// serviceResponse mirrors the body written by helpers.Response
+type serviceResponse struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// OK reports whether the service answered with a 2xx status
+func (r serviceResponse) OK() bool {
+	return r.Code >= 200 && r.Code < 300
+}
+
+// callService runs a service method that writes its result with helpers.Response and decodes that result.
+// The returned error is only set for internal errors, client errors are returned as a non-2xx serviceResponse.
+func callService(c *fiber.Ctx, call func() error) (serviceResponse, error) {
+	c.Response().Reset()
+	if err := call(); err != nil {
+		return serviceResponse{}, err
+	}
+	var response serviceResponse
+	if err := json.Unmarshal(c.Response().Body(), &response); err != nil {
+		return serviceResponse{}, err
+	}
+	return response, nil
+}
+
+// resolveSender loads the linked telegram user of the sender and sets the same locals as AuthMiddleware.
+// Returns nil without error when the sender is not linked yet.
+func (b *Bot) resolveSender(c *fiber.Ctx, sender *telegram.User) (*entities.TelegramUser, error) {
+	if sender == nil {
+		return nil, nil
+	}
+	if telegramUser, ok := c.Locals("telegram_user").(entities.TelegramUser); ok && telegramUser.TelegramID == sender.ID {
+		return &telegramUser, nil
+	}
+
+	tx := database.ClientPostgres
+	var telegramUser entities.TelegramUser
+	if err := b.TelegramRepository.FindByTelegramID(sender.ID, &telegramUser, c, tx); err != nil {
+		if helpers.IsNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var user entities.User
+	if err := b.UserRepository.FindByID(telegramUser.UserID, &user, tx); err != nil {
+		if helpers.IsNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	c.Locals("user_id", user.ID)
+	c.Locals("user", user)
+	c.Locals("auth_type", "telegram_bot")
+	c.Locals("telegram_id", telegramUser.TelegramID)
+	c.Locals("telegram_user", telegramUser)
+	return &telegramUser, nil
+}
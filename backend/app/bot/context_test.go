@@ -0,0 +1,162 @@
+package bot
+
+import (
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/entities"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/helpers"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/telegram"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestCallService(t *testing.T) {
+	respond := func(status int, message string, data interface{}) func(c *fiber.Ctx) error {
+		return func(c *fiber.Ctx) error { return helpers.Response(c, status, message, data) }
+	}
+	tests := []struct {
+		name        string
+		call        func(c *fiber.Ctx) error
+		wantErr     bool
+		wantOK      bool
+		wantCode    int
+		wantMessage string
+		wantData    string
+	}{
+		{
+			name:        "success",
+			call:        respond(fiber.StatusCreated, "Overtime created", map[string]int{"id": 7}),
+			wantOK:      true,
+			wantCode:    fiber.StatusCreated,
+			wantMessage: "Overtime created",
+			wantData:    `{"id":7}`,
+		},
+		{
+			name:        "client error",
+			call:        respond(fiber.StatusNotFound, "Overtime record not found", nil),
+			wantCode:    fiber.StatusNotFound,
+			wantMessage: "Overtime record not found",
+			wantData:    "null",
+		},
+		{
+			name:        "conflict",
+			call:        respond(fiber.StatusConflict, "Overtime overlaps", nil),
+			wantCode:    fiber.StatusConflict,
+			wantMessage: "Overtime overlaps",
+			wantData:    "null",
+		},
+		{
+			name:    "internal error",
+			call:    func(*fiber.Ctx) error { return errors.New("connection reset") },
+			wantErr: true,
+		},
+		{
+			name:    "body is not json",
+			call:    func(c *fiber.Ctx) error { return c.SendString("Internal Server Error") },
+			wantErr: true,
+		},
+		{
+			name:    "nothing written",
+			call:    func(*fiber.Ctx) error { return nil },
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, release := NewDispatcher().NewContext()
+			defer release()
+			// jawaban service sebelumnya tidak boleh terbaca lagi
+			_ = helpers.Response(c, fiber.StatusOK, "previous call", nil)
+
+			response, err := callService(c, func() error { return tt.call(c) })
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("callService() = %+v, want error", response)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("callService() error = %v", err)
+			}
+			if response.OK() != tt.wantOK || response.Code != tt.wantCode || response.Message != tt.wantMessage || string(response.Data) != tt.wantData {
+				t.Errorf("callService() = {code %d, ok %v, message %q, data %s}, want {code %d, ok %v, message %q, data %s}",
+					response.Code, response.OK(), response.Message, response.Data, tt.wantCode, tt.wantOK, tt.wantMessage, tt.wantData)
+			}
+		})
+	}
+}
+
+func TestResolveSender(t *testing.T) {
+	telegramUserColumns := []string{"id", "user_id", "telegram_id", "first_name"}
+	tests := []struct {
+		name       string
+		setup      func(db *fakeDB)
+		wantLinked bool
+		wantErr    bool
+	}{
+		{
+			name: "linked",
+			setup: func(db *fakeDB) {
+				db.On(`FROM "telegram_users"`, telegramUserColumns, []driver.Value{int64(1), int64(11), int64(42), "Budi"})
+				db.On(`FROM "users"`, []string{"id"}, []driver.Value{int64(11)})
+			},
+			wantLinked: true,
+		},
+		{name: "not linked", setup: func(*fakeDB) {}},
+		{
+			name: "user deleted",
+			setup: func(db *fakeDB) {
+				db.On(`FROM "telegram_users"`, telegramUserColumns, []driver.Value{int64(1), int64(11), int64(42), "Budi"})
+			},
+		},
+		{
+			name: "database error",
+			setup: func(db *fakeDB) {
+				db.Fail(`FROM "telegram_users"`, errors.New("connection reset"))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newFakeDB(t)
+			tt.setup(db)
+			b, _ := newTestBot(t, sentMessage)
+			c, release := b.dispatcher.NewContext()
+			defer release()
+
+			telegramUser, err := b.resolveSender(c, &telegram.User{ID: 42})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("resolveSender() error = nil, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveSender() error = %v", err)
+			}
+			if (telegramUser != nil) != tt.wantLinked {
+				t.Fatalf("resolveSender() = %+v, want linked %v", telegramUser, tt.wantLinked)
+			}
+			if !tt.wantLinked {
+				return
+			}
+			if c.Locals("user_id") != uint(11) || c.Locals("auth_type") != "telegram_bot" || c.Locals("telegram_id") != int64(42) {
+				t.Errorf("locals user_id=%v auth_type=%v telegram_id=%v", c.Locals("user_id"), c.Locals("auth_type"), c.Locals("telegram_id"))
+			}
+
+			// panggilan kedua memakai locals, tidak query ulang
+			queries := len(db.Statements(`FROM "telegram_users"`))
+			if _, err := b.resolveSender(c, &telegram.User{ID: 42}); err != nil {
+				t.Fatalf("second resolveSender() error = %v", err)
+			}
+			if got := len(db.Statements(`FROM "telegram_users"`)); got != queries {
+				t.Errorf("second resolveSender() queried telegram_users again")
+			}
+			if _, ok := c.Locals("telegram_user").(entities.TelegramUser); !ok {
+				t.Errorf("telegram_user local = %T, want entities.TelegramUser", c.Locals("telegram_user"))
+			}
+		})
+	}
+}
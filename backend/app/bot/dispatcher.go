@@ -0,0 +1,145 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/entities"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/database"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/helpers"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/telegram"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/repositories"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/scheduler"
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+)
+
+// HandlerFunc handles one Telegram update. c is a standalone context (not tied to any HTTP request)
+// so handlers can reuse services and repositories that expect a *fiber.Ctx.
+type HandlerFunc func(c *fiber.Ctx, update *telegram.Update) error
+
+// Dispatcher is the handler registry shared by the webhook receiver and the long-polling runner
+type Dispatcher struct {
+	TelegramUpdateRepository repositories.TelegramUpdateRepository
+
+	app      *fiber.App
+	mu       sync.RWMutex
+	handlers map[string][]HandlerFunc
+}
+
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{
+		app:      fiber.New(),
+		handlers: make(map[string][]HandlerFunc),
+	}
+}
+
+// Handle registers a handler for an update type (telegram.UpdateTypeMessage, telegram.UpdateTypeCallbackQuery, ...)
+func (d *Dispatcher) Handle(updateType string, handler HandlerFunc) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.handlers[updateType] = append(d.handlers[updateType], handler)
+}
+
+// Dispatch dedupes the update by update_id and hands it to every handler registered for its type
+func (d *Dispatcher) Dispatch(update *telegram.Update) error {
+	start := time.Now()
+	updateType := update.Type()
+
+	c, release := d.NewContext()
+	defer release()
+
+	processed := entities.TelegramUpdate{UpdateID: update.UpdateID, Type: updateType}
+	if err := d.TelegramUpdateRepository.Create(&processed, c, database.ClientPostgres); err != nil {
+		if helpers.IsDuplicateKeyError(err) {
+			helpers.LogTelegramWebhook("duplicate_update_skipped", update.UpdateID, map[string]interface{}{
+				"update_type": updateType,
+			})
+			return nil
+		}
+		return err
+	}
+
+	d.mu.RLock()
+	handlers := d.handlers[updateType]
+	d.mu.RUnlock()
+
+	if len(handlers) == 0 {
+		helpers.LogTelegramWebhook("unhandled_update", update.UpdateID, map[string]interface{}{
+			"update_type": updateType,
+		})
+		return nil
+	}
+
+	var userID int64
+	if sender := update.Sender(); sender != nil {
+		userID = sender.ID
+	}
+	for _, handler := range handlers {
+		if err := d.runHandler(handler, c, update); err != nil {
+			helpers.LogTelegramError(err, "dispatch_"+updateType, update.ChatID(), userID, map[string]interface{}{
+				"update_id": update.UpdateID,
+			})
+		}
+	}
+
+	helpers.LogTelegramPerformance("dispatch_"+updateType, time.Since(start), update.ChatID(), userID, map[string]interface{}{
+		"update_id":     update.UpdateID,
+		"handler_count": len(handlers),
+	})
+	return nil
+}
+
+// NewContext acquires a standalone fiber context for work that does not come from an HTTP request.
+// The request ctx is initialised like a served one: a zero fasthttp.RequestCtx has no server,
+// so its Done (called by database/sql and net/http through c.Context()) dereferences nil.
+func (d *Dispatcher) NewContext() (*fiber.Ctx, func()) {
+	requestCtx := &fasthttp.RequestCtx{}
+	requestCtx.Init(&fasthttp.Request{}, nil, nil)
+	c := d.app.AcquireCtx(requestCtx)
+	return c, func() { d.app.ReleaseCtx(c) }
+}
+
+// runHandler protects the receiver loop from a panicking handler
+func (d *Dispatcher) runHandler(handler HandlerFunc, c *fiber.Ctx, update *telegram.Update) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("handler panic: %v", r)
+		}
+	}()
+	return handler(c, update)
+}
+
+// purgeJobs returns the daily cleanup of processed update_ids at TELEGRAM_UPDATE_PURGE_TIME (default 03:00).
+// Telegram keeps undelivered updates for 24 hours, so rows older than TELEGRAM_UPDATE_RETENTION_DAYS (default 7)
+// can no longer dedupe anything. None when the retention is 0.
+func (d *Dispatcher) purgeJobs() ([]scheduler.Job, error) {
+	days, err := strconv.Atoi(helpers.GetEnv("TELEGRAM_UPDATE_RETENTION_DAYS", "7"))
+	if err != nil || days < 0 {
+		return nil, fmt.Errorf("TELEGRAM_UPDATE_RETENTION_DAYS: must be a number of days, 0 = never purge")
+	}
+	if days == 0 {
+		return nil, nil
+	}
+	hour, minute, err := scheduler.ParseClock(helpers.GetEnv("TELEGRAM_UPDATE_PURGE_TIME", "03:00"))
+	if err != nil {
+		return nil, fmt.Errorf("TELEGRAM_UPDATE_PURGE_TIME: %w", err)
+	}
+	return []scheduler.Job{{
+		Name: "telegram_update_purge",
+		Next: scheduler.DailyAt(hour, minute),
+		Run: func(ctx context.Context) error {
+			c, release := d.NewContext()
+			defer release()
+			purged, err := d.TelegramUpdateRepository.DeleteCreatedBefore(time.Now().AddDate(0, 0, -days), c, database.ClientPostgres)
+			if err != nil {
+				return err
+			}
+			helpers.Logger.Info().Str("type", "scheduler").Str("job", "telegram_update_purge").Int64("purged", purged).Int("retention_days", days).Msg("Processed telegram updates purged")
+			return nil
+		},
+	}}, nil
+}
@@ -0,0 +1,180 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/entities"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/database"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/helpers"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/telegram"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/repositories"
+	"github.com/gofiber/fiber/v2"
+)
+
+func messageUpdate(updateID int64, text string) *telegram.Update {
+	return &telegram.Update{
+		UpdateID: updateID,
+		Message: &telegram.Message{
+			MessageID: 1,
+			From:      &telegram.User{ID: 42, FirstName: "Budi"},
+			Chat:      telegram.Chat{ID: 42, Type: "private"},
+			Text:      text,
+		},
+	}
+}
+
+func TestDispatchRunsHandlerAgainstDatabase(t *testing.T) {
+	db := newFakeDB(t)
+	dispatcher := NewDispatcher()
+
+	var handled int
+	var handlerErr error
+	var telegramRepository repositories.TelegramRepository
+	dispatcher.Handle(telegram.UpdateTypeMessage, func(c *fiber.Ctx, update *telegram.Update) error {
+		handled++
+		var telegramUser entities.TelegramUser
+		handlerErr = telegramRepository.FindByTelegramID(update.Message.From.ID, &telegramUser, c, database.ClientPostgres)
+		return nil
+	})
+
+	if err := dispatcher.Dispatch(messageUpdate(10, "halo")); err != nil {
+		t.Fatalf("Dispatch() error = %v", err)
+	}
+	if handled != 1 {
+		t.Fatalf("handler ran %d times, want 1", handled)
+	}
+	if !helpers.IsNotFoundError(handlerErr) {
+		t.Errorf("handler query error = %v, want record not found", handlerErr)
+	}
+
+	inserts := db.Statements(`INSERT INTO "telegram_updates"`)
+	if len(inserts) != 1 {
+		t.Fatalf("telegram_updates inserts = %d, want 1", len(inserts))
+	}
+	if got := inserts[0].Args[0]; got != int64(10) {
+		t.Errorf("inserted update_id = %v, want 10", got)
+	}
+	if len(db.Statements(`FROM "telegram_users"`)) != 1 {
+		t.Errorf("handler query did not reach the database")
+	}
+}
+
+func TestDispatchSkipsDuplicateUpdate(t *testing.T) {
+	db := newFakeDB(t)
+	db.Fail(`INSERT INTO "telegram_updates"`, errors.New(`ERROR: duplicate key value violates unique constraint "telegram_updates_pkey" (SQLSTATE 23505)`))
+	dispatcher := NewDispatcher()
+
+	handled := 0
+	dispatcher.Handle(telegram.UpdateTypeMessage, func(*fiber.Ctx, *telegram.Update) error {
+		handled++
+		return nil
+	})
+
+	if err := dispatcher.Dispatch(messageUpdate(10, "halo")); err != nil {
+		t.Fatalf("Dispatch() error = %v", err)
+	}
+	if handled != 0 {
+		t.Errorf("handler ran %d times for a duplicate update, want 0", handled)
+	}
+}
+
+func TestDispatchReturnsDatabaseError(t *testing.T) {
+	db := newFakeDB(t)
+	db.Fail(`INSERT INTO "telegram_updates"`, errors.New("connection refused"))
+	dispatcher := NewDispatcher()
+
+	handled := 0
+	dispatcher.Handle(telegram.UpdateTypeMessage, func(*fiber.Ctx, *telegram.Update) error {
+		handled++
+		return nil
+	})
+
+	if err := dispatcher.Dispatch(messageUpdate(10, "halo")); err == nil {
+		t.Fatal("Dispatch() error = nil, want the insert error so the update is retried")
+	}
+	if handled != 0 {
+		t.Errorf("handler ran %d times, want 0", handled)
+	}
+}
+
+func TestDispatchRecoversHandlerPanic(t *testing.T) {
+	newFakeDB(t)
+	dispatcher := NewDispatcher()
+
+	handled := 0
+	dispatcher.Handle(telegram.UpdateTypeMessage, func(*fiber.Ctx, *telegram.Update) error {
+		panic("boom")
+	})
+	dispatcher.Handle(telegram.UpdateTypeMessage, func(*fiber.Ctx, *telegram.Update) error {
+		handled++
+		return nil
+	})
+
+	if err := dispatcher.Dispatch(messageUpdate(10, "halo")); err != nil {
+		t.Fatalf("Dispatch() error = %v", err)
+	}
+	if handled != 1 {
+		t.Errorf("handler after the panicking one ran %d times, want 1", handled)
+	}
+}
+
+func TestPurgeJob(t *testing.T) {
+	tests := []struct {
+		name      string
+		retention string
+		wantJobs  int
+		wantErr   bool
+	}{
+		{name: "a week", retention: "7", wantJobs: 1},
+		{name: "custom retention", retention: "30", wantJobs: 1},
+		{name: "disabled", retention: "0", wantJobs: 0},
+		{name: "invalid", retention: "-1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TELEGRAM_UPDATE_RETENTION_DAYS", tt.retention)
+			jobs, err := NewDispatcher().purgeJobs()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("purgeJobs() error = nil, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("purgeJobs() error = %v", err)
+			}
+			if len(jobs) != tt.wantJobs {
+				t.Errorf("purgeJobs() returned %d jobs, want %d", len(jobs), tt.wantJobs)
+			}
+		})
+	}
+}
+
+func TestPurgeJobDeletesOldUpdates(t *testing.T) {
+	db := newFakeDB(t)
+	db.OnExec(`DELETE FROM "telegram_updates"`, 3)
+	t.Setenv("TELEGRAM_UPDATE_RETENTION_DAYS", "7")
+
+	jobs, err := NewDispatcher().purgeJobs()
+	if err != nil || len(jobs) != 1 {
+		t.Fatalf("purgeJobs() = %d jobs, %v", len(jobs), err)
+	}
+	if err := jobs[0].Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	deletes := db.Statements(`DELETE FROM "telegram_updates"`)
+	if len(deletes) != 1 {
+		t.Fatalf("telegram_updates deletes = %d, want 1", len(deletes))
+	}
+	cutoff, ok := deletes[0].Args[0].(time.Time)
+	if !ok {
+		t.Fatalf("cutoff argument = %T, want time.Time", deletes[0].Args[0])
+	}
+	if want := time.Now().AddDate(0, 0, -7); cutoff.Sub(want).Abs() > time.Minute {
+		t.Errorf("cutoff = %v, want about %v", cutoff, want)
+	}
+}
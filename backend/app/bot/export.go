@@ -0,0 +1,97 @@
+package bot
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/payloads"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/database"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/helpers"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/telegram"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/timesheet"
+	"github.com/gofiber/fiber/v2"
+)
+
+const exportUsage = "Format: <code>/export [bulan ini|bulan lalu|minggu ini|minggu lalu|YYYY-MM-DD YYYY-MM-DD] [pdf|xlsx|csv]</code>"
+
+// exportPeriod reads the period and format of /export relative to today, default bulan ini as pdf.
+// Weeks run Monday to Sunday, months cover the whole calendar month.
+func exportPeriod(args string, today time.Time) (time.Time, time.Time, string, bool) {
+	format := timesheet.FormatPDF
+	fields := strings.Fields(strings.ToLower(args))
+	if len(fields) > 0 {
+		for _, candidate := range timesheet.Formats {
+			if fields[len(fields)-1] == candidate {
+				format = candidate
+				fields = fields[:len(fields)-1]
+				break
+			}
+		}
+	}
+
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, today.Location())
+	monthStart := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, today.Location())
+	weekStart := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+	switch strings.Join(fields, " ") {
+	case "", "bulan ini":
+		return monthStart, monthStart.AddDate(0, 1, -1), format, true
+	case "bulan lalu":
+		start := monthStart.AddDate(0, -1, 0)
+		return start, monthStart.AddDate(0, 0, -1), format, true
+	case "minggu ini":
+		return weekStart, weekStart.AddDate(0, 0, 6), format, true
+	case "minggu lalu":
+		return weekStart.AddDate(0, 0, -7), weekStart.AddDate(0, 0, -1), format, true
+	}
+
+	if len(fields) != 2 {
+		return time.Time{}, time.Time{}, "", false
+	}
+	start, err := helpers.ParseDateWithTimezone(fields[0])
+	if err != nil {
+		return time.Time{}, time.Time{}, "", false
+	}
+	end, err := helpers.ParseDateWithTimezone(fields[1])
+	if err != nil || end.Before(start) {
+		return time.Time{}, time.Time{}, "", false
+	}
+	return start, end, format, true
+}
+
+func (b *Bot) cmdExport(c *fiber.Ctx, cmd *CommandContext) error {
+	startDate, endDate, format, ok := exportPeriod(cmd.Args, helpers.NowWithTimezone())
+	if !ok {
+		return b.reply(c, cmd.ChatID(), exportUsage)
+	}
+
+	var file payloads.ExportFile
+	response, err := callService(c, func() error {
+		built, ok, err := b.OvertimeService.BuildTimesheet(cmd.TelegramUser.TelegramID, startDate, endDate, format, c, database.ClientPostgres)
+		if !ok || err != nil {
+			return err
+		}
+		file = built
+		return helpers.Response(c, fiber.StatusOK, "Timesheet built successfully", nil)
+	})
+	if err != nil {
+		return err
+	}
+	if !response.OK() {
+		return b.reply(c, cmd.ChatID(), html.EscapeString(response.Message))
+	}
+
+	period := startDate.Format("2006-01-02") + " s/d " + endDate.Format("2006-01-02")
+	if file.Records == 0 {
+		return b.reply(c, cmd.ChatID(), fmt.Sprintf("Tidak ada catatan lembur pada %s.", period))
+	}
+	_, err = b.Client.SendDocument(c.Context(), telegram.SendDocumentParams{
+		ChatID:    cmd.ChatID(),
+		FileName:  file.FileName,
+		Data:      file.Data,
+		Caption:   fmt.Sprintf("📄 Timesheet lembur %s (%d catatan)", period, file.Records),
+		ParseMode: telegram.ParseModeHTML,
+	})
+	return err
+}
@@ -0,0 +1,295 @@
+package bot
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/database"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/telegram"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/scheduler"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// fakeDB is a database/sql driver that answers statements from canned results and records every one of them,
+// enough to run the repositories through GORM without a Postgres server
+type fakeDB struct {
+	mu         sync.Mutex
+	results    []fakeResult
+	statements []fakeStatement
+}
+
+// fakeResult answers the first statement containing match; exec statements affect one row unless set
+type fakeResult struct {
+	match    string
+	columns  []string
+	rows     [][]driver.Value
+	affected int64
+	err      error
+}
+
+type fakeStatement struct {
+	Query string
+	Args  []driver.Value
+}
+
+// newFakeDB points database.ClientPostgres at a fake database for the duration of the test
+func newFakeDB(t *testing.T) *fakeDB {
+	t.Helper()
+	fake := &fakeDB{}
+	sqlDB := sql.OpenDB(fakeConnector{db: fake})
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("open fake db: %v", err)
+	}
+
+	previous := database.ClientPostgres
+	database.ClientPostgres = db
+	t.Cleanup(func() {
+		database.ClientPostgres = previous
+		_ = sqlDB.Close()
+	})
+	return fake
+}
+
+// On answers statements containing match with rows
+func (f *fakeDB) On(match string, columns []string, rows ...[]driver.Value) {
+	f.add(fakeResult{match: match, columns: columns, rows: rows})
+}
+
+// OnExec sets the affected row count of statements containing match
+func (f *fakeDB) OnExec(match string, affected int64) {
+	f.add(fakeResult{match: match, affected: affected})
+}
+
+// Fail answers statements containing match with err
+func (f *fakeDB) Fail(match string, err error) {
+	f.add(fakeResult{match: match, err: err})
+}
+
+func (f *fakeDB) add(result fakeResult) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.results = append(f.results, result)
+}
+
+// Statements returns the recorded statements containing match
+func (f *fakeDB) Statements(match string) []fakeStatement {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var statements []fakeStatement
+	for _, statement := range f.statements {
+		if strings.Contains(statement.Query, match) {
+			statements = append(statements, statement)
+		}
+	}
+	return statements
+}
+
+func (f *fakeDB) answer(query string, args []driver.NamedValue) fakeResult {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	f.statements = append(f.statements, fakeStatement{Query: query, Args: values})
+	for _, result := range f.results {
+		if strings.Contains(query, result.match) {
+			return result
+		}
+	}
+	return fakeResult{affected: 1}
+}
+
+type fakeConnector struct {
+	db *fakeDB
+}
+
+func (c fakeConnector) Connect(context.Context) (driver.Conn, error) {
+	return fakeConn{db: c.db}, nil
+}
+
+func (c fakeConnector) Driver() driver.Driver {
+	return fakeDriver{db: c.db}
+}
+
+type fakeDriver struct {
+	db *fakeDB
+}
+
+func (d fakeDriver) Open(string) (driver.Conn, error) {
+	return fakeConn{db: d.db}, nil
+}
+
+type fakeConn struct {
+	db *fakeDB
+}
+
+func (c fakeConn) Prepare(string) (driver.Stmt, error) {
+	return nil, driver.ErrSkip
+}
+
+func (c fakeConn) Close() error {
+	return nil
+}
+
+func (c fakeConn) Begin() (driver.Tx, error) {
+	return fakeTx{}, nil
+}
+
+func (c fakeConn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	return fakeTx{}, nil
+}
+
+// CheckNamedValue passes every argument through unchanged
+func (c fakeConn) CheckNamedValue(*driver.NamedValue) error {
+	return nil
+}
+
+func (c fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	result := c.db.answer(query, args)
+	if result.err != nil {
+		return nil, result.err
+	}
+	return &fakeRows{columns: result.columns, rows: result.rows}, nil
+}
+
+func (c fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	result := c.db.answer(query, args)
+	if result.err != nil {
+		return nil, result.err
+	}
+	return driver.RowsAffected(result.affected), nil
+}
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error {
+	return nil
+}
+
+func (fakeTx) Rollback() error {
+	return nil
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+	next    int
+}
+
+func (r *fakeRows) Columns() []string {
+	return r.columns
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.next >= len(r.rows) {
+		return io.EOF
+	}
+	copy(dest, r.rows[r.next])
+	r.next++
+	return nil
+}
+
+// fakeBotAPI is a minimal Bot API server, handler answers one method call with its decoded parameters
+type fakeBotAPI struct {
+	mu    sync.Mutex
+	calls []botAPICall
+}
+
+type botAPICall struct {
+	Method string
+	Params map[string]interface{}
+}
+
+func newFakeBotAPI(t *testing.T, handler func(method string, params map[string]interface{}, call int) (int, string)) (*fakeBotAPI, *telegram.Client) {
+	t.Helper()
+	fake := &fakeBotAPI{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/bot123:secret/") {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `{"ok":false,"error_code":404,"description":"Not Found"}`)
+			return
+		}
+		method := strings.TrimPrefix(r.URL.Path, "/bot123:secret/")
+		params := map[string]interface{}{}
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil && err != io.EOF {
+			t.Errorf("decode %s body: %v", method, err)
+		}
+		fake.mu.Lock()
+		fake.calls = append(fake.calls, botAPICall{Method: method, Params: params})
+		call := len(fake.calls)
+		fake.mu.Unlock()
+
+		status, body := handler(method, params, call)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = io.WriteString(w, body)
+	}))
+	t.Cleanup(server.Close)
+
+	t.Setenv("TELEGRAM_API_BASE_URL", server.URL+"/")
+	t.Setenv("TELEGRAM_BOT_TOKEN", "123:secret")
+	client := telegram.NewClient()
+	client.MaxRetries = 0
+	client.RetryBackoff = time.Millisecond
+	return fake, client
+}
+
+// Calls returns the recorded calls of method, or every call when method is empty
+func (f *fakeBotAPI) Calls(method string) []botAPICall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var calls []botAPICall
+	for _, call := range f.calls {
+		if method == "" || call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// sentMessage answers sendMessage and the other methods that return a Message
+func sentMessage(string, map[string]interface{}, int) (int, string) {
+	return http.StatusOK, `{"ok":true,"result":{"message_id":1,"chat":{"id":1,"type":"private"}}}`
+}
+
+// newTestBot wires a Bot to a fake Bot API server and a fresh dispatcher
+func newTestBot(t *testing.T, handler func(method string, params map[string]interface{}, call int) (int, string)) (*Bot, *fakeBotAPI) {
+	t.Helper()
+	fake, client := newFakeBotAPI(t, handler)
+	b := New(client)
+	b.Register(NewDispatcher())
+	return b, fake
+}
+
+// findJob returns the job called name from the bot jobs
+func findJob(t *testing.T, b *Bot, name string) scheduler.Job {
+	t.Helper()
+	jobs, err := b.Jobs()
+	if err != nil {
+		t.Fatalf("Jobs() error = %v", err)
+	}
+	for _, job := range jobs {
+		if job.Name == name {
+			return job
+		}
+	}
+	t.Fatalf("job %q not scheduled", name)
+	return scheduler.Job{}
+}
@@ -0,0 +1,82 @@
+package bot
+
+import (
+	"encoding/json"
+	"html"
+	"strings"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/payloads"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/database"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/helpers"
+	"github.com/gofiber/fiber/v2"
+)
+
+const kalenderUsage = "Format: <code>/kalender</code>, <code>/kalender baru</code> atau <code>/kalender hapus</code>"
+
+func (b *Bot) cmdKalender(c *fiber.Ctx, cmd *CommandContext) error {
+	telegramID := cmd.TelegramUser.TelegramID
+	switch strings.ToLower(strings.TrimSpace(cmd.Args)) {
+	case "":
+		response, err := callService(c, func() error {
+			return b.OvertimeFeedService.GetFeedToken(telegramID, c, database.ClientPostgres)
+		})
+		if err != nil {
+			return err
+		}
+		if !response.OK() {
+			return b.reply(c, cmd.ChatID(), html.EscapeString(response.Message))
+		}
+		var status payloads.OvertimeFeedStatusResponse
+		if err := json.Unmarshal(response.Data, &status); err != nil {
+			return err
+		}
+		if !status.Active {
+			return b.reply(c, cmd.ChatID(), "📅 Feed kalender belum aktif. Buat dengan <code>/kalender baru</code> lalu subscribe URL-nya di Google Calendar, Outlook atau Apple Calendar.")
+		}
+		text := "📅 Feed kalender aktif sejak " + status.RotatedAt.In(helpers.GetTimezone()).Format("02/01/2006 15:04")
+		if status.LastUsedAt != nil {
+			text += "\nTerakhir diambil: " + status.LastUsedAt.In(helpers.GetTimezone()).Format("02/01/2006 15:04")
+		}
+		return b.reply(c, cmd.ChatID(), text+"\n\nURL hanya ditampilkan sekali, buat ulang dengan <code>/kalender baru</code> atau cabut dengan <code>/kalender hapus</code>.")
+
+	case "baru":
+		// update bot tidak punya host request, URL feed hanya bisa dibangun dari APP_PUBLIC_URL
+		if helpers.GetEnv("APP_PUBLIC_URL", "") == "" {
+			return b.reply(c, cmd.ChatID(), "Feed kalender belum bisa dibuat lewat bot, APP_PUBLIC_URL belum diatur.")
+		}
+		tx := database.ClientPostgres.Begin()
+		defer tx.Rollback()
+
+		response, err := callService(c, func() error {
+			return b.OvertimeFeedService.RotateFeedToken(telegramID, c, tx)
+		})
+		if err != nil {
+			return err
+		}
+		if !response.OK() {
+			return b.reply(c, cmd.ChatID(), "Gagal membuat feed: "+html.EscapeString(response.Message))
+		}
+		var token payloads.OvertimeFeedTokenResponse
+		if err := json.Unmarshal(response.Data, &token); err != nil {
+			return err
+		}
+		return b.reply(c, cmd.ChatID(), "📅 Subscribe URL berikut di aplikasi kalender:\n<code>"+html.EscapeString(token.URL)+"</code>\n\n"+
+			"Jangan bagikan URL ini, siapa pun yang memilikinya bisa melihat catatan lembur Anda. URL lama (jika ada) sudah tidak berlaku.")
+
+	case "hapus":
+		tx := database.ClientPostgres.Begin()
+		defer tx.Rollback()
+
+		response, err := callService(c, func() error {
+			return b.OvertimeFeedService.RevokeFeedToken(telegramID, c, tx)
+		})
+		if err != nil {
+			return err
+		}
+		if !response.OK() {
+			return b.reply(c, cmd.ChatID(), html.EscapeString(response.Message))
+		}
+		return b.reply(c, cmd.ChatID(), "🗑️ Feed kalender dicabut, aplikasi kalender tidak bisa mengambilnya lagi.")
+	}
+	return b.reply(c, cmd.ChatID(), kalenderUsage)
+}
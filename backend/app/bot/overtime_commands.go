@@ -0,0 +1,500 @@
+package bot
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/entities"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/payloads"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/database"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/helpers"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/limits"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/parser"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/telegram"
+	"github.com/gofiber/fiber/v2"
+)
+
+const lemburUsage = "Contoh: <code>lembur kemarin 18:00-21:30 istirahat 30m deploy server #infra</code>\n" +
+	"Tanggal: <code>kemarin</code>, <code>senin</code>, <code>12/03</code> • Istirahat: <code>30m</code>, <code>1j</code>, <code>1.5h</code>, <code>1 jam 30 menit</code>"
+
+// recapMaxRecords is how many records /rekap lists, the rest is left to /export
+const recapMaxRecords = 20
+
+func (b *Bot) registerCommands() {
+	b.registerCommand(
+		Command{
+			Name:        "start",
+			Description: "Mulai menggunakan bot",
+			Handler:     b.cmdStart,
+		},
+		Command{
+			Name:          "lembur",
+			Usage:         "[tanggal] HH:MM-HH:MM [istirahat 30m] [deskripsi] [#kategori]",
+			Description:   "Catat lembur baru, tanggal default hari ini. Bisa juga tanpa garis miring: lembur 18:00-21:30 ... Tanpa argumen akan dipandu langkah demi langkah",
+			RequireLinked: true,
+			Handler:       b.cmdLembur,
+		},
+		Command{
+			Name:          "catat",
+			Description:   "Catat lembur dengan panduan langkah demi langkah",
+			RequireLinked: true,
+			Handler:       b.startOvertimeWizard,
+		},
+		Command{
+			Name:          "hariini",
+			Description:   "Lihat lembur hari ini",
+			RequireLinked: true,
+			Handler:       b.cmdHariIni,
+		},
+		Command{
+			Name:          "rekap",
+			Usage:         "[YYYY-MM-DD YYYY-MM-DD]",
+			Description:   "Rekap lembur pada rentang tanggal, default bulan ini",
+			RequireLinked: true,
+			Handler:       b.cmdRekap,
+		},
+		Command{
+			Name:          "export",
+			Usage:         "[bulan ini|bulan lalu|minggu ini|minggu lalu|YYYY-MM-DD YYYY-MM-DD] [pdf|xlsx|csv]",
+			Description:   "Kirim timesheet lembur sebagai file, default bulan ini dalam PDF",
+			RequireLinked: true,
+			Handler:       b.cmdExport,
+		},
+		Command{
+			Name:          "lampiran",
+			Usage:         "ID",
+			Description:   "Lihat lampiran bukti lembur, kirim foto/dokumen dengan caption #ID untuk menambah",
+			RequireLinked: true,
+			Handler:       b.cmdLampiran,
+		},
+		Command{
+			Name:          "kalender",
+			Usage:         "[baru|hapus]",
+			Description:   "Feed kalender lembur untuk Google Calendar, Outlook atau Apple Calendar",
+			RequireLinked: true,
+			Handler:       b.cmdKalender,
+		},
+		Command{
+			Name:          "hapus",
+			Usage:         "ID",
+			Description:   "Hapus catatan lembur berdasarkan ID",
+			RequireLinked: true,
+			Handler:       b.cmdHapus,
+		},
+		Command{
+			Name:          "pulihkan",
+			Usage:         "ID",
+			Description:   "Pulihkan catatan lembur yang terhapus",
+			RequireLinked: true,
+			Handler:       b.cmdPulihkan,
+		},
+		Command{
+			Name:          "pengingat",
+			Usage:         "[on|off|tenang HH:MM-HH:MM|tenang off]",
+			Description:   "Atur pengingat harian jika belum mencatat lembur",
+			RequireLinked: true,
+			Handler:       b.cmdPengingat,
+		},
+		Command{
+			Name:          "rekapotomatis",
+			Usage:         "[on|off]",
+			Description:   "Atur rekap lembur mingguan dan bulanan otomatis",
+			RequireLinked: true,
+			Handler:       b.cmdRekapOtomatis,
+		},
+		Command{
+			Name:        "help",
+			Description: "Tampilkan daftar perintah",
+			Handler:     b.cmdHelp,
+		},
+	)
+}
+
+// overtimeView is the JSON shape of entities.Overtime as returned by OvertimeService
+type overtimeView struct {
+	ID            uint    `json:"id"`
+	Date          string  `json:"date"`
+	TimeStart     string  `json:"time_start"`
+	TimeStop      string  `json:"time_stop"`
+	BreakDuration float64 `json:"break_duration"`
+	Duration      float64 `json:"duration"`
+	Description   string  `json:"description"`
+	Category      string  `json:"category"`
+	Status        string  `json:"status"`
+	// Warnings diisi saat lembur melewati batas harian, mingguan atau bulanan
+	Warnings []limits.Violation `json:"warnings"`
+}
+
+var limitPeriodNames = map[string]string{
+	limits.PeriodDay:   "hari ini",
+	limits.PeriodWeek:  "minggu ini",
+	limits.PeriodMonth: "bulan ini",
+}
+
+// formatLimitViolations lists the exceeded limits, one line per period
+func formatLimitViolations(violations []limits.Violation) string {
+	lines := make([]string, 0, len(violations))
+	for _, violation := range violations {
+		lines = append(lines, fmt.Sprintf("⚠️ Lembur %s %.2f jam, melewati batas %.2f jam (lebih %.2f jam)",
+			limitPeriodNames[violation.Period], violation.Hours, violation.LimitHours, violation.ExcessHours))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// clock returns HH:MM from "15:04:05" or an RFC3339 timestamp
+func clock(value string) string {
+	if i := strings.Index(value, "T"); i >= 0 {
+		value = value[i+1:]
+	}
+	if len(value) >= 5 {
+		return value[:5]
+	}
+	return value
+}
+
+func formatOvertime(o overtimeView) string {
+	date := o.Date
+	if len(date) >= 10 {
+		date = date[:10]
+	}
+	line := fmt.Sprintf("<b>#%d</b> %s %s–%s • %.2f jam", o.ID, date, clock(o.TimeStart), clock(o.TimeStop), o.Duration)
+	if o.BreakDuration > 0 {
+		line += fmt.Sprintf(" (istirahat %.2f jam)", o.BreakDuration)
+	}
+	if o.Category != "" {
+		line += " • " + html.EscapeString(o.Category)
+	}
+	if o.Description != "" {
+		line += "\n" + html.EscapeString(o.Description)
+	}
+	return line
+}
+
+func (b *Bot) cmdStart(c *fiber.Ctx, cmd *CommandContext) error {
+	name := html.EscapeString(cmd.Message.From.FirstName)
+	if cmd.Args != "" {
+		return b.linkAccount(c, cmd)
+	}
+	if cmd.TelegramUser == nil {
+		return b.reply(c, cmd.ChatID(), fmt.Sprintf("Halo %s! Akun Telegram kamu belum terhubung. Buka menu hubungkan Telegram di aplikasi lalu tekan link yang diberikan.\n\nKetik /help untuk daftar perintah.", name))
+	}
+	return b.reply(c, cmd.ChatID(), fmt.Sprintf("Halo %s! Akun kamu sudah terhubung.\n\nKetik /help untuk daftar perintah.", name))
+}
+
+// linkAccount handles "/start <code>" from t.me/<bot>?start=<code>, the account data comes from the verified sender
+func (b *Bot) linkAccount(c *fiber.Ctx, cmd *CommandContext) error {
+	if cmd.TelegramUser != nil {
+		return b.reply(c, cmd.ChatID(), "Akun Telegram kamu sudah terhubung.")
+	}
+	if cmd.Message.Chat.Type != "private" {
+		return b.reply(c, cmd.ChatID(), "Hubungkan akun lewat chat pribadi dengan bot.")
+	}
+	sender := cmd.Message.From
+	payload := payloads.LinkTelegramAccountPayload{
+		Code:       cmd.Args,
+		TelegramID: sender.ID,
+		Username:   sender.Username,
+		FirstName:  sender.FirstName,
+		LastName:   sender.LastName,
+	}
+
+	tx := database.ClientPostgres.Begin()
+	defer tx.Rollback()
+
+	response, err := callService(c, func() error {
+		return b.TelegramService.LinkWithCode(&payload, c, tx)
+	})
+	if err != nil {
+		return err
+	}
+	switch {
+	case response.OK():
+		helpers.LogTelegramUserRegistration(cmd.ChatID(), sender.ID, sender.Username, sender.FirstName, sender.LastName, nil)
+		return b.reply(c, cmd.ChatID(), fmt.Sprintf("✅ Akun Telegram berhasil terhubung, %s!\n\nKetik /help untuk daftar perintah.", html.EscapeString(sender.FirstName)))
+	case response.Code == fiber.StatusGone:
+		return b.reply(c, cmd.ChatID(), "Kode sudah kedaluwarsa atau sudah dipakai. Minta link baru dari aplikasi.")
+	case response.Code == fiber.StatusNotFound:
+		return b.reply(c, cmd.ChatID(), "Kode tidak valid. Minta link baru dari aplikasi.")
+	default:
+		return b.reply(c, cmd.ChatID(), "Gagal menghubungkan akun: "+html.EscapeString(response.Message))
+	}
+}
+
+// cmdLembur parses free text like "18:00-21:30 istirahat 30m deploy server #infra", see parser.ParseOvertime
+func (b *Bot) cmdLembur(c *fiber.Ctx, cmd *CommandContext) error {
+	if strings.TrimSpace(cmd.Args) == "" {
+		return b.startOvertimeWizard(c, cmd)
+	}
+	payload, err := parser.ParseOvertime(cmd.Args, cmd.TelegramUser.TelegramID, helpers.NowWithTimezone())
+	if err != nil {
+		return b.reply(c, cmd.ChatID(), html.EscapeString(err.Error())+"\n\n"+lemburUsage)
+	}
+	_, err = b.createOvertime(c, cmd.ChatID(), &payload)
+	return err
+}
+
+// validationMessage flattens the messages of a helpers.Error from ValidateStruct
+func validationMessage(err error) string {
+	customErr, ok := err.(helpers.Error)
+	if !ok {
+		return html.EscapeString(err.Error())
+	}
+	messages, ok := customErr.Data.([]map[string]string)
+	if !ok || len(messages) == 0 {
+		return html.EscapeString(customErr.Message)
+	}
+	lines := make([]string, 0, len(messages))
+	for _, fieldMessages := range messages {
+		for _, message := range fieldMessages {
+			lines = append(lines, "• "+html.EscapeString(message))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// createOvertime runs the same path as POST /v1/overtime/ and replies with the result, created is false when the reply is an error
+func (b *Bot) createOvertime(c *fiber.Ctx, chatID int64, payload *payloads.CreateNewRecordOvertime) (bool, error) {
+	if err := helpers.ValidateStruct(payload); err != nil {
+		return false, b.reply(c, chatID, "Data lembur tidak valid:\n"+validationMessage(err))
+	}
+
+	tx := database.ClientPostgres.Begin()
+	defer tx.Rollback()
+
+	response, err := callService(c, func() error {
+		return b.OvertimeService.CreateNewRecordOvertime(payload, c, tx)
+	})
+	if err != nil {
+		return false, err
+	}
+	if response.Code == fiber.StatusConflict {
+		var conflict struct {
+			ConflictingIDs []uint `json:"conflicting_ids"`
+		}
+		if err := json.Unmarshal(response.Data, &conflict); err == nil && len(conflict.ConflictingIDs) > 0 {
+			ids := make([]string, 0, len(conflict.ConflictingIDs))
+			for _, id := range conflict.ConflictingIDs {
+				ids = append(ids, fmt.Sprintf("#%d", id))
+			}
+			return false, b.reply(c, chatID, "Gagal mencatat lembur: jam lembur bentrok dengan catatan "+strings.Join(ids, ", ")+". Lihat dengan /hariini atau hapus dengan /hapus ID.")
+		}
+	}
+	if response.Code == fiber.StatusUnprocessableEntity {
+		var blocked struct {
+			LimitViolations []limits.Violation `json:"limit_violations"`
+		}
+		if err := json.Unmarshal(response.Data, &blocked); err == nil && len(blocked.LimitViolations) > 0 {
+			return false, b.reply(c, chatID, "Gagal mencatat lembur: melewati batas lembur.\n"+formatLimitViolations(blocked.LimitViolations))
+		}
+	}
+	if !response.OK() {
+		return false, b.reply(c, chatID, "Gagal mencatat lembur: "+html.EscapeString(response.Message))
+	}
+
+	var overtime overtimeView
+	if err := json.Unmarshal(response.Data, &overtime); err != nil {
+		return true, err
+	}
+	text := "✅ Lembur tercatat\n\n" + formatOvertime(overtime)
+	if len(overtime.Warnings) > 0 {
+		text += "\n\n" + formatLimitViolations(overtime.Warnings)
+		if overtime.Status == entities.OvertimeStatusSubmitted {
+			text += "\nCatatan ini otomatis diajukan dan menunggu persetujuan approver."
+		}
+	}
+	return true, b.reply(c, chatID, text)
+}
+
+func (b *Bot) cmdHariIni(c *fiber.Ctx, cmd *CommandContext) error {
+	today := helpers.NowWithTimezone()
+	response, err := callService(c, func() error {
+		return b.OvertimeService.GetRecordByDateByTelegramID(cmd.TelegramUser.TelegramID, today, c, database.ClientPostgres)
+	})
+	if err != nil {
+		return err
+	}
+	if !response.OK() {
+		return b.reply(c, cmd.ChatID(), html.EscapeString(response.Message))
+	}
+
+	var overtimes []overtimeView
+	if err := json.Unmarshal(response.Data, &overtimes); err != nil {
+		return err
+	}
+	if len(overtimes) == 0 {
+		return b.reply(c, cmd.ChatID(), "Belum ada lembur hari ini.")
+	}
+
+	var total float64
+	lines := make([]string, 0, len(overtimes))
+	for _, overtime := range overtimes {
+		total += overtime.Duration
+		lines = append(lines, formatOvertime(overtime))
+	}
+	text := fmt.Sprintf("<b>Lembur %s</b>\n\n%s\n\nTotal: <b>%.2f jam</b>", today.Format("2006-01-02"), strings.Join(lines, "\n\n"), total)
+	return b.reply(c, cmd.ChatID(), text)
+}
+
+func (b *Bot) cmdRekap(c *fiber.Ctx, cmd *CommandContext) error {
+	now := helpers.NowWithTimezone()
+	startDate := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	endDate := now
+
+	fields := strings.Fields(cmd.Args)
+	if len(fields) > 0 {
+		if len(fields) != 2 {
+			return b.reply(c, cmd.ChatID(), "Format: <code>/rekap YYYY-MM-DD YYYY-MM-DD</code>")
+		}
+		var err error
+		if startDate, err = helpers.ParseDateWithTimezone(fields[0]); err != nil {
+			return b.reply(c, cmd.ChatID(), "Tanggal awal tidak valid. Gunakan YYYY-MM-DD")
+		}
+		if endDate, err = helpers.ParseDateWithTimezone(fields[1]); err != nil {
+			return b.reply(c, cmd.ChatID(), "Tanggal akhir tidak valid. Gunakan YYYY-MM-DD")
+		}
+		if endDate.Before(startDate) {
+			return b.reply(c, cmd.ChatID(), "Tanggal akhir harus setelah tanggal awal")
+		}
+	}
+
+	response, err := callService(c, func() error {
+		return b.OvertimeService.GetRecordBetweenDateByTelegramId(cmd.TelegramUser.TelegramID, startDate, endDate, c, database.ClientPostgres)
+	})
+	if err != nil {
+		return err
+	}
+	if !response.OK() {
+		return b.reply(c, cmd.ChatID(), html.EscapeString(response.Message))
+	}
+
+	var recap struct {
+		Records       []overtimeView `json:"records"`
+		TotalDuration float64        `json:"total_duration"`
+		RecordsCount  int            `json:"records_count"`
+	}
+	if err := json.Unmarshal(response.Data, &recap); err != nil {
+		return err
+	}
+
+	header := fmt.Sprintf("<b>Rekap lembur %s s/d %s</b>", startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
+	if recap.RecordsCount == 0 {
+		return b.reply(c, cmd.ChatID(), header+"\n\nTidak ada catatan lembur.")
+	}
+	footer := fmt.Sprintf("Jumlah catatan: <b>%d</b>\nTotal: <b>%.2f jam</b>", recap.RecordsCount, recap.TotalDuration)
+	return b.reply(c, cmd.ChatID(), formatRecapRecords(header, recap.Records, footer, startDate, endDate))
+}
+
+// formatRecapRecords lists the newest records (the service returns them newest first) that fit in one Telegram message,
+// the totals always cover the whole range and /export is suggested for the rest
+func formatRecapRecords(header string, records []overtimeView, footer string, startDate time.Time, endDate time.Time) string {
+	more := fmt.Sprintf("\n\n<i>Menampilkan %%d dari %d catatan terbaru.</i> Semua catatan: <code>/export %s %s</code>",
+		len(records), startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
+	// the limit counts characters after HTML parsing, bytes with tags are a safe upper bound
+	budget := telegram.MaxMessageLength - len(header) - len(footer) - len(more) - 16
+	lines := make([]string, 0, min(len(records), recapMaxRecords))
+	for _, overtime := range records {
+		line := formatOvertime(overtime)
+		if len(lines) == recapMaxRecords || budget-len(line)-2 < 0 {
+			break
+		}
+		budget -= len(line) + 2
+		lines = append(lines, line)
+	}
+
+	text := header + "\n\n"
+	if len(lines) > 0 {
+		text += strings.Join(lines, "\n\n") + "\n\n"
+	}
+	text += footer
+	if len(lines) < len(records) {
+		text += fmt.Sprintf(more, len(lines))
+	}
+	return text
+}
+
+func (b *Bot) cmdHapus(c *fiber.Ctx, cmd *CommandContext) error {
+	id, err := strconv.ParseUint(strings.TrimPrefix(strings.TrimSpace(cmd.Args), "#"), 10, 32)
+	if err != nil || id == 0 {
+		return b.reply(c, cmd.ChatID(), "Format: <code>/hapus ID</code>")
+	}
+
+	// hanya pemilik catatan yang boleh menghapus lewat bot
+	var overtime entities.Overtime
+	if err := b.OvertimeRepository.GetRecordByID(uint(id), &overtime, c, database.ClientPostgres); err != nil {
+		if helpers.IsNotFoundError(err) {
+			return b.reply(c, cmd.ChatID(), "Catatan lembur tidak ditemukan.")
+		}
+		return err
+	}
+	if overtime.TelegramUserID != cmd.TelegramUser.ID {
+		return b.reply(c, cmd.ChatID(), "Catatan lembur tidak ditemukan.")
+	}
+
+	tx := database.ClientPostgres.Begin()
+	defer tx.Rollback()
+
+	response, err := callService(c, func() error {
+		return b.OvertimeService.DeleteRecordOvertime(uint(id), c, tx)
+	})
+	if err != nil {
+		return err
+	}
+	if !response.OK() {
+		return b.reply(c, cmd.ChatID(), "Gagal menghapus: "+html.EscapeString(response.Message))
+	}
+	text := fmt.Sprintf("🗑️ Catatan lembur #%d dihapus. Salah hapus? Pulihkan dengan <code>/pulihkan %d</code>", id, id)
+	var info payloads.OvertimeTrashInfo
+	if err := json.Unmarshal(response.Data, &info); err == nil && info.PurgeAt != nil {
+		text += fmt.Sprintf(" sebelum %s.", info.PurgeAt.In(helpers.GetTimezone()).Format("02/01/2006"))
+	}
+	return b.reply(c, cmd.ChatID(), text)
+}
+
+func (b *Bot) cmdPulihkan(c *fiber.Ctx, cmd *CommandContext) error {
+	id, err := strconv.ParseUint(strings.TrimPrefix(strings.TrimSpace(cmd.Args), "#"), 10, 32)
+	if err != nil || id == 0 {
+		return b.reply(c, cmd.ChatID(), "Format: <code>/pulihkan ID</code>")
+	}
+
+	tx := database.ClientPostgres.Begin()
+	defer tx.Rollback()
+
+	// hanya pemilik catatan yang boleh memulihkan lewat bot
+	var overtime entities.Overtime
+	if err := b.OvertimeRepository.GetTrashedRecordByIDForUpdate(uint(id), &overtime, c, tx); err != nil {
+		if helpers.IsNotFoundError(err) {
+			return b.reply(c, cmd.ChatID(), "Catatan lembur tidak ada di tempat sampah.")
+		}
+		return err
+	}
+	if overtime.TelegramUserID != cmd.TelegramUser.ID {
+		return b.reply(c, cmd.ChatID(), "Catatan lembur tidak ada di tempat sampah.")
+	}
+
+	response, err := callService(c, func() error {
+		return b.OvertimeService.RestoreRecordOvertime(uint(id), c, tx)
+	})
+	if err != nil {
+		return err
+	}
+	if !response.OK() {
+		if response.Code == fiber.StatusConflict {
+			return b.reply(c, cmd.ChatID(), "Gagal memulihkan: jam lembur bentrok dengan catatan lain. Lihat dengan /hariini atau hapus dengan /hapus ID.")
+		}
+		return b.reply(c, cmd.ChatID(), "Gagal memulihkan: "+html.EscapeString(response.Message))
+	}
+
+	var restored overtimeView
+	if err := json.Unmarshal(response.Data, &restored); err != nil {
+		return b.reply(c, cmd.ChatID(), fmt.Sprintf("♻️ Catatan lembur #%d dipulihkan.", id))
+	}
+	text := "♻️ Catatan lembur dipulihkan.\n\n" + formatOvertime(restored)
+	if len(restored.Warnings) > 0 {
+		text += "\n\n" + formatLimitViolations(restored.Warnings)
+	}
+	return b.reply(c, cmd.ChatID(), text)
+}
@@ -0,0 +1,133 @@
+package bot
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/entities"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/database"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/helpers"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/telegram"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/repositories"
+)
+
+// Poller runs a getUpdates long-polling loop as an alternative to the webhook.
+// The next offset is persisted in Postgres after every update.
+type Poller struct {
+	Client                         *telegram.Client
+	Dispatcher                     *Dispatcher
+	TelegramPollingStateRepository repositories.TelegramPollingStateRepository
+
+	// Timeout is the long-poll timeout in seconds
+	Timeout int
+}
+
+func NewPoller(client *telegram.Client, dispatcher *Dispatcher) *Poller {
+	timeout, err := strconv.Atoi(helpers.GetEnv("TELEGRAM_POLLING_TIMEOUT", "30"))
+	if err != nil || timeout < 0 {
+		timeout = 30
+	}
+	return &Poller{
+		Client:     client,
+		Dispatcher: dispatcher,
+		Timeout:    timeout,
+	}
+}
+
+// Run polls until ctx is cancelled
+func (p *Poller) Run(ctx context.Context) {
+	botID := p.Client.BotID()
+	offset := p.loadOffset(botID)
+	helpers.LogTelegramBotAction("polling_started", 0, botID, true, map[string]interface{}{
+		"offset":   offset,
+		"base_url": p.Client.BaseURL,
+		"timeout":  p.Timeout,
+	})
+
+	backoff := time.Second
+	for {
+		if ctx.Err() != nil {
+			helpers.LogTelegramBotAction("polling_stopped", 0, botID, true, nil)
+			return
+		}
+
+		updates, err := p.Client.GetUpdates(ctx, telegram.GetUpdatesParams{
+			Offset:  offset,
+			Timeout: p.Timeout,
+			AllowedUpdates: []string{
+				telegram.UpdateTypeMessage,
+				telegram.UpdateTypeEditedMessage,
+				telegram.UpdateTypeCallbackQuery,
+				telegram.UpdateTypeInlineQuery,
+				telegram.UpdateTypeMyChatMember,
+			},
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				continue
+			}
+			// 409 artinya webhook masih aktif, getUpdates tidak bisa dipakai bersamaan dengan webhook
+			helpers.LogTelegramError(err, "polling_get_updates", 0, botID, map[string]interface{}{
+				"offset":  offset,
+				"backoff": backoff.String(),
+			})
+			wait := backoff
+			if apiErr, ok := err.(*telegram.APIError); ok && apiErr.RetryAfter > 0 {
+				wait = time.Duration(apiErr.RetryAfter) * time.Second
+			}
+			select {
+			case <-ctx.Done():
+			case <-time.After(wait):
+			}
+			if backoff < time.Minute {
+				backoff *= 2
+			}
+			continue
+		}
+		backoff = time.Second
+
+		for i := range updates {
+			update := updates[i]
+			if err := p.Dispatcher.Dispatch(&update); err != nil {
+				// jangan majukan offset supaya update diambil lagi di iterasi berikutnya
+				helpers.LogTelegramError(err, "polling_dispatch", update.ChatID(), botID, map[string]interface{}{
+					"update_id": update.UpdateID,
+				})
+				select {
+				case <-ctx.Done():
+				case <-time.After(backoff):
+				}
+				break
+			}
+			offset = update.UpdateID + 1
+			p.saveOffset(botID, offset)
+		}
+	}
+}
+
+func (p *Poller) loadOffset(botID int64) int64 {
+	c, release := p.Dispatcher.NewContext()
+	defer release()
+
+	var state entities.TelegramPollingState
+	if err := p.TelegramPollingStateRepository.FindByBotID(botID, &state, c, database.ClientPostgres); err != nil {
+		if !helpers.IsNotFoundError(err) {
+			helpers.LogTelegramError(err, "polling_load_offset", 0, botID, nil)
+		}
+		return 0
+	}
+	return state.Offset
+}
+
+func (p *Poller) saveOffset(botID int64, offset int64) {
+	c, release := p.Dispatcher.NewContext()
+	defer release()
+
+	state := entities.TelegramPollingState{BotID: botID, Offset: offset}
+	if err := p.TelegramPollingStateRepository.Save(&state, c, database.ClientPostgres); err != nil {
+		helpers.LogTelegramError(err, "polling_save_offset", 0, botID, map[string]interface{}{
+			"offset": offset,
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package bot
+
+import (
+	"context"
+	"database/sql/driver"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestPollerLoadsAndSavesOffset(t *testing.T) {
+	tests := []struct {
+		name       string
+		stored     []driver.Value
+		wantOffset float64
+		wantSave   string
+	}{
+		{name: "stored offset", stored: []driver.Value{int64(123), int64(41), time.Now()}, wantOffset: 41, wantSave: `UPDATE "telegram_polling_states"`},
+		{name: "first run", wantOffset: 0, wantSave: `INSERT INTO "telegram_polling_states"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newFakeDB(t)
+			if tt.stored != nil {
+				db.On(`FROM "telegram_polling_states"`, []string{"bot_id", "offset", "updated_at"}, tt.stored)
+			} else {
+				// tanpa baris, Save jatuh ke INSERT ... ON CONFLICT
+				db.OnExec(`UPDATE "telegram_polling_states"`, 0)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			fake, client := newFakeBotAPI(t, func(method string, params map[string]interface{}, call int) (int, string) {
+				if call > 1 {
+					cancel()
+					return http.StatusOK, `{"ok":true,"result":[]}`
+				}
+				return http.StatusOK, `{"ok":true,"result":[{"update_id":41,"message":{"message_id":1,"chat":{"id":42,"type":"private"},"text":"halo"}}]}`
+			})
+
+			poller := NewPoller(client, NewDispatcher())
+			poller.Timeout = 0
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				poller.Run(ctx)
+			}()
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				t.Fatal("Run did not stop after ctx was cancelled")
+			}
+
+			calls := fake.Calls("getUpdates")
+			if len(calls) < 2 {
+				t.Fatalf("getUpdates calls = %d, want at least 2", len(calls))
+			}
+			if got, _ := calls[0].Params["offset"].(float64); got != tt.wantOffset {
+				t.Errorf("first getUpdates offset = %v, want %v", got, tt.wantOffset)
+			}
+			if got, _ := calls[1].Params["offset"].(float64); got != 42 {
+				t.Errorf("second getUpdates offset = %v, want 42", got)
+			}
+
+			if len(db.Statements(`INSERT INTO "telegram_updates"`)) != 1 {
+				t.Errorf("update 41 was not dispatched")
+			}
+			saves := db.Statements(tt.wantSave)
+			if len(saves) != 1 {
+				t.Fatalf("polling state saves = %d, want 1", len(saves))
+			}
+			if !containsValue(saves[0].Args, int64(42)) || !containsValue(saves[0].Args, int64(123)) {
+				t.Errorf("saved polling state args = %v, want offset 42 for bot 123", saves[0].Args)
+			}
+		})
+	}
+}
+
+func containsValue(values []driver.Value, want driver.Value) bool {
+	for _, value := range values {
+		if value == want {
+			return true
+		}
+	}
+	return false
+}
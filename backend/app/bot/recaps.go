@@ -0,0 +1,250 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/entities"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/payloads"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/database"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/helpers"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/telegram"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/scheduler"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	recapWeekly  = "weekly"
+	recapMonthly = "monthly"
+)
+
+// recapJobs builds the weekly and monthly recap jobs from RECAP_* variables.
+// Defaults: weekly on Monday, monthly on the 1st, both at 08:00 local time.
+func (b *Bot) recapJobs() ([]scheduler.Job, error) {
+	hour, minute, err := scheduler.ParseClock(helpers.GetEnv("RECAP_TIME", "08:00"))
+	if err != nil {
+		return nil, fmt.Errorf("RECAP_TIME: %w", err)
+	}
+	var jobs []scheduler.Job
+	if helpers.GetEnv("RECAP_WEEKLY_ENABLED", "true") == "true" {
+		weekday, err := scheduler.ParseWeekday(helpers.GetEnv("RECAP_WEEKLY_DAY", "monday"))
+		if err != nil {
+			return nil, fmt.Errorf("RECAP_WEEKLY_DAY: %w", err)
+		}
+		jobs = append(jobs, scheduler.Job{
+			Name: "weekly_overtime_recap",
+			Next: scheduler.WeeklyAt(weekday, hour, minute),
+			Run:  func(ctx context.Context) error { return b.sendRecaps(ctx, recapWeekly) },
+		})
+	}
+	if helpers.GetEnv("RECAP_MONTHLY_ENABLED", "true") == "true" {
+		day, err := strconv.Atoi(helpers.GetEnv("RECAP_MONTHLY_DAY", "1"))
+		if err != nil || day < 1 || day > 28 {
+			return nil, fmt.Errorf("RECAP_MONTHLY_DAY: must be between 1 and 28")
+		}
+		jobs = append(jobs, scheduler.Job{
+			Name: "monthly_overtime_recap",
+			Next: scheduler.MonthlyAt(day, hour, minute),
+			Run:  func(ctx context.Context) error { return b.sendRecaps(ctx, recapMonthly) },
+		})
+	}
+	return jobs, nil
+}
+
+// recapPeriod returns the previous period for a recap sent on today: the 7 days before it, or the previous calendar month
+func recapPeriod(kind string, today time.Time) (time.Time, time.Time) {
+	if kind == recapMonthly {
+		start := time.Date(today.Year(), today.Month()-1, 1, 0, 0, 0, 0, today.Location())
+		return start, start.AddDate(0, 1, -1)
+	}
+	return today.AddDate(0, 0, -7), today.AddDate(0, 0, -1)
+}
+
+type recapCategory struct {
+	Name  string
+	Hours float64
+}
+
+type recapSummary struct {
+	TotalHours      float64
+	Records         int
+	Categories      []recapCategory
+	LongestDay      string
+	LongestDayHours float64
+	HolidayHours    float64 // lembur di hari libur nasional/cuti bersama
+	WeekendHours    float64 // lembur di akhir pekan yang bukan hari libur
+}
+
+// summarizeRecap reads the totals per category and per day of GET /v1/overtime/summary for one telegram user
+func summarizeRecap(byCategory payloads.OvertimeSummaryUser, byDay payloads.OvertimeSummaryUser) recapSummary {
+	summary := recapSummary{
+		TotalHours: byCategory.Totals.TotalDuration,
+		Records:    int(byCategory.Totals.RecordsCount),
+	}
+	for _, bucket := range byCategory.Buckets {
+		summary.Categories = append(summary.Categories, recapCategory{Name: bucket.Category, Hours: bucket.TotalDuration})
+	}
+	sort.Slice(summary.Categories, func(i, j int) bool {
+		if summary.Categories[i].Hours == summary.Categories[j].Hours {
+			return summary.Categories[i].Name < summary.Categories[j].Name
+		}
+		return summary.Categories[i].Hours > summary.Categories[j].Hours
+	})
+	for _, day := range byDay.Buckets {
+		if day.TotalDuration > summary.LongestDayHours || (day.TotalDuration == summary.LongestDayHours && day.StartDate < summary.LongestDay) {
+			summary.LongestDay, summary.LongestDayHours = day.StartDate, day.TotalDuration
+		}
+		switch {
+		case day.IsHoliday != nil && *day.IsHoliday:
+			summary.HolidayHours += day.TotalDuration
+		case day.IsWeekend != nil && *day.IsWeekend:
+			summary.WeekendHours += day.TotalDuration
+		}
+	}
+	return summary
+}
+
+// recapSummaryOf calls the summary service the same way as GET /v1/overtime/summary
+func (b *Bot) recapSummaryOf(c *fiber.Ctx, telegramID int64, start time.Time, end time.Time, groupBy string) (payloads.OvertimeSummaryUser, error) {
+	response, err := callService(c, func() error {
+		return b.OvertimeService.GetOvertimeSummary([]int64{telegramID}, start, end, groupBy, "", c, database.ClientPostgres)
+	})
+	if err != nil {
+		return payloads.OvertimeSummaryUser{}, err
+	}
+	if !response.OK() {
+		return payloads.OvertimeSummaryUser{}, errors.New(response.Message)
+	}
+	var summary payloads.OvertimeSummaryResponse
+	if err := json.Unmarshal(response.Data, &summary); err != nil {
+		return payloads.OvertimeSummaryUser{}, err
+	}
+	if len(summary.Users) == 0 {
+		return payloads.OvertimeSummaryUser{}, nil
+	}
+	return summary.Users[0], nil
+}
+
+func formatRecap(kind string, start time.Time, end time.Time, summary recapSummary) string {
+	title := "Rekap lembur mingguan"
+	if kind == recapMonthly {
+		title = "Rekap lembur bulanan"
+	}
+	header := fmt.Sprintf("<b>%s</b>\n%s s/d %s", title, start.Format("2006-01-02"), end.Format("2006-01-02"))
+	if summary.Records == 0 {
+		return header + "\n\nTidak ada catatan lembur pada periode ini."
+	}
+
+	lines := []string{
+		header,
+		"",
+		fmt.Sprintf("Total: <b>%.2f jam</b>", summary.TotalHours),
+		fmt.Sprintf("Jumlah catatan: <b>%d</b>", summary.Records),
+		fmt.Sprintf("Hari terpanjang: <b>%s</b> (%.2f jam)", summary.LongestDay, summary.LongestDayHours),
+	}
+	if summary.HolidayHours > 0 {
+		lines = append(lines, fmt.Sprintf("Di hari libur: <b>%.2f jam</b>", summary.HolidayHours))
+	}
+	if summary.WeekendHours > 0 {
+		lines = append(lines, fmt.Sprintf("Di akhir pekan: <b>%.2f jam</b>", summary.WeekendHours))
+	}
+	lines = append(lines, "", "<b>Per kategori</b>")
+	for _, category := range summary.Categories {
+		lines = append(lines, fmt.Sprintf("• %s: %.2f jam", html.EscapeString(category.Name), category.Hours))
+	}
+	lines = append(lines, "", "<i>Matikan rekap otomatis dengan /rekapotomatis off</i>")
+	return strings.Join(lines, "\n")
+}
+
+// sendRecaps sends the recap of the previous period to every linked user that has recaps on, at most once per day
+func (b *Bot) sendRecaps(ctx context.Context, kind string) error {
+	c, release := b.dispatcher.NewContext()
+	defer release()
+
+	lastSentColumn := "last_weekly_recap_at"
+	if kind == recapMonthly {
+		lastSentColumn = "last_monthly_recap_at"
+	}
+	now := helpers.NowWithTimezone()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	start, end := recapPeriod(kind, today)
+
+	var telegramUsers []entities.TelegramUser
+	if err := b.TelegramRepository.FindRecapCandidates(lastSentColumn, today, &telegramUsers, c, database.ClientPostgres); err != nil {
+		return err
+	}
+
+	sent, failed := 0, 0
+	for _, telegramUser := range telegramUsers {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		byCategory, err := b.recapSummaryOf(c, telegramUser.TelegramID, start, end, "category")
+		if err != nil {
+			return err
+		}
+		byDay, err := b.recapSummaryOf(c, telegramUser.TelegramID, start, end, "day")
+		if err != nil {
+			return err
+		}
+
+		_, err = b.Client.SendMessage(ctx, telegram.SendMessageParams{
+			ChatID:    telegramUser.TelegramID,
+			Text:      formatRecap(kind, start, end, summarizeRecap(byCategory, byDay)),
+			ParseMode: telegram.ParseModeHTML,
+		})
+		var apiErr *telegram.APIError
+		if err != nil && !(errors.As(err, &apiErr) && apiErr.Code == http.StatusForbidden) {
+			failed++
+			continue
+		}
+		if err := b.TelegramRepository.UpdateFields(telegramUser.ID, map[string]interface{}{lastSentColumn: now}, c, database.ClientPostgres); err != nil {
+			return err
+		}
+		sent++
+	}
+
+	helpers.LogTelegramBotAction(kind+"_recap", 0, 0, failed == 0, map[string]interface{}{
+		"candidates": len(telegramUsers),
+		"sent":       sent,
+		"failed":     failed,
+		"start_date": start.Format("2006-01-02"),
+		"end_date":   end.Format("2006-01-02"),
+	})
+	return nil
+}
+
+// cmdRekapOtomatis turns the automatic weekly and monthly recaps on or off
+func (b *Bot) cmdRekapOtomatis(c *fiber.Ctx, cmd *CommandContext) error {
+	var enabled bool
+	switch strings.ToLower(strings.TrimSpace(cmd.Args)) {
+	case "on", "aktif":
+		enabled = true
+	case "off", "nonaktif":
+		enabled = false
+	case "":
+		status := "nonaktif"
+		if cmd.TelegramUser.RecapEnabled {
+			status = "aktif"
+		}
+		return b.reply(c, cmd.ChatID(), fmt.Sprintf("Rekap otomatis mingguan dan bulanan: <b>%s</b>\n\nFormat: <code>/rekapotomatis on</code> atau <code>/rekapotomatis off</code>", status))
+	default:
+		return b.reply(c, cmd.ChatID(), "Format: <code>/rekapotomatis on</code> atau <code>/rekapotomatis off</code>")
+	}
+
+	if err := b.TelegramRepository.UpdateFields(cmd.TelegramUser.ID, map[string]interface{}{"recap_enabled": enabled}, c, database.ClientPostgres); err != nil {
+		return err
+	}
+	if enabled {
+		return b.reply(c, cmd.ChatID(), "✅ Rekap otomatis diaktifkan.")
+	}
+	return b.reply(c, cmd.ChatID(), "✅ Rekap otomatis dimatikan.")
+}
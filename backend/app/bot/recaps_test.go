@@ -0,0 +1,154 @@
+package bot
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/calendar"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/helpers"
+)
+
+func TestRecapPeriod(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name               string
+		kind               string
+		today              time.Time
+		wantStart, wantEnd string
+	}{
+		{name: "weekly", kind: recapWeekly, today: date(2026, 3, 9), wantStart: "2026-03-02", wantEnd: "2026-03-08"},
+		{name: "weekly across months", kind: recapWeekly, today: date(2026, 3, 2), wantStart: "2026-02-23", wantEnd: "2026-03-01"},
+		{name: "monthly", kind: recapMonthly, today: date(2026, 3, 1), wantStart: "2026-02-01", wantEnd: "2026-02-28"},
+		{name: "monthly in January", kind: recapMonthly, today: date(2026, 1, 15), wantStart: "2025-12-01", wantEnd: "2025-12-31"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := recapPeriod(tt.kind, tt.today)
+			if got := start.Format("2006-01-02"); got != tt.wantStart {
+				t.Errorf("start = %s, want %s", got, tt.wantStart)
+			}
+			if got := end.Format("2006-01-02"); got != tt.wantEnd {
+				t.Errorf("end = %s, want %s", got, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestWeeklyRecapJob(t *testing.T) {
+	t.Setenv("RECAP_WEEKLY_ENABLED", "true")
+	now := helpers.NowWithTimezone()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	start, end := recapPeriod(recapWeekly, today)
+
+	// satu hari kerja libur nasional, satu hari akhir pekan dan satu hari kerja biasa dari periode minggu lalu
+	var holiday, weekend, workday string
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		switch {
+		case calendar.IsWeekend(day):
+			if weekend == "" {
+				weekend = day.Format("2006-01-02")
+			}
+		case holiday == "":
+			holiday = day.Format("2006-01-02")
+		case workday == "":
+			workday = day.Format("2006-01-02")
+		}
+	}
+	holidayDate, _ := time.Parse("2006-01-02", holiday)
+
+	db := newFakeDB(t)
+	db.On(`recap_enabled = `, []string{"id", "user_id", "telegram_id", "first_name", "recap_enabled"},
+		[]driver.Value{int64(1), int64(11), int64(101), "Budi", true},
+		[]driver.Value{int64(2), int64(12), int64(102), "Sari", true},
+	)
+	db.On(`SELECT "telegram_id" FROM "telegram_users"`, []string{"telegram_id"},
+		[]driver.Value{int64(101)},
+		[]driver.Value{int64(102)},
+	)
+	db.On(`categories.name AS category_name`,
+		[]string{"all_users", "all_buckets", "telegram_id", "category_id", "category_name", "records_count", "days_count", "total_duration"},
+		[]driver.Value{true, true, nil, nil, nil, int64(3), int64(3), 6.5},
+		[]driver.Value{false, true, int64(101), nil, nil, int64(3), int64(3), 6.5},
+		[]driver.Value{false, false, int64(101), int64(1), "Proyek & Support", int64(2), int64(2), 4.5},
+		[]driver.Value{false, false, int64(101), nil, nil, int64(1), int64(1), 2.0},
+	)
+	db.On(`AS bucket_start`,
+		[]string{"all_users", "all_buckets", "telegram_id", "bucket_start", "records_count", "days_count", "total_duration"},
+		[]driver.Value{true, true, nil, nil, int64(3), int64(3), 6.5},
+		[]driver.Value{false, true, int64(101), nil, int64(3), int64(3), 6.5},
+		[]driver.Value{false, false, int64(101), holiday, int64(1), int64(1), 3.0},
+		[]driver.Value{false, false, int64(101), weekend, int64(1), int64(1), 2.0},
+		[]driver.Value{false, false, int64(101), workday, int64(1), int64(1), 1.5},
+	)
+	db.On(`FROM "holidays"`, []string{"id", "date", "name", "kind"},
+		[]driver.Value{int64(1), holidayDate, "Hari Libur", "national"},
+	)
+
+	b, fake := newTestBot(t, sentMessage)
+	job := findJob(t, b, "weekly_overtime_recap")
+	if err := job.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	messages := fake.Calls("sendMessage")
+	if len(messages) != 2 {
+		t.Fatalf("sendMessage calls = %d, want 2", len(messages))
+	}
+	if chat := messages[0].Params["chat_id"]; chat != float64(101) {
+		t.Errorf("first recap sent to %v, want 101", chat)
+	}
+	text, _ := messages[0].Params["text"].(string)
+	for _, want := range []string{
+		"<b>Rekap lembur mingguan</b>\n" + start.Format("2006-01-02") + " s/d " + end.Format("2006-01-02"),
+		"Total: <b>6.50 jam</b>",
+		"Jumlah catatan: <b>3</b>",
+		"Hari terpanjang: <b>" + holiday + "</b> (3.00 jam)",
+		"Di hari libur: <b>3.00 jam</b>",
+		"Di akhir pekan: <b>2.00 jam</b>",
+		"• Proyek &amp; Support: 4.50 jam\n• Tanpa kategori: 2.00 jam",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("recap text missing %q:\n%s", want, text)
+		}
+	}
+	if text, _ := messages[1].Params["text"].(string); !strings.Contains(text, "Tidak ada catatan lembur pada periode ini.") {
+		t.Errorf("recap of a user without records = %q", text)
+	}
+
+	updates := db.Statements(`UPDATE "telegram_users" SET "last_weekly_recap_at"`)
+	if len(updates) != 2 {
+		t.Fatalf("last_weekly_recap_at updates = %d, want 2", len(updates))
+	}
+	if !containsValue(updates[0].Args, uint(1)) || !containsValue(updates[1].Args, uint(2)) {
+		t.Errorf("last_weekly_recap_at updated for %v and %v, want ids 1 and 2", updates[0].Args, updates[1].Args)
+	}
+	if len(db.Statements(`"last_monthly_recap_at"`)) != 0 {
+		t.Error("weekly recap touched last_monthly_recap_at")
+	}
+}
+
+func TestRecapJobStopsOnSummaryError(t *testing.T) {
+	t.Setenv("RECAP_MONTHLY_ENABLED", "true")
+	db := newFakeDB(t)
+	db.On(`recap_enabled = `, []string{"id", "user_id", "telegram_id", "first_name", "recap_enabled"},
+		[]driver.Value{int64(1), int64(11), int64(101), "Budi", true},
+	)
+	// telegram_id tidak ditemukan, service menjawab 404
+
+	b, fake := newTestBot(t, sentMessage)
+	job := findJob(t, b, "monthly_overtime_recap")
+	if err := job.Run(context.Background()); err == nil {
+		t.Fatal("Run() error = nil, want the summary error")
+	}
+	if calls := fake.Calls("sendMessage"); len(calls) != 0 {
+		t.Errorf("sendMessage calls = %d, want 0", len(calls))
+	}
+	if len(db.Statements(`UPDATE "telegram_users"`)) != 0 {
+		t.Error("recap marked as sent after a summary error")
+	}
+}
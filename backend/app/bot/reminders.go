@@ -0,0 +1,190 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/entities"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/database"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/helpers"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/parser"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/telegram"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/scheduler"
+	"github.com/gofiber/fiber/v2"
+)
+
+// reminderRetryInterval is how often users skipped because of quiet hours are checked again the same day
+const reminderRetryInterval = 15 * time.Minute
+
+// Jobs returns the background jobs of the bot for the scheduler.
+// REMINDER_TIME (HH:MM, default 17:00) is interpreted in helpers.GetTimezone.
+func (b *Bot) Jobs() ([]scheduler.Job, error) {
+	var jobs []scheduler.Job
+	if helpers.GetEnv("REMINDER_ENABLED", "true") == "true" {
+		hour, minute, err := scheduler.ParseClock(helpers.GetEnv("REMINDER_TIME", "17:00"))
+		if err != nil {
+			return nil, fmt.Errorf("REMINDER_TIME: %w", err)
+		}
+		jobs = append(jobs, scheduler.Job{
+			Name: "daily_overtime_reminder",
+			Next: scheduler.DailyWindow(hour, minute, reminderRetryInterval),
+			Run:  b.sendReminders,
+		})
+	}
+
+	recapJobs, err := b.recapJobs()
+	if err != nil {
+		return nil, err
+	}
+	jobs = append(jobs, recapJobs...)
+
+	updatePurgeJobs, err := b.dispatcher.purgeJobs()
+	if err != nil {
+		return nil, err
+	}
+	return append(jobs, updatePurgeJobs...), nil
+}
+
+// inQuietHours reports whether now falls in the HH:MM window [start, end), windows may wrap past midnight
+func inQuietHours(now time.Time, start string, end string) bool {
+	if start == "" || end == "" {
+		return false
+	}
+	startHour, startMinute, err := scheduler.ParseClock(start)
+	if err != nil {
+		return false
+	}
+	endHour, endMinute, err := scheduler.ParseClock(end)
+	if err != nil {
+		return false
+	}
+	from := startHour*60 + startMinute
+	to := endHour*60 + endMinute
+	current := now.Hour()*60 + now.Minute()
+	switch {
+	case from == to:
+		return false
+	case from < to:
+		return current >= from && current < to
+	default:
+		return current >= from || current < to
+	}
+}
+
+// sendReminders reminds opted-in users without an overtime record today, at most once per day
+func (b *Bot) sendReminders(ctx context.Context) error {
+	c, release := b.dispatcher.NewContext()
+	defer release()
+
+	now := helpers.NowWithTimezone()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	var telegramUsers []entities.TelegramUser
+	if err := b.TelegramRepository.FindReminderCandidates(today, today, &telegramUsers, c, database.ClientPostgres); err != nil {
+		return err
+	}
+
+	sent, deferred, failed := 0, 0, 0
+	markup := &telegram.InlineKeyboardMarkup{InlineKeyboard: [][]telegram.InlineKeyboardButton{
+		{wizardButton("📝 Catat sekarang", callbackWizardStart)},
+	}}
+	for _, telegramUser := range telegramUsers {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if inQuietHours(now, telegramUser.QuietHoursStart, telegramUser.QuietHoursEnd) {
+			deferred++
+			continue
+		}
+
+		text := fmt.Sprintf("Halo %s, kamu belum mencatat lembur hari ini (%s).\n\nKetik <code>lembur 18:00-21:00 deskripsi</code> atau tekan tombol di bawah.", html.EscapeString(telegramUser.FirstName), today.Format("2006-01-02"))
+		_, err := b.Client.SendMessage(ctx, telegram.SendMessageParams{
+			ChatID:      telegramUser.TelegramID,
+			Text:        text,
+			ParseMode:   telegram.ParseModeHTML,
+			ReplyMarkup: markup,
+		})
+		var apiErr *telegram.APIError
+		if err != nil && !(errors.As(err, &apiErr) && apiErr.Code == http.StatusForbidden) {
+			// try again on the next run of the window
+			failed++
+			continue
+		}
+		// a user who blocked the bot (403) is also marked, otherwise every run would retry
+		if err := b.TelegramRepository.UpdateFields(telegramUser.ID, map[string]interface{}{"last_reminder_at": now}, c, database.ClientPostgres); err != nil {
+			return err
+		}
+		sent++
+	}
+
+	helpers.LogTelegramBotAction("daily_reminder", 0, 0, failed == 0, map[string]interface{}{
+		"candidates": len(telegramUsers),
+		"sent":       sent,
+		"deferred":   deferred,
+		"failed":     failed,
+	})
+	return nil
+}
+
+// cmdPengingat shows or changes the reminder opt-in and quiet hours of the sender
+func (b *Bot) cmdPengingat(c *fiber.Ctx, cmd *CommandContext) error {
+	telegramUser := cmd.TelegramUser
+	fields := strings.Fields(strings.ToLower(cmd.Args))
+	usage := "Format: <code>/pengingat on</code>, <code>/pengingat off</code>, <code>/pengingat tenang 21:00-07:00</code> atau <code>/pengingat tenang off</code>"
+
+	updates := map[string]interface{}{}
+	switch {
+	case len(fields) == 0:
+		return b.reply(c, cmd.ChatID(), reminderStatus(telegramUser)+"\n\n"+usage)
+	case len(fields) == 1 && (fields[0] == "on" || fields[0] == "aktif"):
+		updates["reminder_enabled"] = true
+		telegramUser.ReminderEnabled = true
+	case len(fields) == 1 && (fields[0] == "off" || fields[0] == "nonaktif"):
+		updates["reminder_enabled"] = false
+		telegramUser.ReminderEnabled = false
+	case len(fields) == 2 && fields[0] == "tenang" && fields[1] == "off":
+		updates["quiet_hours_start"] = ""
+		updates["quiet_hours_end"] = ""
+		telegramUser.QuietHoursStart, telegramUser.QuietHoursEnd = "", ""
+	case len(fields) == 2 && fields[0] == "tenang":
+		window := strings.SplitN(fields[1], "-", 2)
+		if len(window) != 2 {
+			return b.reply(c, cmd.ChatID(), usage)
+		}
+		start, err := parser.ParseClock(window[0])
+		if err != nil {
+			return b.reply(c, cmd.ChatID(), html.EscapeString(err.Error()))
+		}
+		end, err := parser.ParseClock(window[1])
+		if err != nil {
+			return b.reply(c, cmd.ChatID(), html.EscapeString(err.Error()))
+		}
+		updates["quiet_hours_start"] = start
+		updates["quiet_hours_end"] = end
+		telegramUser.QuietHoursStart, telegramUser.QuietHoursEnd = start, end
+	default:
+		return b.reply(c, cmd.ChatID(), usage)
+	}
+
+	if err := b.TelegramRepository.UpdateFields(telegramUser.ID, updates, c, database.ClientPostgres); err != nil {
+		return err
+	}
+	return b.reply(c, cmd.ChatID(), "✅ Pengaturan disimpan\n\n"+reminderStatus(telegramUser))
+}
+
+func reminderStatus(telegramUser *entities.TelegramUser) string {
+	status := "nonaktif"
+	if telegramUser.ReminderEnabled {
+		status = fmt.Sprintf("aktif, setiap hari pukul %s jika belum ada lembur", helpers.GetEnv("REMINDER_TIME", "17:00"))
+	}
+	quiet := "tidak ada"
+	if telegramUser.QuietHoursStart != "" && telegramUser.QuietHoursEnd != "" {
+		quiet = telegramUser.QuietHoursStart + "–" + telegramUser.QuietHoursEnd + " (pengingat ditunda sampai jam tenang selesai)"
+	}
+	return fmt.Sprintf("<b>Pengingat lembur</b>\nStatus: %s\nJam tenang: %s", status, quiet)
+}
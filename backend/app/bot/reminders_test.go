@@ -0,0 +1,101 @@
+package bot
+
+import (
+	"context"
+	"database/sql/driver"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/helpers"
+)
+
+func TestInQuietHours(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2026, 1, 5, hour, minute, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name       string
+		now        time.Time
+		start, end string
+		want       bool
+	}{
+		{name: "no window", now: at(22, 0), want: false},
+		{name: "inside same day window", now: at(13, 0), start: "12:00", end: "14:00", want: true},
+		{name: "end is exclusive", now: at(14, 0), start: "12:00", end: "14:00", want: false},
+		{name: "inside window past midnight", now: at(23, 30), start: "21:00", end: "07:00", want: true},
+		{name: "early morning inside window past midnight", now: at(6, 59), start: "21:00", end: "07:00", want: true},
+		{name: "outside window past midnight", now: at(17, 0), start: "21:00", end: "07:00", want: false},
+		{name: "empty window", now: at(17, 0), start: "17:00", end: "17:00", want: false},
+		{name: "invalid clock", now: at(17, 0), start: "25:00", end: "07:00", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := inQuietHours(tt.now, tt.start, tt.end); got != tt.want {
+				t.Errorf("inQuietHours(%s, %q, %q) = %v, want %v", tt.now.Format("15:04"), tt.start, tt.end, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReminderJob(t *testing.T) {
+	t.Setenv("REMINDER_ENABLED", "true")
+	now := helpers.NowWithTimezone()
+	quietStart := now.Add(-time.Hour).Format("15:04")
+	quietEnd := now.Add(time.Hour).Format("15:04")
+
+	db := newFakeDB(t)
+	columns := []string{"id", "user_id", "telegram_id", "first_name", "reminder_enabled", "quiet_hours_start", "quiet_hours_end"}
+	db.On(`FROM "telegram_users"`, columns,
+		[]driver.Value{int64(1), int64(11), int64(101), "Budi", true, "", ""},
+		[]driver.Value{int64(2), int64(12), int64(102), "Sari", true, "", ""},
+		[]driver.Value{int64(3), int64(13), int64(103), "Tono", true, quietStart, quietEnd},
+		[]driver.Value{int64(4), int64(14), int64(104), "Ayu", true, "", ""},
+	)
+
+	b, fake := newTestBot(t, func(method string, params map[string]interface{}, call int) (int, string) {
+		switch params["chat_id"] {
+		case float64(102):
+			return http.StatusForbidden, `{"ok":false,"error_code":403,"description":"Forbidden: bot was blocked by the user"}`
+		case float64(104):
+			return http.StatusInternalServerError, `{"ok":false,"error_code":500,"description":"Internal Server Error"}`
+		}
+		return sentMessage(method, params, call)
+	})
+
+	job := findJob(t, b, "daily_overtime_reminder")
+	if err := job.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	var chats []float64
+	for _, call := range fake.Calls("sendMessage") {
+		chats = append(chats, call.Params["chat_id"].(float64))
+	}
+	if len(chats) != 3 || chats[0] != 101 || chats[1] != 102 || chats[2] != 104 {
+		t.Errorf("reminders sent to %v, want [101 102 104] (103 is in quiet hours)", chats)
+	}
+
+	// 101 terkirim dan 102 memblokir bot, keduanya ditandai; 104 gagal dan dicoba lagi di putaran berikutnya
+	updates := db.Statements(`UPDATE "telegram_users" SET "last_reminder_at"`)
+	if len(updates) != 2 {
+		t.Fatalf("last_reminder_at updates = %d, want 2", len(updates))
+	}
+	if !containsValue(updates[0].Args, uint(1)) || !containsValue(updates[1].Args, uint(2)) {
+		t.Errorf("last_reminder_at updated for %v and %v, want ids 1 and 2", updates[0].Args, updates[1].Args)
+	}
+}
+
+func TestReminderJobDisabled(t *testing.T) {
+	t.Setenv("REMINDER_ENABLED", "false")
+	b, _ := newTestBot(t, sentMessage)
+	jobs, err := b.Jobs()
+	if err != nil {
+		t.Fatalf("Jobs() error = %v", err)
+	}
+	for _, job := range jobs {
+		if job.Name == "daily_overtime_reminder" {
+			t.Fatal("daily_overtime_reminder scheduled while REMINDER_ENABLED=false")
+		}
+	}
+}
@@ -0,0 +1,477 @@
+package bot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/entities"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/payloads"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/database"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/helpers"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/parser"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/telegram"
+	"github.com/gofiber/fiber/v2"
+)
+
+const flowOvertime = "overtime"
+
+// wizard steps, in order
+const (
+	stepDate     = "date"
+	stepStart    = "start"
+	stepStop     = "stop"
+	stepBreak    = "break"
+	stepCategory = "category"
+	stepConfirm  = "confirm"
+)
+
+var overtimeSteps = []string{stepDate, stepStart, stepStop, stepBreak, stepCategory, stepConfirm}
+
+// callback data of the wizard buttons, "wz:set:<value>" answers the current step
+const (
+	callbackWizardPrefix  = "wz:"
+	callbackWizardSet     = "wz:set:"
+	callbackWizardBack    = "wz:back"
+	callbackWizardCancel  = "wz:cancel"
+	callbackWizardConfirm = "wz:confirm"
+	callbackWizardStart   = "wz:start"
+	noCategoryValue       = "-"
+	categoryIDPrefix      = "id:" // "wz:set:id:<category id>" from the category keyboard
+)
+
+// overtimeWizardData is stored as JSON in ConversationState.Data
+type overtimeWizardData struct {
+	Date          string  `json:"date,omitempty"`
+	TimeStart     string  `json:"time_start,omitempty"`
+	TimeStop      string  `json:"time_stop,omitempty"`
+	BreakDuration float64 `json:"break_duration"`
+	Category      string  `json:"category,omitempty"`
+}
+
+// conversationTTL is how long an unfinished wizard is kept (TELEGRAM_CONVERSATION_TTL in minutes, default 30)
+func conversationTTL() time.Duration {
+	minutes, err := strconv.Atoi(helpers.GetEnv("TELEGRAM_CONVERSATION_TTL", "30"))
+	if err != nil || minutes <= 0 {
+		minutes = 30
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// overtimeCategories returns the active categories, they are offered on the keyboard and accepted as typed answers
+func (b *Bot) overtimeCategories(c *fiber.Ctx) ([]entities.Category, error) {
+	var categories []entities.Category
+	if err := b.CategoryRepository.FindAll(true, &categories, c, database.ClientPostgres); err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+func stepIndex(step string) int {
+	for i, s := range overtimeSteps {
+		if s == step {
+			return i
+		}
+	}
+	return 0
+}
+
+func (b *Bot) startOvertimeWizard(c *fiber.Ctx, cmd *CommandContext) error {
+	return b.beginOvertimeWizard(c, cmd.ChatID(), cmd.TelegramUser.TelegramID)
+}
+
+// beginOvertimeWizard starts (or restarts) the wizard in a chat
+func (b *Bot) beginOvertimeWizard(c *fiber.Ctx, chatID int64, telegramID int64) error {
+	state := entities.ConversationState{
+		ChatID:     chatID,
+		TelegramID: telegramID,
+		Flow:       flowOvertime,
+		Step:       stepDate,
+		Data:       "{}",
+		ExpiresAt:  helpers.NowWithTimezone().Add(conversationTTL()),
+	}
+	categories, err := b.overtimeCategories(c)
+	if err != nil {
+		return err
+	}
+	text, markup := wizardPrompt(state.Step, overtimeWizardData{}, "", categories)
+	message, err := b.send(c, state.ChatID, text, markup)
+	if err != nil {
+		return err
+	}
+	state.MessageID = message.MessageID
+	return b.ConversationStateRepository.Save(&state, c, database.ClientPostgres)
+}
+
+// loadConversation returns the active conversation of a chat, or nil when there is none or it expired
+func (b *Bot) loadConversation(c *fiber.Ctx, chatID int64) (*entities.ConversationState, error) {
+	var state entities.ConversationState
+	if err := b.ConversationStateRepository.FindByChatID(chatID, &state, c, database.ClientPostgres); err != nil {
+		if helpers.IsNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if state.IsExpired(helpers.NowWithTimezone()) {
+		return nil, b.ConversationStateRepository.DeleteByChatID(chatID, c, database.ClientPostgres)
+	}
+	return &state, nil
+}
+
+// handleConversationText treats a non-command message as the answer to the current wizard step
+func (b *Bot) handleConversationText(c *fiber.Ctx, update *telegram.Update) error {
+	message := update.Message
+	state, err := b.loadConversation(c, message.Chat.ID)
+	if err != nil || state == nil {
+		return err
+	}
+	if state.TelegramID != message.From.ID {
+		return nil
+	}
+	if _, err := b.resolveSender(c, message.From); err != nil {
+		return err
+	}
+
+	var data overtimeWizardData
+	if err := json.Unmarshal([]byte(state.Data), &data); err != nil {
+		return err
+	}
+	categories, err := b.overtimeCategories(c)
+	if err != nil {
+		return err
+	}
+	notice := ""
+	if state.Step == stepConfirm {
+		notice = "Tekan tombol Simpan untuk menyimpan."
+	} else if err := applyWizardAnswer(state.Step, &data, message.Text, categories); err != nil {
+		notice = err.Error()
+	} else {
+		state.Step = overtimeSteps[stepIndex(state.Step)+1]
+	}
+
+	// the answer is a new message, so the prompt is sent again below it
+	text, markup := wizardPrompt(state.Step, data, notice, categories)
+	sent, err := b.send(c, state.ChatID, text, markup)
+	if err != nil {
+		return err
+	}
+	if state.MessageID != 0 {
+		_ = b.edit(c, state.ChatID, state.MessageID, wizardSummary(data), nil)
+	}
+	state.MessageID = sent.MessageID
+	return b.saveConversation(c, state, data)
+}
+
+func (b *Bot) saveConversation(c *fiber.Ctx, state *entities.ConversationState, data overtimeWizardData) error {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	state.Data = string(encoded)
+	state.ExpiresAt = helpers.NowWithTimezone().Add(conversationTTL())
+	return b.ConversationStateRepository.Save(state, c, database.ClientPostgres)
+}
+
+func (b *Bot) handleCallbackQuery(c *fiber.Ctx, update *telegram.Update) error {
+	query := update.CallbackQuery
+	if query.Message == nil {
+		return b.answerCallback(c, query.ID, "")
+	}
+	chatID := query.Message.Chat.ID
+	helpers.LogTelegramCallback(chatID, query.From.ID, query.Data, map[string]interface{}{
+		"message_id": query.Message.MessageID,
+	})
+
+	if !strings.HasPrefix(query.Data, callbackWizardPrefix) {
+		return b.answerCallback(c, query.ID, "")
+	}
+	if err := b.handleWizardCallback(c, query); err != nil {
+		_ = b.answerCallback(c, query.ID, "Terjadi kesalahan, silakan coba lagi.")
+		return err
+	}
+	return nil
+}
+
+func (b *Bot) handleWizardCallback(c *fiber.Ctx, query *telegram.CallbackQuery) error {
+	chatID := query.Message.Chat.ID
+	messageID := query.Message.MessageID
+
+	telegramUser, err := b.resolveSender(c, &query.From)
+	if err != nil {
+		return err
+	}
+	if telegramUser == nil {
+		return b.answerCallback(c, query.ID, "Akun Telegram kamu belum terhubung.")
+	}
+
+	if query.Data == callbackWizardStart {
+		if err := b.answerCallback(c, query.ID, ""); err != nil {
+			return err
+		}
+		return b.beginOvertimeWizard(c, chatID, telegramUser.TelegramID)
+	}
+
+	state, err := b.loadConversation(c, chatID)
+	if err != nil {
+		return err
+	}
+	if state == nil || state.MessageID != messageID || state.TelegramID != query.From.ID {
+		_ = b.edit(c, chatID, messageID, html.EscapeString(query.Message.Text), nil)
+		return b.answerCallback(c, query.ID, "Sesi sudah berakhir, mulai lagi dengan /catat")
+	}
+
+	var data overtimeWizardData
+	if err := json.Unmarshal([]byte(state.Data), &data); err != nil {
+		return err
+	}
+
+	categories, err := b.overtimeCategories(c)
+	if err != nil {
+		return err
+	}
+	notice := ""
+	switch {
+	case query.Data == callbackWizardCancel:
+		if err := b.ConversationStateRepository.DeleteByChatID(chatID, c, database.ClientPostgres); err != nil {
+			return err
+		}
+		if err := b.edit(c, chatID, messageID, "Pencatatan lembur dibatalkan.", nil); err != nil {
+			return err
+		}
+		return b.answerCallback(c, query.ID, "Dibatalkan")
+
+	case query.Data == callbackWizardBack:
+		if i := stepIndex(state.Step); i > 0 {
+			state.Step = overtimeSteps[i-1]
+		}
+
+	case query.Data == callbackWizardConfirm && state.Step == stepConfirm:
+		return b.confirmOvertimeWizard(c, query, state, data)
+
+	case strings.HasPrefix(query.Data, callbackWizardSet) && state.Step != stepConfirm:
+		if err := applyWizardAnswer(state.Step, &data, strings.TrimPrefix(query.Data, callbackWizardSet), categories); err != nil {
+			notice = err.Error()
+		} else {
+			state.Step = overtimeSteps[stepIndex(state.Step)+1]
+		}
+
+	default:
+		return b.answerCallback(c, query.ID, "")
+	}
+
+	text, markup := wizardPrompt(state.Step, data, notice, categories)
+	if err := b.edit(c, chatID, messageID, text, markup); err != nil {
+		return err
+	}
+	if err := b.saveConversation(c, state, data); err != nil {
+		return err
+	}
+	return b.answerCallback(c, query.ID, "")
+}
+
+// confirmOvertimeWizard saves the collected answers through the same path as /lembur
+func (b *Bot) confirmOvertimeWizard(c *fiber.Ctx, query *telegram.CallbackQuery, state *entities.ConversationState, data overtimeWizardData) error {
+	duration, err := parser.ComputeDuration(data.TimeStart, data.TimeStop, data.BreakDuration)
+	if err != nil {
+		var parseErr *parser.ParseError
+		if errors.As(err, &parseErr) {
+			return b.answerCallback(c, query.ID, parseErr.Message)
+		}
+		return err
+	}
+	payload := payloads.CreateNewRecordOvertime{
+		TelegramID:    state.TelegramID,
+		Date:          data.Date,
+		TimeStart:     data.TimeStart,
+		TimeStop:      data.TimeStop,
+		BreakDuration: data.BreakDuration,
+		Duration:      duration,
+		Category:      data.Category,
+	}
+
+	if err := b.edit(c, state.ChatID, state.MessageID, wizardSummary(data), nil); err != nil {
+		return err
+	}
+	if err := b.answerCallback(c, query.ID, ""); err != nil {
+		return err
+	}
+	created, err := b.createOvertime(c, state.ChatID, &payload)
+	if created {
+		if deleteErr := b.ConversationStateRepository.DeleteByChatID(state.ChatID, c, database.ClientPostgres); deleteErr != nil {
+			return deleteErr
+		}
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	// the answers are kept at the confirm step, the prompt is sent again below the error so the user can go back and fix it
+	categories, err := b.overtimeCategories(c)
+	if err != nil {
+		return err
+	}
+	text, markup := wizardPrompt(stepConfirm, data, "Lembur belum tersimpan. Tekan Kembali untuk memperbaiki jawaban atau Simpan untuk mencoba lagi.", categories)
+	sent, err := b.send(c, state.ChatID, text, markup)
+	if err != nil {
+		return err
+	}
+	state.MessageID = sent.MessageID
+	return b.saveConversation(c, state, data)
+}
+
+// applyWizardAnswer validates the answer for step and stores it in data, a category must be one of categories
+func applyWizardAnswer(step string, data *overtimeWizardData, answer string, categories []entities.Category) error {
+	answer = strings.TrimSpace(answer)
+	switch step {
+	case stepDate:
+		date, err := parser.ParseDate(answer, helpers.NowWithTimezone())
+		if err != nil {
+			return err
+		}
+		data.Date = date.Format("2006-01-02")
+	case stepStart:
+		timeStart, err := parser.ParseClock(answer)
+		if err != nil {
+			return err
+		}
+		data.TimeStart = timeStart
+	case stepStop:
+		timeStop, err := parser.ParseClock(answer)
+		if err != nil {
+			return err
+		}
+		if timeStop == data.TimeStart {
+			return &parser.ParseError{Message: "Jam selesai tidak boleh sama dengan jam mulai"}
+		}
+		data.TimeStop = timeStop
+	case stepBreak:
+		breakDuration, err := parser.ParseBreak(answer)
+		if err != nil {
+			return err
+		}
+		if _, err := parser.ComputeDuration(data.TimeStart, data.TimeStop, breakDuration); err != nil {
+			return err
+		}
+		data.BreakDuration = breakDuration
+	case stepCategory:
+		if answer == noCategoryValue {
+			data.Category = ""
+			return nil
+		}
+		category := findCategory(categories, answer)
+		if category == nil {
+			return &parser.ParseError{Message: "Kategori tidak dikenal, pilih salah satu tombol"}
+		}
+		data.Category = category.Name
+	}
+	return nil
+}
+
+// findCategory matches a keyboard answer ("id:<id>") or a typed name, "#" and case are ignored
+func findCategory(categories []entities.Category, answer string) *entities.Category {
+	if id, ok := strings.CutPrefix(answer, categoryIDPrefix); ok {
+		for i := range categories {
+			if strconv.FormatUint(uint64(categories[i].ID), 10) == id {
+				return &categories[i]
+			}
+		}
+		return nil
+	}
+	name := strings.TrimSpace(strings.TrimPrefix(answer, "#"))
+	for i := range categories {
+		if strings.EqualFold(categories[i].Name, name) {
+			return &categories[i]
+		}
+	}
+	return nil
+}
+
+// wizardSummary lists the answers given so far
+func wizardSummary(data overtimeWizardData) string {
+	lines := []string{"<b>Catat lembur</b>"}
+	if data.Date != "" {
+		lines = append(lines, "Tanggal: "+data.Date)
+	}
+	if data.TimeStart != "" {
+		lines = append(lines, "Mulai: "+data.TimeStart)
+	}
+	if data.TimeStop != "" {
+		lines = append(lines, "Selesai: "+data.TimeStop)
+	}
+	if data.BreakDuration > 0 {
+		lines = append(lines, fmt.Sprintf("Istirahat: %.2f jam", data.BreakDuration))
+	}
+	if data.Category != "" {
+		lines = append(lines, "Kategori: "+html.EscapeString(data.Category))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func wizardButton(text string, data string) telegram.InlineKeyboardButton {
+	return telegram.InlineKeyboardButton{Text: text, CallbackData: data}
+}
+
+// wizardPrompt builds the message and keyboard for step, notice is shown when the previous answer was rejected
+func wizardPrompt(step string, data overtimeWizardData, notice string, categories []entities.Category) (string, *telegram.InlineKeyboardMarkup) {
+	var question string
+	var rows [][]telegram.InlineKeyboardButton
+	now := helpers.NowWithTimezone()
+
+	switch step {
+	case stepDate:
+		question = "Tanggal lembur? Ketik mis. <code>kemarin</code>, <code>senin</code>, <code>12/03</code> atau pilih:"
+		rows = append(rows, []telegram.InlineKeyboardButton{
+			wizardButton("Hari ini", callbackWizardSet+now.Format("2006-01-02")),
+			wizardButton("Kemarin", callbackWizardSet+now.AddDate(0, 0, -1).Format("2006-01-02")),
+		})
+	case stepStart:
+		question = "Jam mulai? Ketik mis. <code>18:00</code>"
+	case stepStop:
+		question = "Jam selesai? Ketik mis. <code>21:30</code>"
+	case stepBreak:
+		question = "Istirahat berapa lama? Ketik mis. <code>45m</code> atau <code>1 jam 30 menit</code>, atau pilih:"
+		rows = append(rows, []telegram.InlineKeyboardButton{
+			wizardButton("Tanpa istirahat", callbackWizardSet+"0"),
+			wizardButton("30 menit", callbackWizardSet+"30m"),
+			wizardButton("1 jam", callbackWizardSet+"1j"),
+		})
+	case stepCategory:
+		question = "Kategori? Pilih atau ketik nama kategori:"
+		var row []telegram.InlineKeyboardButton
+		for _, category := range categories {
+			row = append(row, wizardButton(category.Name, callbackWizardSet+categoryIDPrefix+strconv.FormatUint(uint64(category.ID), 10)))
+			if len(row) == 2 {
+				rows = append(rows, row)
+				row = nil
+			}
+		}
+		if len(row) > 0 {
+			rows = append(rows, row)
+		}
+		rows = append(rows, []telegram.InlineKeyboardButton{wizardButton("Tanpa kategori", callbackWizardSet+noCategoryValue)})
+	case stepConfirm:
+		question = "Simpan lembur ini?"
+		if duration, err := parser.ComputeDuration(data.TimeStart, data.TimeStop, data.BreakDuration); err == nil {
+			question = fmt.Sprintf("Durasi: <b>%.2f jam</b>\n\n%s", duration, question)
+		}
+		rows = append(rows, []telegram.InlineKeyboardButton{wizardButton("✅ Simpan", callbackWizardConfirm)})
+	}
+
+	navigation := []telegram.InlineKeyboardButton{}
+	if stepIndex(step) > 0 {
+		navigation = append(navigation, wizardButton("⬅️ Kembali", callbackWizardBack))
+	}
+	navigation = append(navigation, wizardButton("✖️ Batal", callbackWizardCancel))
+	rows = append(rows, navigation)
+
+	text := fmt.Sprintf("%s\n\n<i>Langkah %d/%d</i>\n%s", wizardSummary(data), stepIndex(step)+1, len(overtimeSteps), question)
+	if notice != "" {
+		text = "⚠️ " + html.EscapeString(notice) + "\n\n" + text
+	}
+	return text, &telegram.InlineKeyboardMarkup{InlineKeyboard: rows}
+}
@@ -0,0 +1,60 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/entities"
+)
+
+func TestApplyWizardAnswer(t *testing.T) {
+	categories := []entities.Category{{ID: 3, Name: "Proyek"}, {ID: 4, Name: "Support"}}
+	tests := []struct {
+		name    string
+		step    string
+		data    overtimeWizardData
+		answer  string
+		want    overtimeWizardData
+		wantErr bool
+	}{
+		{name: "start", step: stepStart, answer: " 18:00 ", want: overtimeWizardData{TimeStart: "18:00"}},
+		{name: "invalid start", step: stepStart, answer: "nanti", wantErr: true},
+		{
+			name:   "stop past midnight",
+			step:   stepStop,
+			data:   overtimeWizardData{TimeStart: "22:00"},
+			answer: "01:30",
+			want:   overtimeWizardData{TimeStart: "22:00", TimeStop: "01:30"},
+		},
+		{name: "stop equals start", step: stepStop, data: overtimeWizardData{TimeStart: "18:00"}, answer: "18:00", wantErr: true},
+		{
+			name:   "break",
+			step:   stepBreak,
+			data:   overtimeWizardData{TimeStart: "18:00", TimeStop: "21:00"},
+			answer: "30m",
+			want:   overtimeWizardData{TimeStart: "18:00", TimeStop: "21:00", BreakDuration: 0.5},
+		},
+		{name: "break as long as the shift", step: stepBreak, data: overtimeWizardData{TimeStart: "18:00", TimeStop: "19:00"}, answer: "1j", wantErr: true},
+		{name: "category from keyboard", step: stepCategory, answer: "id:4", want: overtimeWizardData{Category: "Support"}},
+		{name: "typed category", step: stepCategory, answer: "#proyek", want: overtimeWizardData{Category: "Proyek"}},
+		{name: "no category", step: stepCategory, data: overtimeWizardData{Category: "Proyek"}, answer: "-", want: overtimeWizardData{}},
+		{name: "unknown category", step: stepCategory, answer: "id:99", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := tt.data
+			err := applyWizardAnswer(tt.step, &data, tt.answer, categories)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("applyWizardAnswer() = %+v, want error", data)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("applyWizardAnswer() error = %v", err)
+			}
+			if data != tt.want {
+				t.Errorf("applyWizardAnswer() = %+v, want %+v", data, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/payloads"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/database"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/helpers"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+type CategoryController struct {
+	CategoryService services.CategoryService
+}
+
+// GetCategories godoc
+// @Summary Get Categories
+// @Description List overtime categories by name, only active ones unless include_inactive is set
+// @Tags Category
+// @Produce json
+// @Param include_inactive query bool false "Include inactive categories"
+// @Success 200 {object} map[string]interface{} "Categories retrieved successfully"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/category/ [get]
+func (h *CategoryController) GetCategories(c *fiber.Ctx) error {
+	helpers.MyLogger("debug", "CategoryManagement", "GetCategories", "controller", "start get categories", nil, c)
+
+	var query payloads.GetCategoriesQuery
+	if err := c.QueryParser(&query); err != nil {
+		return helpers.ResponseErrorBadRequest(c, "Invalid query", nil)
+	}
+
+	tx := database.ClientPostgres
+	if err := h.CategoryService.GetCategories(query.IncludeInactive, c, tx); err != nil {
+		helpers.MyLogger("error", "CategoryManagement", "GetCategories", "controller", "error get categories", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return helpers.ResponseErrorInternal(c, err)
+	}
+	return nil
+}
+
+// GetCategoryByID godoc
+// @Summary Get Category by ID
+// @Description Get an overtime category by ID
+// @Tags Category
+// @Produce json
+// @Param id path int true "Category ID"
+// @Success 200 {object} map[string]interface{} "Category retrieved successfully"
+// @Failure 404 {object} map[string]interface{} "Category not found"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/category/{id} [get]
+func (h *CategoryController) GetCategoryByID(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return helpers.ResponseErrorBadRequest(c, "Invalid category ID", nil)
+	}
+
+	tx := database.ClientPostgres
+	if err := h.CategoryService.GetCategoryByID(uint(id), c, tx); err != nil {
+		helpers.MyLogger("error", "CategoryManagement", "GetCategoryByID", "controller", "error get category", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return helpers.ResponseErrorInternal(c, err)
+	}
+	return nil
+}
+
+// CreateCategory godoc
+// @Summary Create Category
+// @Description Add an overtime category (role admin). Names are unique regardless of case. Without pay_multiplier the pay profile tiers are used, requires_approval false approves new records right away
+// @Tags Category
+// @Accept json
+// @Produce json
+// @Param createCategoryPayload body payloads.CreateCategoryPayload true "Category"
+// @Success 201 {object} map[string]interface{} "Category created successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request body"
+// @Failure 403 {object} map[string]interface{} "Insufficient role"
+// @Failure 409 {object} map[string]interface{} "Category with this name already exists"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/category/ [post]
+func (h *CategoryController) CreateCategory(c *fiber.Ctx) error {
+	helpers.MyLogger("debug", "CategoryManagement", "CreateCategory", "controller", "start create category", nil, c)
+
+	var payload payloads.CreateCategoryPayload
+	if err := helpers.ValidateBody(&payload, c); err != nil {
+		helpers.MyLogger("error", "CategoryManagement", "CreateCategory", "controller", "error validate body", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		if customErr, ok := err.(helpers.Error); ok {
+			return helpers.ResponseErrorBadRequest(c, customErr.Message, customErr.Data)
+		}
+		return helpers.ResponseErrorBadRequest(c, "Invalid payload", nil)
+	}
+
+	tx := database.ClientPostgres.Begin()
+	defer tx.Rollback()
+
+	if err := h.CategoryService.CreateCategory(&payload, c, tx); err != nil {
+		helpers.MyLogger("error", "CategoryManagement", "CreateCategory", "controller", "error create category", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return helpers.ResponseErrorInternal(c, err)
+	}
+	return nil
+}
+
+// UpdateCategory godoc
+// @Summary Update Category
+// @Description Change the name, colour, active flag, pay multiplier or approval rule of a category (role admin). Renaming keeps existing records linked
+// @Tags Category
+// @Accept json
+// @Produce json
+// @Param id path int true "Category ID"
+// @Param updateCategoryPayload body payloads.UpdateCategoryPayload true "Fields to update"
+// @Success 200 {object} map[string]interface{} "Category updated successfully"
+// @Failure 403 {object} map[string]interface{} "Insufficient role"
+// @Failure 404 {object} map[string]interface{} "Category not found"
+// @Failure 409 {object} map[string]interface{} "Category with this name already exists"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/category/{id} [put]
+func (h *CategoryController) UpdateCategory(c *fiber.Ctx) error {
+	helpers.MyLogger("debug", "CategoryManagement", "UpdateCategory", "controller", "start update category", nil, c)
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return helpers.ResponseErrorBadRequest(c, "Invalid category ID", nil)
+	}
+
+	var payload payloads.UpdateCategoryPayload
+	if err := helpers.ValidateBody(&payload, c); err != nil {
+		if customErr, ok := err.(helpers.Error); ok {
+			return helpers.ResponseErrorBadRequest(c, customErr.Message, customErr.Data)
+		}
+		return helpers.ResponseErrorBadRequest(c, "Invalid payload", nil)
+	}
+
+	tx := database.ClientPostgres.Begin()
+	defer tx.Rollback()
+
+	if err := h.CategoryService.UpdateCategory(uint(id), &payload, c, tx); err != nil {
+		helpers.MyLogger("error", "CategoryManagement", "UpdateCategory", "controller", "error update category", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return helpers.ResponseErrorInternal(c, err)
+	}
+	return nil
+}
+
+// DeleteCategory godoc
+// @Summary Delete Category
+// @Description Delete a category that no overtime record uses (role admin), deactivate categories that are in use instead
+// @Tags Category
+// @Produce json
+// @Param id path int true "Category ID"
+// @Success 200 {object} map[string]interface{} "Category deleted successfully"
+// @Failure 403 {object} map[string]interface{} "Insufficient role"
+// @Failure 404 {object} map[string]interface{} "Category not found"
+// @Failure 409 {object} map[string]interface{} "Category is used by overtime records"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/category/{id} [delete]
+func (h *CategoryController) DeleteCategory(c *fiber.Ctx) error {
+	helpers.MyLogger("debug", "CategoryManagement", "DeleteCategory", "controller", "start delete category", nil, c)
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return helpers.ResponseErrorBadRequest(c, "Invalid category ID", nil)
+	}
+
+	tx := database.ClientPostgres.Begin()
+	defer tx.Rollback()
+
+	if err := h.CategoryService.DeleteCategory(uint(id), c, tx); err != nil {
+		helpers.MyLogger("error", "CategoryManagement", "DeleteCategory", "controller", "error delete category", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return helpers.ResponseErrorInternal(c, err)
+	}
+	return nil
+}
@@ -0,0 +1,279 @@
+package controllers
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/payloads"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/database"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/helpers"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// maxHolidayFileSize limits uploaded holiday files, a yearly calendar is a few KB
+const maxHolidayFileSize = 1 << 20
+
+type HolidayController struct {
+	HolidayService services.HolidayService
+}
+
+// GetHolidays godoc
+// @Summary Get Holidays
+// @Description List national holidays and collective leave (cuti bersama) by year or date range, oldest first
+// @Tags Holiday
+// @Produce json
+// @Param year query int false "Year, default this year"
+// @Param start_date query string false "Start date (YYYY-MM-DD), used with end_date instead of year"
+// @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Success 200 {object} map[string]interface{} "Holidays retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid query"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/holiday/ [get]
+func (h *HolidayController) GetHolidays(c *fiber.Ctx) error {
+	helpers.MyLogger("debug", "HolidayManagement", "GetHolidays", "controller", "start get holidays", nil, c)
+
+	var query payloads.GetHolidaysQuery
+	if err := c.QueryParser(&query); err != nil {
+		return helpers.ResponseErrorBadRequest(c, "Invalid query", nil)
+	}
+	if err := helpers.ValidateStruct(&query); err != nil {
+		if customErr, ok := err.(helpers.Error); ok {
+			return helpers.ResponseErrorBadRequest(c, customErr.Message, customErr.Data)
+		}
+		return helpers.ResponseErrorBadRequest(c, "Invalid query", nil)
+	}
+
+	var startDate, endDate time.Time
+	if query.StartDate != "" || query.EndDate != "" {
+		var err error
+		if startDate, err = helpers.ParseDateWithTimezone(query.StartDate); err != nil {
+			return helpers.ResponseErrorBadRequest(c, "Invalid start date format. Use YYYY-MM-DD", nil)
+		}
+		if endDate, err = helpers.ParseDateWithTimezone(query.EndDate); err != nil {
+			return helpers.ResponseErrorBadRequest(c, "Invalid end date format. Use YYYY-MM-DD", nil)
+		}
+		if endDate.Before(startDate) {
+			return helpers.ResponseErrorBadRequest(c, "End date must be after start date", nil)
+		}
+	} else {
+		year := query.Year
+		if year == 0 {
+			year = helpers.NowWithTimezone().Year()
+		}
+		startDate = time.Date(year, time.January, 1, 0, 0, 0, 0, helpers.GetTimezone())
+		endDate = time.Date(year, time.December, 31, 0, 0, 0, 0, helpers.GetTimezone())
+	}
+
+	tx := database.ClientPostgres
+	if err := h.HolidayService.GetHolidays(startDate, endDate, c, tx); err != nil {
+		helpers.MyLogger("error", "HolidayManagement", "GetHolidays", "controller", "error get holidays", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return helpers.ResponseErrorInternal(c, err)
+	}
+	return nil
+}
+
+// GetHolidayByID godoc
+// @Summary Get Holiday by ID
+// @Description Get a holiday by ID
+// @Tags Holiday
+// @Produce json
+// @Param id path int true "Holiday ID"
+// @Success 200 {object} map[string]interface{} "Holiday retrieved successfully"
+// @Failure 404 {object} map[string]interface{} "Holiday not found"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/holiday/{id} [get]
+func (h *HolidayController) GetHolidayByID(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return helpers.ResponseErrorBadRequest(c, "Invalid holiday ID", nil)
+	}
+
+	tx := database.ClientPostgres
+	if err := h.HolidayService.GetHolidayByID(uint(id), c, tx); err != nil {
+		helpers.MyLogger("error", "HolidayManagement", "GetHolidayByID", "controller", "error get holiday", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return helpers.ResponseErrorInternal(c, err)
+	}
+	return nil
+}
+
+// CreateHoliday godoc
+// @Summary Create Holiday
+// @Description Add a national holiday or collective leave (role admin). Kind is detected from the name when empty
+// @Tags Holiday
+// @Accept json
+// @Produce json
+// @Param createHolidayPayload body payloads.CreateHolidayPayload true "Holiday"
+// @Success 201 {object} map[string]interface{} "Holiday created successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request body"
+// @Failure 403 {object} map[string]interface{} "Insufficient role"
+// @Failure 409 {object} map[string]interface{} "Holiday on this date already exists"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/holiday/ [post]
+func (h *HolidayController) CreateHoliday(c *fiber.Ctx) error {
+	helpers.MyLogger("debug", "HolidayManagement", "CreateHoliday", "controller", "start create holiday", nil, c)
+
+	var payload payloads.CreateHolidayPayload
+	if err := helpers.ValidateBody(&payload, c); err != nil {
+		helpers.MyLogger("error", "HolidayManagement", "CreateHoliday", "controller", "error validate body", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		if customErr, ok := err.(helpers.Error); ok {
+			return helpers.ResponseErrorBadRequest(c, customErr.Message, customErr.Data)
+		}
+		return helpers.ResponseErrorBadRequest(c, "Invalid payload", nil)
+	}
+
+	tx := database.ClientPostgres.Begin()
+	defer tx.Rollback()
+
+	if err := h.HolidayService.CreateHoliday(&payload, c, tx); err != nil {
+		helpers.MyLogger("error", "HolidayManagement", "CreateHoliday", "controller", "error create holiday", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return helpers.ResponseErrorInternal(c, err)
+	}
+	return nil
+}
+
+// UpdateHoliday godoc
+// @Summary Update Holiday
+// @Description Change the date, name or kind of a holiday (role admin)
+// @Tags Holiday
+// @Accept json
+// @Produce json
+// @Param id path int true "Holiday ID"
+// @Param updateHolidayPayload body payloads.UpdateHolidayPayload true "Fields to update"
+// @Success 200 {object} map[string]interface{} "Holiday updated successfully"
+// @Failure 403 {object} map[string]interface{} "Insufficient role"
+// @Failure 404 {object} map[string]interface{} "Holiday not found"
+// @Failure 409 {object} map[string]interface{} "Holiday on this date already exists"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/holiday/{id} [put]
+func (h *HolidayController) UpdateHoliday(c *fiber.Ctx) error {
+	helpers.MyLogger("debug", "HolidayManagement", "UpdateHoliday", "controller", "start update holiday", nil, c)
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return helpers.ResponseErrorBadRequest(c, "Invalid holiday ID", nil)
+	}
+
+	var payload payloads.UpdateHolidayPayload
+	if err := helpers.ValidateBody(&payload, c); err != nil {
+		if customErr, ok := err.(helpers.Error); ok {
+			return helpers.ResponseErrorBadRequest(c, customErr.Message, customErr.Data)
+		}
+		return helpers.ResponseErrorBadRequest(c, "Invalid payload", nil)
+	}
+
+	tx := database.ClientPostgres.Begin()
+	defer tx.Rollback()
+
+	if err := h.HolidayService.UpdateHoliday(uint(id), &payload, c, tx); err != nil {
+		helpers.MyLogger("error", "HolidayManagement", "UpdateHoliday", "controller", "error update holiday", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return helpers.ResponseErrorInternal(c, err)
+	}
+	return nil
+}
+
+// DeleteHoliday godoc
+// @Summary Delete Holiday
+// @Description Delete a holiday (role admin)
+// @Tags Holiday
+// @Produce json
+// @Param id path int true "Holiday ID"
+// @Success 200 {object} map[string]interface{} "Holiday deleted successfully"
+// @Failure 403 {object} map[string]interface{} "Insufficient role"
+// @Failure 404 {object} map[string]interface{} "Holiday not found"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/holiday/{id} [delete]
+func (h *HolidayController) DeleteHoliday(c *fiber.Ctx) error {
+	helpers.MyLogger("debug", "HolidayManagement", "DeleteHoliday", "controller", "start delete holiday", nil, c)
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return helpers.ResponseErrorBadRequest(c, "Invalid holiday ID", nil)
+	}
+
+	tx := database.ClientPostgres.Begin()
+	defer tx.Rollback()
+
+	if err := h.HolidayService.DeleteHoliday(uint(id), c, tx); err != nil {
+		helpers.MyLogger("error", "HolidayManagement", "DeleteHoliday", "controller", "error delete holiday", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return helpers.ResponseErrorInternal(c, err)
+	}
+	return nil
+}
+
+// ImportHolidays godoc
+// @Summary Import Holidays
+// @Description Upload an iCalendar (.ics) or CSV (date,name[,kind]) file (role admin). Existing dates are overwritten, invalid rows are skipped and reported
+// @Tags Holiday
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Holiday file (.ics or .csv, max 1 MB)"
+// @Param format formData string false "ics or csv, default from the file extension"
+// @Success 200 {object} map[string]interface{} "Holidays imported successfully"
+// @Failure 400 {object} map[string]interface{} "Missing or unsupported file"
+// @Failure 403 {object} map[string]interface{} "Insufficient role"
+// @Failure 422 {object} map[string]interface{} "Invalid file content"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/holiday/import [post]
+func (h *HolidayController) ImportHolidays(c *fiber.Ctx) error {
+	helpers.MyLogger("debug", "HolidayManagement", "ImportHolidays", "controller", "start import holidays", nil, c)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return helpers.ResponseErrorBadRequest(c, "File is required (multipart field \"file\")", nil)
+	}
+	if fileHeader.Size > maxHolidayFileSize {
+		return helpers.ResponseErrorBadRequest(c, "File is too large, maximum 1 MB", nil)
+	}
+
+	format := strings.ToLower(strings.TrimSpace(c.FormValue("format")))
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(fileHeader.Filename)), ".")
+	}
+	if format == "ical" {
+		format = "ics"
+	}
+	if format != "ics" && format != "csv" {
+		return helpers.ResponseErrorBadRequest(c, "Unsupported format, use ics or csv", nil)
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		helpers.MyLogger("error", "HolidayManagement", "ImportHolidays", "controller", "error open uploaded file", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return helpers.ResponseErrorInternal(c, err)
+	}
+	defer file.Close()
+
+	tx := database.ClientPostgres.Begin()
+	defer tx.Rollback()
+
+	if err := h.HolidayService.ImportHolidays(format, file, c, tx); err != nil {
+		helpers.MyLogger("error", "HolidayManagement", "ImportHolidays", "controller", "error import holidays", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return helpers.ResponseErrorInternal(c, err)
+	}
+	return nil
+}
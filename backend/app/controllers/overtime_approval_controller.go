@@ -0,0 +1,173 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/payloads"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/database"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/helpers"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// SubmitRecordOvertime godoc
+// @Summary Submit Overtime Record
+// @Description Submit a draft or rejected overtime record for approval, only the owner of the record or an admin
+// @Tags Overtime Approval
+// @Accept json
+// @Produce json
+// @Param id path int true "Overtime record ID"
+// @Param changeStatusPayload body payloads.ChangeOvertimeStatusPayload false "Optional reason"
+// @Success 200 {object} map[string]interface{} "Overtime status changed to submitted"
+// @Failure 403 {object} map[string]interface{} "Not the owner of the record"
+// @Failure 404 {object} map[string]interface{} "Overtime record not found"
+// @Failure 409 {object} map[string]interface{} "Status transition not allowed"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/overtime/{id}/submit [post]
+func (o *OvertimeController) SubmitRecordOvertime(c *fiber.Ctx) error {
+	return o.changeStatus(c, "SubmitRecordOvertime", o.OvertimeService.SubmitRecordOvertime)
+}
+
+// ApproveRecordOvertime godoc
+// @Summary Approve Overtime Record
+// @Description Approve a submitted overtime record (role approver or admin), approvers cannot approve their own records. Approved records cannot be updated or deleted until reopened
+// @Tags Overtime Approval
+// @Accept json
+// @Produce json
+// @Param id path int true "Overtime record ID"
+// @Param changeStatusPayload body payloads.ChangeOvertimeStatusPayload false "Optional reason"
+// @Success 200 {object} map[string]interface{} "Overtime status changed to approved"
+// @Failure 403 {object} map[string]interface{} "Insufficient role or own record"
+// @Failure 409 {object} map[string]interface{} "Status transition not allowed"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/overtime/{id}/approve [post]
+func (o *OvertimeController) ApproveRecordOvertime(c *fiber.Ctx) error {
+	return o.changeStatus(c, "ApproveRecordOvertime", o.OvertimeService.ApproveRecordOvertime)
+}
+
+// RejectRecordOvertime godoc
+// @Summary Reject Overtime Record
+// @Description Reject a submitted overtime record (role approver or admin), approvers cannot reject their own records
+// @Tags Overtime Approval
+// @Accept json
+// @Produce json
+// @Param id path int true "Overtime record ID"
+// @Param changeStatusPayload body payloads.ChangeOvertimeStatusPayload false "Optional reason"
+// @Success 200 {object} map[string]interface{} "Overtime status changed to rejected"
+// @Failure 403 {object} map[string]interface{} "Insufficient role or own record"
+// @Failure 409 {object} map[string]interface{} "Status transition not allowed"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/overtime/{id}/reject [post]
+func (o *OvertimeController) RejectRecordOvertime(c *fiber.Ctx) error {
+	return o.changeStatus(c, "RejectRecordOvertime", o.OvertimeService.RejectRecordOvertime)
+}
+
+// ReopenRecordOvertime godoc
+// @Summary Reopen Overtime Record
+// @Description Move an approved or rejected overtime record back to draft (role approver or admin)
+// @Tags Overtime Approval
+// @Accept json
+// @Produce json
+// @Param id path int true "Overtime record ID"
+// @Param changeStatusPayload body payloads.ChangeOvertimeStatusPayload false "Optional reason"
+// @Success 200 {object} map[string]interface{} "Overtime status changed to draft"
+// @Failure 403 {object} map[string]interface{} "Insufficient role"
+// @Failure 409 {object} map[string]interface{} "Status transition not allowed"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/overtime/{id}/reopen [post]
+func (o *OvertimeController) ReopenRecordOvertime(c *fiber.Ctx) error {
+	return o.changeStatus(c, "ReopenRecordOvertime", o.OvertimeService.ReopenRecordOvertime)
+}
+
+func (o *OvertimeController) changeStatus(c *fiber.Ctx, method string, call func(uint, *payloads.ChangeOvertimeStatusPayload, *fiber.Ctx, *gorm.DB) error) error {
+	helpers.MyLogger("debug", "OvertimeManagement", method, "controller", "start change overtime status", nil, c)
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		helpers.MyLogger("error", "OvertimeManagement", method, "controller", "error parse overtime ID", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return helpers.ResponseErrorBadRequest(c, "Invalid overtime ID", nil)
+	}
+
+	// the body is optional, it only carries the reason
+	var payload payloads.ChangeOvertimeStatusPayload
+	if len(c.Body()) > 0 {
+		if err := helpers.ValidateBody(&payload, c); err != nil {
+			helpers.MyLogger("error", "OvertimeManagement", method, "controller", "error validate body", map[string]interface{}{
+				"error": err.Error(),
+			}, c)
+			if customErr, ok := err.(helpers.Error); ok {
+				return helpers.ResponseErrorBadRequest(c, customErr.Message, customErr.Data)
+			}
+			return helpers.ResponseErrorBadRequest(c, "Invalid payload", nil)
+		}
+	}
+
+	tx := database.ClientPostgres.Begin()
+	defer tx.Rollback()
+
+	if err := call(uint(id), &payload, c, tx); err != nil {
+		helpers.MyLogger("error", "OvertimeManagement", method, "controller", "error change overtime status", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return helpers.ResponseErrorInternal(c, err)
+	}
+	return nil
+}
+
+// GetPendingApprovals godoc
+// @Summary Pending Overtime Approvals
+// @Description List submitted overtime records waiting for approval, oldest date first (role approver or admin)
+// @Tags Overtime Approval
+// @Produce json
+// @Success 200 {object} map[string]interface{} "Pending approvals retrieved successfully"
+// @Failure 403 {object} map[string]interface{} "Insufficient role"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/overtime/pending [get]
+func (o *OvertimeController) GetPendingApprovals(c *fiber.Ctx) error {
+	helpers.MyLogger("debug", "OvertimeManagement", "GetPendingApprovals", "controller", "start get pending approvals", nil, c)
+
+	tx := database.ClientPostgres
+	if err := o.OvertimeService.GetPendingApprovals(c, tx); err != nil {
+		helpers.MyLogger("error", "OvertimeManagement", "GetPendingApprovals", "controller", "error get pending approvals", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return helpers.ResponseErrorInternal(c, err)
+	}
+	return nil
+}
+
+// GetStatusHistory godoc
+// @Summary Overtime Status History
+// @Description List the status changes of an overtime record with who changed it, when and why
+// @Tags Overtime Approval
+// @Produce json
+// @Param id path int true "Overtime record ID"
+// @Success 200 {object} map[string]interface{} "Overtime status history retrieved successfully"
+// @Failure 404 {object} map[string]interface{} "Overtime record not found"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/overtime/{id}/history [get]
+func (o *OvertimeController) GetStatusHistory(c *fiber.Ctx) error {
+	helpers.MyLogger("debug", "OvertimeManagement", "GetStatusHistory", "controller", "start get overtime status history", nil, c)
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return helpers.ResponseErrorBadRequest(c, "Invalid overtime ID", nil)
+	}
+
+	tx := database.ClientPostgres
+	if err := o.OvertimeService.GetStatusHistory(uint(id), c, tx); err != nil {
+		helpers.MyLogger("error", "OvertimeManagement", "GetStatusHistory", "controller", "error get overtime status history", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return helpers.ResponseErrorInternal(c, err)
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package controllers
+
+import (
+	"io"
+	"strconv"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/entities"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/payloads"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/database"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/helpers"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+type OvertimeAttachmentController struct {
+	OvertimeAttachmentService services.OvertimeAttachmentService
+}
+
+// attachmentIDs reads the :id and :attachment_id path parameters, attachmentID is 0 when the route has none
+func attachmentIDs(c *fiber.Ctx) (uint, uint, bool) {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return 0, 0, false
+	}
+	if c.Params("attachment_id") == "" {
+		return uint(id), 0, true
+	}
+	attachmentID, err := strconv.ParseUint(c.Params("attachment_id"), 10, 32)
+	if err != nil {
+		return 0, 0, false
+	}
+	return uint(id), uint(attachmentID), true
+}
+
+// UploadAttachments godoc
+// @Summary Upload Overtime Attachments
+// @Description Attach evidence files (photos, PDF) to an overtime record, repeat the "file" field for several files (max 5 per request). The type is detected from the content and must be in ATTACHMENT_ALLOWED_TYPES, each file at most ATTACHMENT_MAX_SIZE_MB. Only the owner of the record or an admin can upload
+// @Tags Overtime Attachment
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Overtime ID"
+// @Param file formData file true "Evidence file"
+// @Success 201 {object} map[string]interface{} "Attachments uploaded successfully"
+// @Failure 400 {object} map[string]interface{} "Missing or empty file"
+// @Failure 404 {object} map[string]interface{} "Overtime record not found"
+// @Failure 413 {object} map[string]interface{} "File too large"
+// @Failure 415 {object} map[string]interface{} "File type not allowed"
+// @Failure 422 {object} map[string]interface{} "Too many attachments"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/overtime/{id}/attachments [post]
+func (a *OvertimeAttachmentController) UploadAttachments(c *fiber.Ctx) error {
+	helpers.MyLogger("debug", "OvertimeAttachment", "UploadAttachments", "controller", "start upload attachments", nil, c)
+
+	id, _, ok := attachmentIDs(c)
+	if !ok {
+		return helpers.ResponseErrorBadRequest(c, "Invalid overtime ID", nil)
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil || len(form.File["file"]) == 0 {
+		return helpers.ResponseErrorBadRequest(c, "File is required (multipart field \"file\")", nil)
+	}
+	fileHeaders := form.File["file"]
+	if len(fileHeaders) > services.MaxAttachmentsPerUpload {
+		return helpers.ResponseErrorBadRequest(c, "Too many files, maximum "+strconv.Itoa(services.MaxAttachmentsPerUpload)+" per request", nil)
+	}
+
+	maxSize := services.AttachmentMaxSize()
+	files := make([]payloads.AttachmentUpload, 0, len(fileHeaders))
+	for _, fileHeader := range fileHeaders {
+		if fileHeader.Size > maxSize {
+			return helpers.Response(c, fiber.StatusRequestEntityTooLarge, "File "+fileHeader.Filename+" is larger than "+strconv.FormatInt(maxSize>>20, 10)+" MB", nil)
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			helpers.MyLogger("error", "OvertimeAttachment", "UploadAttachments", "controller", "error open uploaded file", map[string]interface{}{
+				"error": err.Error(),
+			}, c)
+			return helpers.ResponseErrorInternal(c, err)
+		}
+		data, err := io.ReadAll(io.LimitReader(file, maxSize+1))
+		file.Close()
+		if err != nil {
+			return helpers.ResponseErrorInternal(c, err)
+		}
+		files = append(files, payloads.AttachmentUpload{FileName: fileHeader.Filename, Data: data})
+	}
+
+	tx := database.ClientPostgres.Begin()
+	defer tx.Rollback()
+
+	if err := a.OvertimeAttachmentService.UploadAttachments(id, files, entities.AttachmentSourceAPI, c, tx); err != nil {
+		helpers.MyLogger("error", "OvertimeAttachment", "UploadAttachments", "controller", "error upload attachments", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return helpers.ResponseErrorInternal(c, err)
+	}
+	return nil
+}
+
+// ListAttachments godoc
+// @Summary List Overtime Attachments
+// @Description List the attachments of an overtime record (owner, approver or admin)
+// @Tags Overtime Attachment
+// @Produce json
+// @Param id path int true "Overtime ID"
+// @Success 200 {object} map[string]interface{} "Attachments retrieved successfully"
+// @Failure 404 {object} map[string]interface{} "Overtime record not found"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/overtime/{id}/attachments [get]
+func (a *OvertimeAttachmentController) ListAttachments(c *fiber.Ctx) error {
+	helpers.MyLogger("debug", "OvertimeAttachment", "ListAttachments", "controller", "start list attachments", nil, c)
+
+	id, _, ok := attachmentIDs(c)
+	if !ok {
+		return helpers.ResponseErrorBadRequest(c, "Invalid overtime ID", nil)
+	}
+
+	if err := a.OvertimeAttachmentService.ListAttachments(id, c, database.ClientPostgres); err != nil {
+		helpers.MyLogger("error", "OvertimeAttachment", "ListAttachments", "controller", "error list attachments", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return helpers.ResponseErrorInternal(c, err)
+	}
+	return nil
+}
+
+// DownloadAttachment godoc
+// @Summary Download Overtime Attachment
+// @Description Download one attachment of an overtime record, only for the owner of the record, approvers and admins
+// @Tags Overtime Attachment
+// @Produce octet-stream
+// @Param id path int true "Overtime ID"
+// @Param attachment_id path int true "Attachment ID"
+// @Success 200 {file} file "Attachment file"
+// @Failure 404 {object} map[string]interface{} "Overtime record or attachment not found"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/overtime/{id}/attachments/{attachment_id} [get]
+func (a *OvertimeAttachmentController) DownloadAttachment(c *fiber.Ctx) error {
+	helpers.MyLogger("debug", "OvertimeAttachment", "DownloadAttachment", "controller", "start download attachment", nil, c)
+
+	id, attachmentID, ok := attachmentIDs(c)
+	if !ok {
+		return helpers.ResponseErrorBadRequest(c, "Invalid overtime or attachment ID", nil)
+	}
+
+	if err := a.OvertimeAttachmentService.DownloadAttachment(id, attachmentID, c, database.ClientPostgres); err != nil {
+		helpers.MyLogger("error", "OvertimeAttachment", "DownloadAttachment", "controller", "error download attachment", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return helpers.ResponseErrorInternal(c, err)
+	}
+	return nil
+}
+
+// DeleteAttachment godoc
+// @Summary Delete Overtime Attachment
+// @Description Delete one attachment and its stored file, only the owner of the record or an admin
+// @Tags Overtime Attachment
+// @Produce json
+// @Param id path int true "Overtime ID"
+// @Param attachment_id path int true "Attachment ID"
+// @Success 200 {object} map[string]interface{} "Attachment deleted successfully"
+// @Failure 403 {object} map[string]interface{} "Approvers cannot change attachments"
+// @Failure 404 {object} map[string]interface{} "Overtime record or attachment not found"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/overtime/{id}/attachments/{attachment_id} [delete]
+func (a *OvertimeAttachmentController) DeleteAttachment(c *fiber.Ctx) error {
+	helpers.MyLogger("debug", "OvertimeAttachment", "DeleteAttachment", "controller", "start delete attachment", nil, c)
+
+	id, attachmentID, ok := attachmentIDs(c)
+	if !ok {
+		return helpers.ResponseErrorBadRequest(c, "Invalid overtime or attachment ID", nil)
+	}
+
+	tx := database.ClientPostgres.Begin()
+	defer tx.Rollback()
+
+	if err := a.OvertimeAttachmentService.DeleteAttachment(id, attachmentID, c, tx); err != nil {
+		helpers.MyLogger("error", "OvertimeAttachment", "DeleteAttachment", "controller", "error delete attachment", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return helpers.ResponseErrorInternal(c, err)
+	}
+	return nil
+}
@@ -54,7 +54,28 @@ func (o *OvertimeController) CreateNewRecordOvertime(c *fiber.Ctx) error {
 	return nil
 }
 
-// GetAllRecordOvertimeByTelegramID retrieves all overtime records by telegram ID
+// GetAllRecordOvertimeByTelegramID godoc
+// @Summary Get Overtime Records by Telegram ID
+// @Description List overtime records of a telegram user with cursor pagination, filters and sorting. The next page is requested with the next_cursor of the pagination object and the same sort
+// @Tags Overtime
+// @Produce json
+// @Param telegram_id path int true "Telegram ID"
+// @Param limit query int false "Page size, default 20, max 100"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param sort query string false "id (default), date, duration, created_at or updated_at, prefix - for descending"
+// @Param order query string false "asc or desc (default)"
+// @Param start_date query string false "From date (YYYY-MM-DD)"
+// @Param end_date query string false "Until date (YYYY-MM-DD)"
+// @Param category query string false "Category name, case-insensitive"
+// @Param category_id query int false "Category ID, 0 = records without category"
+// @Param min_duration query number false "Minimum duration in hours"
+// @Param max_duration query number false "Maximum duration in hours"
+// @Param status query string false "draft, submitted, approved or rejected"
+// @Success 200 {object} map[string]interface{} "Overtime records retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid query or pagination"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/overtime/telegram/{telegram_id} [get]
 func (o *OvertimeController) GetAllRecordOvertimeByTelegramID(c *fiber.Ctx) error {
 	helpers.MyLogger("debug", "OvertimeManagement", "GetAllRecordOvertimeByTelegramID", "controller", "start get all overtime records by telegram ID", nil, c)
 
@@ -67,12 +88,26 @@ func (o *OvertimeController) GetAllRecordOvertimeByTelegramID(c *fiber.Ctx) erro
 		return helpers.ResponseErrorBadRequest(c, "Invalid telegram ID", nil)
 	}
 
+	var query payloads.GetOvertimeListQuery
+	if err := c.QueryParser(&query); err != nil {
+		helpers.MyLogger("error", "OvertimeManagement", "GetAllRecordOvertimeByTelegramID", "controller", "error parse query", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return helpers.ResponseErrorBadRequest(c, "Invalid query", nil)
+	}
+	if err := helpers.ValidateStruct(&query); err != nil {
+		if customErr, ok := err.(helpers.Error); ok {
+			return helpers.ResponseErrorBadRequest(c, customErr.Message, customErr.Data)
+		}
+		return helpers.ResponseErrorBadRequest(c, "Invalid query", nil)
+	}
+
 	tx := database.ClientPostgres
 
 	helpers.MyLogger("debug", "OvertimeManagement", "GetAllRecordOvertimeByTelegramID", "controller", "start calling service for get all overtime records", map[string]interface{}{
 		"telegram_id": telegramID,
 	}, c)
-	if err := o.OvertimeService.GetAllRecordOvertimeByTelegramID(telegramID, c, tx); err != nil {
+	if err := o.OvertimeService.GetAllRecordOvertimeByTelegramID(telegramID, &query, c, tx); err != nil {
 		helpers.MyLogger("error", "OvertimeManagement", "GetAllRecordOvertimeByTelegramID", "controller", "error get all overtime records", map[string]interface{}{
 			"error": err.Error(),
 		}, c)
@@ -81,9 +116,85 @@ func (o *OvertimeController) GetAllRecordOvertimeByTelegramID(c *fiber.Ctx) erro
 	return nil
 }
 
+// GetOvertimeRecords godoc
+// @Summary Get Overtime Records by Date or Date Range
+// @Description Get the overtime records of a telegram user on one date (date) or between two dates (start_date and end_date, inclusive). Replaces POST /v1/overtime/by-date and POST /v1/overtime/between-dates
+// @Tags Overtime
+// @Produce json
+// @Param telegram_id query int true "Telegram ID"
+// @Param date query string false "Date (YYYY-MM-DD), not combined with start_date and end_date"
+// @Param start_date query string false "Start date (YYYY-MM-DD), required with end_date"
+// @Param end_date query string false "End date (YYYY-MM-DD), required with start_date"
+// @Success 200 {object} map[string]interface{} "Overtime records retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid query"
+// @Failure 404 {object} map[string]interface{} "Overtime record not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/overtime/ [get]
+func (o *OvertimeController) GetOvertimeRecords(c *fiber.Ctx) error {
+	helpers.MyLogger("debug", "OvertimeManagement", "GetOvertimeRecords", "controller", "start get overtime records", nil, c)
+
+	var query payloads.GetOvertimeQuery
+	if err := c.QueryParser(&query); err != nil {
+		helpers.MyLogger("error", "OvertimeManagement", "GetOvertimeRecords", "controller", "error parse query", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return helpers.ResponseErrorBadRequest(c, "Invalid query", nil)
+	}
+	if err := helpers.ValidateStruct(&query); err != nil {
+		if customErr, ok := err.(helpers.Error); ok {
+			return helpers.ResponseErrorBadRequest(c, customErr.Message, customErr.Data)
+		}
+		return helpers.ResponseErrorBadRequest(c, "Invalid query", nil)
+	}
+	if query.Date != "" && query.StartDate != "" {
+		return helpers.ResponseErrorBadRequest(c, "Use either date or start_date and end_date", nil)
+	}
+	if query.Date == "" && query.StartDate == "" {
+		return helpers.ResponseErrorBadRequest(c, "Date or start_date and end_date is required", nil)
+	}
+
+	tx := database.ClientPostgres
+
+	if query.Date != "" {
+		date, err := helpers.ParseDateWithTimezone(query.Date)
+		if err != nil {
+			return helpers.ResponseErrorBadRequest(c, "Invalid date format. Use YYYY-MM-DD", nil)
+		}
+		if err := o.OvertimeService.GetRecordByDateByTelegramID(query.TelegramID, date, c, tx); err != nil {
+			helpers.MyLogger("error", "OvertimeManagement", "GetOvertimeRecords", "controller", "error get overtime record by date", map[string]interface{}{
+				"error": err.Error(),
+			}, c)
+			return helpers.ResponseErrorInternal(c, err)
+		}
+		return nil
+	}
+
+	startDate, err := helpers.ParseDateWithTimezone(query.StartDate)
+	if err != nil {
+		return helpers.ResponseErrorBadRequest(c, "Invalid start date format. Use YYYY-MM-DD", nil)
+	}
+	endDate, err := helpers.ParseDateWithTimezone(query.EndDate)
+	if err != nil {
+		return helpers.ResponseErrorBadRequest(c, "Invalid end date format. Use YYYY-MM-DD", nil)
+	}
+	if endDate.Before(startDate) {
+		return helpers.ResponseErrorBadRequest(c, "End date must be after start date", nil)
+	}
+	if err := o.OvertimeService.GetRecordBetweenDateByTelegramId(query.TelegramID, startDate, endDate, c, tx); err != nil {
+		helpers.MyLogger("error", "OvertimeManagement", "GetOvertimeRecords", "controller", "error get overtime records between dates", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return helpers.ResponseErrorInternal(c, err)
+	}
+	return nil
+}
+
 // GetRecordByDateByTelegramID godoc
 // @Summary Get Overtime Record by Date
-// @Description Get overtime record for a specific date and telegram ID
+// @Description Get overtime record for a specific date and telegram ID. Deprecated, use GET /v1/overtime/?telegram_id=&date=
+// @Deprecated
 // @Tags Overtime
 // @Accept json
 // @Produce json
@@ -134,7 +245,8 @@ func (o *OvertimeController) GetRecordByDateByTelegramID(c *fiber.Ctx) error {
 	return nil
 }
 
-// GetRecordBetweenDateByTelegramId retrieves overtime records between two dates for a telegram user
+// GetRecordBetweenDateByTelegramId retrieves overtime records between two dates for a telegram user.
+// Deprecated: use GetOvertimeRecords (GET /v1/overtime/?telegram_id=&start_date=&end_date=).
 func (o *OvertimeController) GetRecordBetweenDateByTelegramId(c *fiber.Ctx) error {
 	helpers.MyLogger("debug", "OvertimeManagement", "GetRecordBetweenDateByTelegramId", "controller", "start get overtime records between dates", nil, c)
 
@@ -0,0 +1,67 @@
+package controllers
+
+import (
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/payloads"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/database"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/helpers"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/timesheet"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ExportTimesheet godoc
+// @Summary Export Overtime Timesheet
+// @Description Download the timesheet of a telegram user over a period as CSV, XLSX or PDF: employee name, period header, one line per record, total and subtotals per category. The PDF ends with signature lines for the employee and HR
+// @Tags Overtime
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Produce application/pdf
+// @Param telegram_id query int true "Telegram ID"
+// @Param start_date query string true "Start date (YYYY-MM-DD)"
+// @Param end_date query string true "End date (YYYY-MM-DD)"
+// @Param format query string false "csv (default), xlsx or pdf"
+// @Success 200 {file} file "Timesheet file"
+// @Failure 400 {object} map[string]interface{} "Invalid query"
+// @Failure 404 {object} map[string]interface{} "Telegram user not found"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/overtime/export [get]
+func (o *OvertimeController) ExportTimesheet(c *fiber.Ctx) error {
+	helpers.MyLogger("debug", "OvertimeManagement", "ExportTimesheet", "controller", "start export timesheet", nil, c)
+
+	var query payloads.GetOvertimeExportQuery
+	if err := c.QueryParser(&query); err != nil {
+		helpers.MyLogger("error", "OvertimeManagement", "ExportTimesheet", "controller", "error parse query", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return helpers.ResponseErrorBadRequest(c, "Invalid query", nil)
+	}
+	if err := helpers.ValidateStruct(&query); err != nil {
+		if customErr, ok := err.(helpers.Error); ok {
+			return helpers.ResponseErrorBadRequest(c, customErr.Message, customErr.Data)
+		}
+		return helpers.ResponseErrorBadRequest(c, "Invalid query", nil)
+	}
+	if query.Format == "" {
+		query.Format = timesheet.FormatCSV
+	}
+
+	startDate, err := helpers.ParseDateWithTimezone(query.StartDate)
+	if err != nil {
+		return helpers.ResponseErrorBadRequest(c, "Invalid start date format. Use YYYY-MM-DD", nil)
+	}
+	endDate, err := helpers.ParseDateWithTimezone(query.EndDate)
+	if err != nil {
+		return helpers.ResponseErrorBadRequest(c, "Invalid end date format. Use YYYY-MM-DD", nil)
+	}
+	if endDate.Before(startDate) {
+		return helpers.ResponseErrorBadRequest(c, "End date must be after start date", nil)
+	}
+
+	if err := o.OvertimeService.ExportTimesheet(query.TelegramID, startDate, endDate, query.Format, c, database.ClientPostgres); err != nil {
+		helpers.MyLogger("error", "OvertimeManagement", "ExportTimesheet", "controller", "error export timesheet", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return helpers.ResponseErrorInternal(c, err)
+	}
+	return nil
+}
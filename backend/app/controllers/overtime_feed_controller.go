@@ -0,0 +1,131 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/database"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/helpers"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+type OvertimeFeedController struct {
+	OvertimeFeedService services.OvertimeFeedService
+}
+
+// GetOvertimeFeed godoc
+// @Summary Overtime Calendar Feed
+// @Description iCalendar feed of the overtime records of one telegram user over the last 12 months, for subscribing in Google Calendar, Outlook or Apple Calendar. The token in the path is the only authentication
+// @Tags Overtime Feed
+// @Produce text/calendar
+// @Param token path string true "Feed token"
+// @Success 200 {file} file "iCalendar feed"
+// @Failure 404 {object} map[string]interface{} "Calendar feed not found"
+// @Router /v1/overtime/feed/{token}.ics [get]
+func (f *OvertimeFeedController) GetOvertimeFeed(c *fiber.Ctx) error {
+	helpers.MyLogger("debug", "OvertimeFeed", "GetOvertimeFeed", "controller", "start get overtime feed", nil, c)
+
+	token := c.Params("token")
+	if token == "" {
+		return helpers.Response(c, fiber.StatusNotFound, "Calendar feed not found", nil)
+	}
+
+	if err := f.OvertimeFeedService.RenderFeed(token, c, database.ClientPostgres); err != nil {
+		helpers.MyLogger("error", "OvertimeFeed", "GetOvertimeFeed", "controller", "error render overtime feed", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return helpers.ResponseErrorInternal(c, err)
+	}
+	return nil
+}
+
+// GetFeedToken godoc
+// @Summary Get Calendar Feed Status
+// @Description Whether the calendar feed of a telegram user is active, when it was rotated and last fetched. Only the linked user or an admin can see it
+// @Tags Overtime Feed
+// @Produce json
+// @Param telegram_id path int true "Telegram ID"
+// @Success 200 {object} map[string]interface{} "Calendar feed retrieved successfully"
+// @Failure 404 {object} map[string]interface{} "Telegram user not found"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/overtime/feed/{telegram_id} [get]
+func (f *OvertimeFeedController) GetFeedToken(c *fiber.Ctx) error {
+	helpers.MyLogger("debug", "OvertimeFeed", "GetFeedToken", "controller", "start get feed token", nil, c)
+
+	telegramID, err := strconv.ParseInt(c.Params("telegram_id"), 10, 64)
+	if err != nil {
+		return helpers.ResponseErrorBadRequest(c, "Invalid telegram ID", nil)
+	}
+
+	tx := database.ClientPostgres
+	if err := f.OvertimeFeedService.GetFeedToken(telegramID, c, tx); err != nil {
+		helpers.MyLogger("error", "OvertimeFeed", "GetFeedToken", "controller", "error get feed token", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return helpers.ResponseErrorInternal(c, err)
+	}
+	return nil
+}
+
+// RotateFeedToken godoc
+// @Summary Create or Rotate Calendar Feed Token
+// @Description Create a new feed token for a telegram user and return the subscribe URL. The token is shown only once, the previous URL stops working immediately
+// @Tags Overtime Feed
+// @Produce json
+// @Param telegram_id path int true "Telegram ID"
+// @Success 201 {object} map[string]interface{} "Calendar feed token created successfully"
+// @Failure 404 {object} map[string]interface{} "Telegram user not found"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/overtime/feed/{telegram_id}/token [post]
+func (f *OvertimeFeedController) RotateFeedToken(c *fiber.Ctx) error {
+	helpers.MyLogger("debug", "OvertimeFeed", "RotateFeedToken", "controller", "start rotate feed token", nil, c)
+
+	telegramID, err := strconv.ParseInt(c.Params("telegram_id"), 10, 64)
+	if err != nil {
+		return helpers.ResponseErrorBadRequest(c, "Invalid telegram ID", nil)
+	}
+
+	tx := database.ClientPostgres.Begin()
+	defer tx.Rollback()
+
+	if err := f.OvertimeFeedService.RotateFeedToken(telegramID, c, tx); err != nil {
+		helpers.MyLogger("error", "OvertimeFeed", "RotateFeedToken", "controller", "error rotate feed token", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return helpers.ResponseErrorInternal(c, err)
+	}
+	return nil
+}
+
+// RevokeFeedToken godoc
+// @Summary Revoke Calendar Feed Token
+// @Description Delete the feed token of a telegram user, calendar apps subscribed to the old URL get 404
+// @Tags Overtime Feed
+// @Produce json
+// @Param telegram_id path int true "Telegram ID"
+// @Success 200 {object} map[string]interface{} "Calendar feed token revoked successfully"
+// @Failure 404 {object} map[string]interface{} "Telegram user not found or calendar feed is not active"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/overtime/feed/{telegram_id}/token [delete]
+func (f *OvertimeFeedController) RevokeFeedToken(c *fiber.Ctx) error {
+	helpers.MyLogger("debug", "OvertimeFeed", "RevokeFeedToken", "controller", "start revoke feed token", nil, c)
+
+	telegramID, err := strconv.ParseInt(c.Params("telegram_id"), 10, 64)
+	if err != nil {
+		return helpers.ResponseErrorBadRequest(c, "Invalid telegram ID", nil)
+	}
+
+	tx := database.ClientPostgres.Begin()
+	defer tx.Rollback()
+
+	if err := f.OvertimeFeedService.RevokeFeedToken(telegramID, c, tx); err != nil {
+		helpers.MyLogger("error", "OvertimeFeed", "RevokeFeedToken", "controller", "error revoke feed token", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return helpers.ResponseErrorInternal(c, err)
+	}
+	return nil
+}
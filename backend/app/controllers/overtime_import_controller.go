@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/database"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/helpers"
+	"github.com/gofiber/fiber/v2"
+)
+
+// maxOvertimeImportFileSize limits uploaded overtime CSV files, about 10.000 rows
+const maxOvertimeImportFileSize = 1 << 20
+
+// importFlag reads a boolean form or query value, empty = false
+func importFlag(c *fiber.Ctx, key string) (bool, error) {
+	value := strings.TrimSpace(c.FormValue(key))
+	if value == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(value)
+}
+
+// ImportRecordOvertime godoc
+// @Summary Import Overtime Records
+// @Description Upload a CSV file with the header date,time_start,time_stop,break,duration,category,description,telegram_id (any order, break, duration, category and description optional). Every row is validated like POST /v1/overtime and all rows are saved in one transaction. dry_run only reports the per-row errors, skip_invalid saves the valid rows instead of rejecting the file
+// @Tags Overtime
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Overtime CSV file (max 1 MB)"
+// @Param dry_run formData bool false "Validate without saving"
+// @Param skip_invalid formData bool false "Save the valid rows and skip the invalid ones"
+// @Success 200 {object} map[string]interface{} "Dry run finished, nothing was saved"
+// @Success 201 {object} map[string]interface{} "Overtime records imported successfully"
+// @Failure 400 {object} map[string]interface{} "Missing file or invalid flag"
+// @Failure 422 {object} map[string]interface{} "Invalid file or invalid rows"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/overtime/import [post]
+func (o *OvertimeController) ImportRecordOvertime(c *fiber.Ctx) error {
+	helpers.MyLogger("debug", "OvertimeManagement", "ImportRecordOvertime", "controller", "start import overtime records", nil, c)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return helpers.ResponseErrorBadRequest(c, "File is required (multipart field \"file\")", nil)
+	}
+	if fileHeader.Size > maxOvertimeImportFileSize {
+		return helpers.ResponseErrorBadRequest(c, "File is too large, maximum 1 MB", nil)
+	}
+
+	dryRun, err := importFlag(c, "dry_run")
+	if err != nil {
+		return helpers.ResponseErrorBadRequest(c, "dry_run must be true or false", nil)
+	}
+	skipInvalid, err := importFlag(c, "skip_invalid")
+	if err != nil {
+		return helpers.ResponseErrorBadRequest(c, "skip_invalid must be true or false", nil)
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		helpers.MyLogger("error", "OvertimeManagement", "ImportRecordOvertime", "controller", "error open uploaded file", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return helpers.ResponseErrorInternal(c, err)
+	}
+	defer file.Close()
+
+	tx := database.ClientPostgres.Begin()
+	defer tx.Rollback()
+
+	if err := o.OvertimeService.ImportRecordOvertime(file, dryRun, skipInvalid, c, tx); err != nil {
+		helpers.MyLogger("error", "OvertimeManagement", "ImportRecordOvertime", "controller", "error import overtime records", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return helpers.ResponseErrorInternal(c, err)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/payloads"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/database"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/helpers"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+type OvertimeLimitController struct {
+	OvertimeLimitService services.OvertimeLimitService
+}
+
+// GetOvertimeLimit godoc
+// @Summary Get Overtime Limit
+// @Description Get the default, per-employee override and effective daily, weekly and monthly overtime limits of a telegram user (role approver or admin). Defaults follow PP 35/2021: 4 hours a day and 18 hours a week
+// @Tags Overtime Limit
+// @Produce json
+// @Param telegram_id path int true "Telegram ID"
+// @Success 200 {object} map[string]interface{} "Overtime limit retrieved successfully"
+// @Failure 403 {object} map[string]interface{} "Insufficient role"
+// @Failure 404 {object} map[string]interface{} "Telegram user not found"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/overtime/limits/{telegram_id} [get]
+func (l *OvertimeLimitController) GetOvertimeLimit(c *fiber.Ctx) error {
+	helpers.MyLogger("debug", "OvertimeLimit", "GetOvertimeLimit", "controller", "start get overtime limit", nil, c)
+
+	telegramID, err := strconv.ParseInt(c.Params("telegram_id"), 10, 64)
+	if err != nil {
+		return helpers.ResponseErrorBadRequest(c, "Invalid telegram ID", nil)
+	}
+
+	tx := database.ClientPostgres
+	if err := l.OvertimeLimitService.GetOvertimeLimit(telegramID, c, tx); err != nil {
+		helpers.MyLogger("error", "OvertimeLimit", "GetOvertimeLimit", "controller", "error get overtime limit", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return helpers.ResponseErrorInternal(c, err)
+	}
+	return nil
+}
+
+// UpsertOvertimeLimit godoc
+// @Summary Create or Replace Overtime Limit
+// @Description Override the overtime limits and the policy mode (warn, require_approval or block) of a telegram user (role approver or admin). A null field uses the default, 0 disables that limit
+// @Tags Overtime Limit
+// @Accept json
+// @Produce json
+// @Param telegram_id path int true "Telegram ID"
+// @Param upsertOvertimeLimitPayload body payloads.UpsertOvertimeLimitPayload true "Overtime limit"
+// @Success 200 {object} map[string]interface{} "Overtime limit saved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request body"
+// @Failure 403 {object} map[string]interface{} "Insufficient role"
+// @Failure 404 {object} map[string]interface{} "Telegram user not found"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/overtime/limits/{telegram_id} [put]
+func (l *OvertimeLimitController) UpsertOvertimeLimit(c *fiber.Ctx) error {
+	helpers.MyLogger("debug", "OvertimeLimit", "UpsertOvertimeLimit", "controller", "start upsert overtime limit", nil, c)
+
+	telegramID, err := strconv.ParseInt(c.Params("telegram_id"), 10, 64)
+	if err != nil {
+		return helpers.ResponseErrorBadRequest(c, "Invalid telegram ID", nil)
+	}
+
+	var payload payloads.UpsertOvertimeLimitPayload
+	if err := helpers.ValidateBody(&payload, c); err != nil {
+		helpers.MyLogger("error", "OvertimeLimit", "UpsertOvertimeLimit", "controller", "error validate body", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		if customErr, ok := err.(helpers.Error); ok {
+			return helpers.ResponseErrorBadRequest(c, customErr.Message, customErr.Data)
+		}
+		return helpers.ResponseErrorBadRequest(c, "Invalid payload", nil)
+	}
+
+	tx := database.ClientPostgres.Begin()
+	defer tx.Rollback()
+
+	if err := l.OvertimeLimitService.UpsertOvertimeLimit(telegramID, &payload, c, tx); err != nil {
+		helpers.MyLogger("error", "OvertimeLimit", "UpsertOvertimeLimit", "controller", "error upsert overtime limit", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return helpers.ResponseErrorInternal(c, err)
+	}
+	return nil
+}
+
+// DeleteOvertimeLimit godoc
+// @Summary Delete Overtime Limit
+// @Description Remove the overtime limit override of a telegram user so the default limits apply again (role approver or admin)
+// @Tags Overtime Limit
+// @Produce json
+// @Param telegram_id path int true "Telegram ID"
+// @Success 200 {object} map[string]interface{} "Overtime limit deleted successfully"
+// @Failure 403 {object} map[string]interface{} "Insufficient role"
+// @Failure 404 {object} map[string]interface{} "Telegram user or override not found"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/overtime/limits/{telegram_id} [delete]
+func (l *OvertimeLimitController) DeleteOvertimeLimit(c *fiber.Ctx) error {
+	helpers.MyLogger("debug", "OvertimeLimit", "DeleteOvertimeLimit", "controller", "start delete overtime limit", nil, c)
+
+	telegramID, err := strconv.ParseInt(c.Params("telegram_id"), 10, 64)
+	if err != nil {
+		return helpers.ResponseErrorBadRequest(c, "Invalid telegram ID", nil)
+	}
+
+	tx := database.ClientPostgres.Begin()
+	defer tx.Rollback()
+
+	if err := l.OvertimeLimitService.DeleteOvertimeLimit(telegramID, c, tx); err != nil {
+		helpers.MyLogger("error", "OvertimeLimit", "DeleteOvertimeLimit", "controller", "error delete overtime limit", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return helpers.ResponseErrorInternal(c, err)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/payloads"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/database"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/helpers"
+	"github.com/gofiber/fiber/v2"
+)
+
+// maxSummaryTelegramIDs limits how many telegram users one summary request can aggregate
+const maxSummaryTelegramIDs = 50
+
+// GetOvertimeSummary godoc
+// @Summary Get Overtime Summary
+// @Description Total duration, total break, record count and averages of one or more telegram users, grouped per day, week (Monday to Sunday), month or category. Dates are calendar days in TIMEZONE
+// @Tags Overtime
+// @Produce json
+// @Param telegram_id query []int true "Telegram ID, repeat or comma separate for more users (max 50)" collectionFormat(multi)
+// @Param group_by query string false "day, week, month or category (default day)"
+// @Param start_date query string true "Start date (YYYY-MM-DD)"
+// @Param end_date query string true "End date (YYYY-MM-DD)"
+// @Param status query string false "Only records with this status"
+// @Success 200 {object} map[string]interface{} "Overtime summary retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid query"
+// @Failure 404 {object} map[string]interface{} "Telegram user not found"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/overtime/summary [get]
+func (o *OvertimeController) GetOvertimeSummary(c *fiber.Ctx) error {
+	helpers.MyLogger("debug", "OvertimeManagement", "GetOvertimeSummary", "controller", "start get overtime summary", nil, c)
+
+	var query payloads.GetOvertimeSummaryQuery
+	if err := c.QueryParser(&query); err != nil {
+		helpers.MyLogger("error", "OvertimeManagement", "GetOvertimeSummary", "controller", "error parse query", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return helpers.ResponseErrorBadRequest(c, "Invalid query", nil)
+	}
+	if err := helpers.ValidateStruct(&query); err != nil {
+		if customErr, ok := err.(helpers.Error); ok {
+			return helpers.ResponseErrorBadRequest(c, customErr.Message, customErr.Data)
+		}
+		return helpers.ResponseErrorBadRequest(c, "Invalid query", nil)
+	}
+
+	var telegramIDs []int64
+	seen := map[int64]bool{}
+	for _, value := range query.TelegramIDs {
+		for _, part := range strings.Split(value, ",") {
+			telegramID, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+			if err != nil {
+				return helpers.ResponseErrorBadRequest(c, "Invalid telegram ID: "+part, nil)
+			}
+			if !seen[telegramID] {
+				seen[telegramID] = true
+				telegramIDs = append(telegramIDs, telegramID)
+			}
+		}
+	}
+	if len(telegramIDs) > maxSummaryTelegramIDs {
+		return helpers.ResponseErrorBadRequest(c, "Too many telegram IDs, maximum "+strconv.Itoa(maxSummaryTelegramIDs), nil)
+	}
+
+	startDate, err := helpers.ParseDateWithTimezone(query.StartDate)
+	if err != nil {
+		return helpers.ResponseErrorBadRequest(c, "Invalid start date format. Use YYYY-MM-DD", nil)
+	}
+	endDate, err := helpers.ParseDateWithTimezone(query.EndDate)
+	if err != nil {
+		return helpers.ResponseErrorBadRequest(c, "Invalid end date format. Use YYYY-MM-DD", nil)
+	}
+	if endDate.Before(startDate) {
+		return helpers.ResponseErrorBadRequest(c, "End date must be after start date", nil)
+	}
+	groupBy := query.GroupBy
+	if groupBy == "" {
+		groupBy = "day"
+	}
+
+	tx := database.ClientPostgres
+	if err := o.OvertimeService.GetOvertimeSummary(telegramIDs, startDate, endDate, groupBy, query.Status, c, tx); err != nil {
+		helpers.MyLogger("error", "OvertimeManagement", "GetOvertimeSummary", "controller", "error get overtime summary", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return helpers.ResponseErrorInternal(c, err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/payloads"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/database"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/helpers"
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetTrashByTelegramID godoc
+// @Summary Get Overtime Trash
+// @Description List the deleted overtime records of a telegram user that can still be restored, newest deletion first. The trash is purged after OVERTIME_TRASH_RETENTION_DAYS
+// @Tags Overtime
+// @Produce json
+// @Param telegram_id query int true "Telegram ID"
+// @Param limit query int false "Page size, default 20, max 100"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param sort query string false "deleted_at (default), id or date, prefix - for descending"
+// @Param order query string false "asc or desc (default)"
+// @Success 200 {object} map[string]interface{} "Overtime trash retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid query or pagination"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/overtime/trash [get]
+func (o *OvertimeController) GetTrashByTelegramID(c *fiber.Ctx) error {
+	helpers.MyLogger("debug", "OvertimeManagement", "GetTrashByTelegramID", "controller", "start get overtime trash", nil, c)
+
+	var query payloads.GetOvertimeTrashQuery
+	if err := c.QueryParser(&query); err != nil {
+		helpers.MyLogger("error", "OvertimeManagement", "GetTrashByTelegramID", "controller", "error parse query", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return helpers.ResponseErrorBadRequest(c, "Invalid query", nil)
+	}
+	if err := helpers.ValidateStruct(&query); err != nil {
+		if customErr, ok := err.(helpers.Error); ok {
+			return helpers.ResponseErrorBadRequest(c, customErr.Message, customErr.Data)
+		}
+		return helpers.ResponseErrorBadRequest(c, "Invalid query", nil)
+	}
+
+	if err := o.OvertimeService.GetTrashByTelegramID(&query, c, database.ClientPostgres); err != nil {
+		helpers.MyLogger("error", "OvertimeManagement", "GetTrashByTelegramID", "controller", "error get overtime trash", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return helpers.ResponseErrorInternal(c, err)
+	}
+	return nil
+}
+
+// RestoreRecordOvertime godoc
+// @Summary Restore Overtime Record
+// @Description Take a deleted overtime record out of the trash. Fails when another record now overlaps it or when it exceeds a limit in block mode
+// @Tags Overtime
+// @Produce json
+// @Param id path int true "Overtime record ID"
+// @Success 200 {object} map[string]interface{} "Overtime record restored successfully"
+// @Failure 404 {object} map[string]interface{} "Overtime record not found in trash"
+// @Failure 409 {object} map[string]interface{} "Overtime overlaps with existing records"
+// @Failure 422 {object} map[string]interface{} "Overtime exceeds the limit"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/overtime/{id}/restore [post]
+func (o *OvertimeController) RestoreRecordOvertime(c *fiber.Ctx) error {
+	helpers.MyLogger("debug", "OvertimeManagement", "RestoreRecordOvertime", "controller", "start restore overtime record", nil, c)
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil || id == 0 {
+		return helpers.ResponseErrorBadRequest(c, "Invalid overtime ID", nil)
+	}
+
+	tx := database.ClientPostgres.Begin()
+	defer tx.Rollback()
+
+	if err := o.OvertimeService.RestoreRecordOvertime(uint(id), c, tx); err != nil {
+		helpers.MyLogger("error", "OvertimeManagement", "RestoreRecordOvertime", "controller", "error restore overtime record", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return helpers.ResponseErrorInternal(c, err)
+	}
+	return nil
+}
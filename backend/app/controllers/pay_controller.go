@@ -0,0 +1,144 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/payloads"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/database"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/helpers"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+type PayController struct {
+	PayService services.PayService
+}
+
+// GetOvertimePay godoc
+// @Summary Calculate Overtime Pay
+// @Description Calculate pay of approved overtime records between two dates with a per-day breakdown. Default rates follow Kepmenakertrans 102/2004, hourly wage is 1/173 of the monthly salary. Users only see their own telegram users (others get 404) and monthly_salary is left out unless the caller is an approver or admin
+// @Tags Overtime Pay
+// @Produce json
+// @Param telegram_id query int true "Telegram ID"
+// @Param start_date query string true "Start date (YYYY-MM-DD)"
+// @Param end_date query string true "End date (YYYY-MM-DD)"
+// @Success 200 {object} map[string]interface{} "Overtime pay calculated successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid query"
+// @Failure 404 {object} map[string]interface{} "Telegram user or pay profile not found"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/overtime/pay [get]
+func (p *PayController) GetOvertimePay(c *fiber.Ctx) error {
+	helpers.MyLogger("debug", "OvertimePay", "GetOvertimePay", "controller", "start calculate overtime pay", nil, c)
+
+	var query payloads.GetOvertimePayQuery
+	if err := c.QueryParser(&query); err != nil {
+		helpers.MyLogger("error", "OvertimePay", "GetOvertimePay", "controller", "error parse query", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return helpers.ResponseErrorBadRequest(c, "Invalid query", nil)
+	}
+	if err := helpers.ValidateStruct(&query); err != nil {
+		if customErr, ok := err.(helpers.Error); ok {
+			return helpers.ResponseErrorBadRequest(c, customErr.Message, customErr.Data)
+		}
+		return helpers.ResponseErrorBadRequest(c, "Invalid query", nil)
+	}
+
+	startDate, err := helpers.ParseDateWithTimezone(query.StartDate)
+	if err != nil {
+		return helpers.ResponseErrorBadRequest(c, "Invalid start date format. Use YYYY-MM-DD", nil)
+	}
+	endDate, err := helpers.ParseDateWithTimezone(query.EndDate)
+	if err != nil {
+		return helpers.ResponseErrorBadRequest(c, "Invalid end date format. Use YYYY-MM-DD", nil)
+	}
+	if endDate.Before(startDate) {
+		return helpers.ResponseErrorBadRequest(c, "End date must be after start date", nil)
+	}
+
+	tx := database.ClientPostgres
+	if err := p.PayService.GetOvertimePay(query.TelegramID, startDate, endDate, c, tx); err != nil {
+		helpers.MyLogger("error", "OvertimePay", "GetOvertimePay", "controller", "error calculate overtime pay", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return helpers.ResponseErrorInternal(c, err)
+	}
+	return nil
+}
+
+// GetPayProfile godoc
+// @Summary Get Pay Profile
+// @Description Get the monthly salary, work week scheme and effective rate tiers of a telegram user (role approver or admin)
+// @Tags Overtime Pay
+// @Produce json
+// @Param telegram_id path int true "Telegram ID"
+// @Success 200 {object} map[string]interface{} "Pay profile retrieved successfully"
+// @Failure 403 {object} map[string]interface{} "Insufficient role"
+// @Failure 404 {object} map[string]interface{} "Telegram user or pay profile not found"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/overtime/pay/profile/{telegram_id} [get]
+func (p *PayController) GetPayProfile(c *fiber.Ctx) error {
+	helpers.MyLogger("debug", "OvertimePay", "GetPayProfile", "controller", "start get pay profile", nil, c)
+
+	telegramID, err := strconv.ParseInt(c.Params("telegram_id"), 10, 64)
+	if err != nil {
+		return helpers.ResponseErrorBadRequest(c, "Invalid telegram ID", nil)
+	}
+
+	tx := database.ClientPostgres
+	if err := p.PayService.GetPayProfile(telegramID, c, tx); err != nil {
+		helpers.MyLogger("error", "OvertimePay", "GetPayProfile", "controller", "error get pay profile", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return helpers.ResponseErrorInternal(c, err)
+	}
+	return nil
+}
+
+// UpsertPayProfile godoc
+// @Summary Create or Replace Pay Profile
+// @Description Set the monthly salary, work week scheme (5 or 6 days) and optional custom rate tiers of a telegram user (role approver or admin). Without tiers the Kepmenakertrans 102/2004 rates are used
+// @Tags Overtime Pay
+// @Accept json
+// @Produce json
+// @Param telegram_id path int true "Telegram ID"
+// @Param upsertPayProfilePayload body payloads.UpsertPayProfilePayload true "Pay profile"
+// @Success 200 {object} map[string]interface{} "Pay profile saved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request body"
+// @Failure 403 {object} map[string]interface{} "Insufficient role"
+// @Failure 422 {object} map[string]interface{} "Invalid pay profile"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/overtime/pay/profile/{telegram_id} [put]
+func (p *PayController) UpsertPayProfile(c *fiber.Ctx) error {
+	helpers.MyLogger("debug", "OvertimePay", "UpsertPayProfile", "controller", "start upsert pay profile", nil, c)
+
+	telegramID, err := strconv.ParseInt(c.Params("telegram_id"), 10, 64)
+	if err != nil {
+		return helpers.ResponseErrorBadRequest(c, "Invalid telegram ID", nil)
+	}
+
+	var payload payloads.UpsertPayProfilePayload
+	if err := helpers.ValidateBody(&payload, c); err != nil {
+		helpers.MyLogger("error", "OvertimePay", "UpsertPayProfile", "controller", "error validate body", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		if customErr, ok := err.(helpers.Error); ok {
+			return helpers.ResponseErrorBadRequest(c, customErr.Message, customErr.Data)
+		}
+		return helpers.ResponseErrorBadRequest(c, "Invalid payload", nil)
+	}
+
+	tx := database.ClientPostgres.Begin()
+	defer tx.Rollback()
+
+	if err := p.PayService.UpsertPayProfile(telegramID, &payload, c, tx); err != nil {
+		helpers.MyLogger("error", "OvertimePay", "UpsertPayProfile", "controller", "error upsert pay profile", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return helpers.ResponseErrorInternal(c, err)
+	}
+	return nil
+}
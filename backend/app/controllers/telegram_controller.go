@@ -14,24 +14,22 @@ type TelegramController struct {
 	TelegramService services.TelegramService
 }
 
+// CreateNewUserForNowUserActive used to link any posted telegram_id to the current user.
+// Linking now goes through CreateLinkCode and the bot, so the sender is verified by Telegram.
 func (t *TelegramController) CreateNewUserForNowUserActive(c *fiber.Ctx) error {
-	// helpers.Logger.Debug().Msg("TelegramController: CreateNewUserForNowUserActive validate body")
-	helpers.LogDebug("CreateNewUserForNowUserActive", "TelegramController: CreateNewUserForNowUserActive validate body", nil, c)
-	var payload payloads.CreateNewTelegramPayload
-	if err := helpers.ValidateBody(&payload, c); err != nil {
-		// helpers.Logger.Error().Err(err).Msg("TelegramController: CreateNewUserForNowUserActive Error validating body")
-		helpers.LogError(err, "CreateNewUserForNowUserActive", "TelegramController: CreateNewUserForNowUserActive Error validating body", nil, c)
-		if customErr, ok := err.(helpers.Error); ok {
-			return helpers.ResponseErrorBadRequest(c, customErr.Message, customErr.Data)
-		}
-	}
-	helpers.Logger.Debug().Msg("TelegramController: CreateNewUserForNowUserActive validate body success")
-	helpers.Logger.Debug().Msg("TelegramController: CreateNewUserForNowUserActive calling service")
+	helpers.MyLogger("info", "TelegramAccountLink", "CreateNewUserForNowUserActive", "controller", "rejected legacy link by telegram_id", nil, c)
+	return helpers.Response(c, fiber.StatusGone, "Linking by telegram_id is no longer supported, use POST /v1/telegram/link-code and open the returned link", nil)
+}
+
+func (t *TelegramController) CreateLinkCode(c *fiber.Ctx) error {
+	helpers.MyLogger("debug", "TelegramAccountLink", "CreateLinkCode", "controller", "start create link code", nil, c)
 	tx := database.ClientPostgres.Begin()
 	defer tx.Rollback()
 
-	if err := t.TelegramService.CreateNewUserForNowUserActive(&payload, c, tx); err != nil {
-		helpers.Logger.Error().Err(err).Msg("TelegramController: CreateNewUserForNowUserActive Error creating new user for now user active")
+	if err := t.TelegramService.CreateLinkCode(c, tx); err != nil {
+		helpers.MyLogger("error", "TelegramAccountLink", "CreateLinkCode", "controller", "error create link code", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
 		return helpers.ResponseErrorInternal(c, err)
 	}
 	return nil
@@ -57,26 +55,40 @@ func (t *TelegramController) DeleteByTelegramID(c *fiber.Ctx) error {
 	return nil
 }
 
-// find by user id
+// FindByUserID godoc
+// @Summary Get Telegram Accounts of Current User
+// @Description List the telegram accounts linked to the current user with cursor pagination and search
+// @Tags Telegram
+// @Produce json
+// @Param limit query int false "Page size, default 20, max 100"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param sort query string false "id (default), telegram_id or created_at, prefix - for descending"
+// @Param order query string false "asc or desc (default)"
+// @Param q query string false "Username, first or last name contains"
+// @Success 200 {object} map[string]interface{} "Telegram user found successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid query or pagination"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/telegram/ [get]
 func (t *TelegramController) FindByUserID(c *fiber.Ctx) error {
 	helpers.MyLogger("debug", "TelegramAccountLink", "FindByUserId", "controller", "start find telegram user by user ID", nil, c)
 	userID := helpers.GetCurrentUserID(c)
-	// parse userID to uint
-	// userIDInt, err := strconv.ParseUint(userID, 10, 32)
-	// if err != nil {
-	// 	helpers.MyLogger("error", "TelegramAccountLink", "FindByUserId", "controller", "error parse user ID", map[string]interface{}{
-	// 		"error": err.Error(),
-	// 	}, c)
-	// }
-	// tx := database.ClientPostgres.Begin()
-	// defer tx.Rollback()
 
-	// helpers.MyLogger("debug", "TelegramAccountLink", "FindByUserId", "controller", "start calling service for find telegram user by user ID", map[string]interface{}{
-	// 	"user_id":      userIDInt,
-	// 	"user_id_uint": uint(userIDInt),
-	// 	"user_id_str":  userID,
-	// }, c)
-	if err := t.TelegramService.FindByUserID(userID, c, database.ClientPostgres); err != nil {
+	var query payloads.GetTelegramUsersQuery
+	if err := c.QueryParser(&query); err != nil {
+		helpers.MyLogger("error", "TelegramAccountLink", "FindByUserId", "controller", "error parse query", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return helpers.ResponseErrorBadRequest(c, "Invalid query", nil)
+	}
+	if err := helpers.ValidateStruct(&query); err != nil {
+		if customErr, ok := err.(helpers.Error); ok {
+			return helpers.ResponseErrorBadRequest(c, customErr.Message, customErr.Data)
+		}
+		return helpers.ResponseErrorBadRequest(c, "Invalid query", nil)
+	}
+
+	if err := t.TelegramService.FindByUserID(userID, &query, c, database.ClientPostgres); err != nil {
 		helpers.MyLogger("error", "TelegramAccountLink", "FindByUserId", "controller", "error find telegram user by user ID", map[string]interface{}{
 			"error": err.Error(),
 		}, c)
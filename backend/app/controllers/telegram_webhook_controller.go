@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/bot"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/helpers"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/telegram"
+	"github.com/gofiber/fiber/v2"
+)
+
+type TelegramWebhookController struct {
+	Dispatcher *bot.Dispatcher
+}
+
+// ReceiveUpdate godoc
+// @Summary Telegram Bot API Webhook
+// @Description Receive updates pushed by Telegram. Requests must carry the X-Telegram-Bot-Api-Secret-Token header configured with setWebhook.
+// @Tags Telegram
+// @Accept json
+// @Produce json
+// @Param X-Telegram-Bot-Api-Secret-Token header string true "Webhook secret token"
+// @Success 200 {object} map[string]interface{} "Update accepted"
+// @Failure 400 {object} map[string]interface{} "Invalid update body"
+// @Failure 401 {object} map[string]interface{} "Invalid secret token"
+// @Router /v1/telegram/webhook [post]
+func (t *TelegramWebhookController) ReceiveUpdate(c *fiber.Ctx) error {
+	secret := helpers.GetEnv("TELEGRAM_WEBHOOK_SECRET", "")
+	headerSecret := c.Get("X-Telegram-Bot-Api-Secret-Token")
+	if secret == "" || subtle.ConstantTimeCompare([]byte(secret), []byte(headerSecret)) != 1 {
+		helpers.LogSecurity("telegram_webhook_invalid_secret", "anonymous", c.IP(), map[string]interface{}{
+			"path":              c.Path(),
+			"secret_configured": secret != "",
+		})
+		return helpers.Response(c, fiber.StatusUnauthorized, "Invalid secret token", nil)
+	}
+
+	var update telegram.Update
+	if err := json.Unmarshal(c.Body(), &update); err != nil || update.UpdateID == 0 {
+		helpers.LogTelegramWebhook("invalid_update", 0, map[string]interface{}{
+			"body_length": len(c.Body()),
+		})
+		return helpers.ResponseErrorBadRequest(c, "Invalid update", nil)
+	}
+
+	helpers.LogTelegramWebhook(update.Type(), update.UpdateID, map[string]interface{}{
+		"chat_id": update.ChatID(),
+	})
+
+	// Telegram mengirim ulang update jika response bukan 2xx, jadi error handler cukup dicatat
+	if err := t.Dispatcher.Dispatch(&update); err != nil {
+		helpers.LogTelegramError(err, "webhook_dispatch", update.ChatID(), 0, map[string]interface{}{
+			"update_id": update.UpdateID,
+		})
+		return helpers.ResponseErrorInternal(c, err)
+	}
+	return helpers.Response(c, fiber.StatusOK, "OK", nil)
+}
@@ -35,9 +35,36 @@ func (u *UserController) GetUserById(c *fiber.Ctx) error {
 	return nil
 }
 
+// GetAllUsers godoc
+// @Summary Get All Users
+// @Description List users with cursor pagination, role filter and search. The next page is requested with the next_cursor of the pagination object and the same sort
+// @Tags Users
+// @Produce json
+// @Param limit query int false "Page size, default 20, max 100"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param sort query string false "id (default), username or created_at, prefix - for descending"
+// @Param order query string false "asc or desc (default)"
+// @Param role query string false "user, approver or admin"
+// @Param q query string false "Username or email contains"
+// @Success 200 {object} map[string]interface{} "Users retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid query or pagination"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/user/ [get]
 func (u *UserController) GetAllUsers(c *fiber.Ctx) error {
+	var query payloads.GetUsersQuery
+	if err := c.QueryParser(&query); err != nil {
+		return helpers.ResponseErrorBadRequest(c, "Invalid query", nil)
+	}
+	if err := helpers.ValidateStruct(&query); err != nil {
+		if customErr, ok := err.(helpers.Error); ok {
+			return helpers.ResponseErrorBadRequest(c, customErr.Message, customErr.Data)
+		}
+		return helpers.ResponseErrorBadRequest(c, "Invalid query", nil)
+	}
+
 	tx := database.ClientPostgres
-	if err := u.UserService.GetAllUsers(c, tx); err != nil {
+	if err := u.UserService.GetAllUsers(&query, c, tx); err != nil {
 		return helpers.ResponseErrorInternal(c, err)
 	}
 
@@ -148,3 +175,38 @@ func (u *UserController) CreateApiKey(c *fiber.Ctx) error {
 
 	return nil
 }
+
+// UpdateUserRole godoc
+// @Summary Update User Role
+// @Description Change the role of a user (user, approver, admin). Admin only
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param updateUserRolePayload body payloads.UpdateUserRolePayload true "New role"
+// @Success 200 {object} map[string]interface{} "User role updated successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request body"
+// @Failure 403 {object} map[string]interface{} "Insufficient role"
+// @Failure 404 {object} map[string]interface{} "User not found"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /v1/user/{id}/role [put]
+func (u *UserController) UpdateUserRole(c *fiber.Ctx) error {
+	payload := payloads.UpdateUserRolePayload{}
+	if err := helpers.ValidateBody(&payload, c); err != nil {
+		helpers.LogError(err, "UpdateUserRole", "UserController: error when validating body", nil, c)
+		if customErr, ok := err.(helpers.Error); ok {
+			return helpers.ResponseErrorBadRequest(c, customErr.Message, customErr.Data)
+		}
+		return helpers.ResponseErrorBadRequest(c, "Invalid request body", nil)
+	}
+
+	tx := database.ClientPostgres.Begin()
+	defer tx.Rollback()
+
+	if err := u.UserService.UpdateUserRole(&payload, c, tx); err != nil {
+		helpers.LogError(err, "UpdateUserRole", "UserController: error when calling service.UpdateUserRole", nil, c)
+		return helpers.ResponseErrorInternal(c, err)
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+package entities
+
+import "time"
+
+// Category adalah kategori lembur. Nama unik tanpa membedakan huruf besar/kecil (indeks lower(name) di migrateConstraints)
+type Category struct {
+	ID            uint     `json:"id" gorm:"primaryKey"`
+	Name          string   `json:"name" gorm:"type:varchar(100);not null"`
+	Color         string   `json:"color" gorm:"type:varchar(7);not null;default:''"` // Format: #RRGGBB, kosong = bebas
+	Active        bool     `json:"active" gorm:"not null;default:true"`              // kategori nonaktif tidak bisa dipilih untuk lembur baru
+	PayMultiplier *float64 `json:"pay_multiplier" gorm:"type:decimal(4,2)"`          // null = pakai tarif bertingkat dari pay profile
+	// false = lembur di kategori ini langsung approved saat dibuat
+	RequiresApproval bool      `json:"requires_approval" gorm:"not null;default:true"`
+	CreatedAt        time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt        time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// tablename
+func (Category) TableName() string {
+	return "categories"
+}
@@ -0,0 +1,26 @@
+package entities
+
+import "time"
+
+// ConversationState menyimpan progres percakapan bot (wizard) per chat supaya restart backend tidak menghilangkan jawaban
+type ConversationState struct {
+	ChatID     int64     `json:"chat_id" gorm:"primaryKey;autoIncrement:false"`
+	TelegramID int64     `json:"telegram_id" gorm:"not null;index"`
+	Flow       string    `json:"flow" gorm:"type:varchar(50);not null"`
+	Step       string    `json:"step" gorm:"type:varchar(50);not null"`
+	Data       string    `json:"data" gorm:"type:text;not null;default:'{}'"`
+	MessageID  int64     `json:"message_id"`
+	ExpiresAt  time.Time `json:"expires_at" gorm:"not null;index"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// tablename
+func (ConversationState) TableName() string {
+	return "conversation_states"
+}
+
+// IsExpired reports whether the conversation can no longer be continued
+func (s *ConversationState) IsExpired(now time.Time) bool {
+	return !s.ExpiresAt.After(now)
+}
@@ -0,0 +1,36 @@
+package entities
+
+import "time"
+
+// Jenis hari libur: libur nasional atau cuti bersama
+const (
+	HolidayKindNational        = "national"
+	HolidayKindCollectiveLeave = "collective_leave"
+)
+
+// Sumber data hari libur
+const (
+	HolidaySourceManual = "manual"
+	HolidaySourceICS    = "ics"
+	HolidaySourceCSV    = "csv"
+)
+
+// Holiday adalah satu tanggal libur nasional atau cuti bersama, dipakai perhitungan upah lembur dan rekap
+type Holiday struct {
+	ID              uint      `json:"id" gorm:"primaryKey"`
+	Date            time.Time `json:"date" gorm:"type:date;not null;uniqueIndex"` // Format: YYYY-MM-DD
+	Name            string    `json:"name" gorm:"type:varchar(255);not null"`
+	Kind            string    `json:"kind" gorm:"type:varchar(20);not null;default:national"`
+	Source          string    `json:"source" gorm:"type:varchar(20);not null;default:manual"`
+	CreatedByUserID uint      `json:"created_by_user_id" gorm:"not null"`
+	CreatedAt       time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+
+	// Relasi
+	CreatedByUser User `json:"-" gorm:"foreignKey:CreatedByUserID"`
+}
+
+// tablename
+func (Holiday) TableName() string {
+	return "holidays"
+}
@@ -3,6 +3,17 @@ package entities
 import (
 	"encoding/json"
 	"time"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/limits"
+	"gorm.io/gorm"
+)
+
+// Status lembur: draft -> submitted -> approved/rejected, approved/rejected bisa dibuka lagi (reopen) menjadi draft
+const (
+	OvertimeStatusDraft     = "draft"
+	OvertimeStatusSubmitted = "submitted"
+	OvertimeStatusApproved  = "approved"
+	OvertimeStatusRejected  = "rejected"
 )
 
 type Overtime struct {
@@ -12,16 +23,35 @@ type Overtime struct {
 	TimeStart       time.Time `json:"-" gorm:"type:time;defualt:null"`                     // Format: HH:MM (custom JSON)
 	TimeStop        time.Time `json:"-" gorm:"type:time;defualt:null"`                     // Format: HH:MM (custom JSON)
 	BreakDuration   float64   `json:"break_duration" gorm:"type:decimal(4,2);default:0.0"` // Durasi istirahat dalam jam (1.5 = 1 jam 30 menit)
-	Duration        float64   `json:"duration" gorm:"type:decimal(4,2);default:0.0"`       // Durasi total lembur dikurangi break (manual input)
+	Duration        float64   `json:"duration" gorm:"type:decimal(4,2);default:0.0"`       // Durasi total lembur dikurangi break, dihitung server dari time_start, time_stop dan break_duration
 	Description     string    `json:"description" gorm:"type:text;default:null"`
-	Category        string    `json:"category" gorm:"type:varchar(255);default:Other"`
+	CategoryID      *uint     `json:"category_id" gorm:"index"` // null = tanpa kategori
+	Status          string    `json:"status" gorm:"type:varchar(20);not null;default:draft;index"`
 	CreatedByUserID uint      `json:"-" gorm:"not null"`
 	UpdatedAt       time.Time `json:"updated_at" gorm:"autoUpdateTime"`
 	CreatedAt       time.Time `json:"created_at" gorm:"autoCreateTime"`
+	// Soft delete: record yang dihapus masuk trash, bisa dipulihkan sampai dibersihkan job retensi
+	DeletedAt       gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+	DeletedByUserID *uint          `json:"deleted_by_user_id,omitempty"`
+
+	// Bukan kolom, diisi service dari tabel holidays dan hari dalam TIMEZONE
+	IsHoliday bool `json:"is_holiday" gorm:"-"`
+	IsWeekend bool `json:"is_weekend" gorm:"-"`
+	// Bukan kolom, diisi service saat create/update jika lembur melewati batas harian, mingguan atau bulanan
+	Warnings []limits.Violation `json:"warnings,omitempty" gorm:"-"`
 
 	// Relasi
 	User         User         `json:"-" gorm:"foreignKey:CreatedByUserID"`
 	TelegramUser TelegramUser `json:"-" gorm:"foreignKey:TelegramUserID"`
+	Category     *Category    `json:"-" gorm:"foreignKey:CategoryID;constraint:OnDelete:SET NULL"`
+}
+
+// CategoryName returns the name of the preloaded category, empty when there is none
+func (o Overtime) CategoryName() string {
+	if o.Category == nil {
+		return ""
+	}
+	return o.Category.Name
 }
 
 // Custom JSON marshaling for time-only fields
@@ -48,14 +78,36 @@ func (o Overtime) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&struct {
 		TimeStart string `json:"time_start"`
 		TimeStop  string `json:"time_stop"`
+		Category  string `json:"category"`
 		*Alias
 	}{
 		TimeStart: timeStartStr,
 		TimeStop:  timeStopStr,
+		Category:  o.CategoryName(),
 		Alias:     (*Alias)(&o),
 	})
 }
 
+// OvertimeRangeSQL is the time range of an overtime as a tstzrange. time_stop <= time_start ends on the next day.
+// Wall clock values are read as UTC, the range is only compared with ranges built the same way.
+// It is used by the overtimes_no_overlap exclusion constraint and by the overlap query, keep both in sync.
+// Both only look at rows that are not soft deleted, see OvertimeNotDeletedSQL.
+const OvertimeRangeSQL = "tstzrange((date + time_start) AT TIME ZONE 'UTC', " +
+	"(date + time_stop + CASE WHEN time_stop <= time_start THEN interval '1 day' ELSE interval '0' END) AT TIME ZONE 'UTC', '[)')"
+
+// OvertimeNotDeletedSQL is the predicate of the overtimes_no_overlap constraint, records in the trash do not block new ones
+const OvertimeNotDeletedSQL = "deleted_at IS NULL"
+
+// OvertimeRange returns the bounds of OvertimeRangeSQL for a date and clock times
+func OvertimeRange(date time.Time, timeStart time.Time, timeStop time.Time) (time.Time, time.Time) {
+	start := time.Date(date.Year(), date.Month(), date.Day(), timeStart.Hour(), timeStart.Minute(), timeStart.Second(), 0, time.UTC)
+	stop := time.Date(date.Year(), date.Month(), date.Day(), timeStop.Hour(), timeStop.Minute(), timeStop.Second(), 0, time.UTC)
+	if !stop.After(start) {
+		stop = stop.AddDate(0, 0, 1)
+	}
+	return start, stop
+}
+
 // tablename
 func (Overtime) TableName() string {
 	return "overtimes"
@@ -0,0 +1,32 @@
+package entities
+
+import "time"
+
+// Sumber lampiran lembur
+const (
+	AttachmentSourceAPI      = "api"
+	AttachmentSourceTelegram = "telegram"
+)
+
+// OvertimeAttachment adalah bukti lembur (foto, PDF) milik satu record, isinya disimpan di blobstore dengan StorageKey.
+// Hanya pemilik record, approver dan admin yang boleh mengunduh.
+type OvertimeAttachment struct {
+	ID               uint      `json:"id" gorm:"primaryKey"`
+	OvertimeID       uint      `json:"overtime_id" gorm:"not null;index"`
+	FileName         string    `json:"file_name" gorm:"type:varchar(255);not null"`
+	ContentType      string    `json:"content_type" gorm:"type:varchar(100);not null"`
+	Size             int64     `json:"size" gorm:"not null"`
+	SHA256           string    `json:"sha256" gorm:"column:sha256;type:varchar(64);not null"`
+	StorageKey       string    `json:"-" gorm:"type:varchar(255);not null;uniqueIndex"`
+	Source           string    `json:"source" gorm:"type:varchar(20);not null;default:api"`
+	UploadedByUserID uint      `json:"uploaded_by_user_id" gorm:"not null"`
+	CreatedAt        time.Time `json:"created_at" gorm:"autoCreateTime"`
+
+	// Relasi, lampiran ikut terhapus saat record lembur dihapus permanen
+	Overtime Overtime `json:"-" gorm:"foreignKey:OvertimeID;constraint:OnDelete:CASCADE"`
+}
+
+// tablename
+func (OvertimeAttachment) TableName() string {
+	return "overtime_attachments"
+}
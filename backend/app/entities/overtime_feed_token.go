@@ -0,0 +1,23 @@
+package entities
+
+import "time"
+
+// OvertimeFeedToken membuka feed iCalendar lembur satu karyawan lewat /v1/overtime/feed/<token>.ics tanpa login.
+// Hanya hash SHA-256 dari token yang disimpan, satu token aktif per telegram user; rotate menggantinya, revoke menghapusnya.
+type OvertimeFeedToken struct {
+	ID              uint       `json:"id" gorm:"primaryKey"`
+	TelegramUserID  uint       `json:"telegram_user_id" gorm:"not null;uniqueIndex"`
+	TokenHash       string     `json:"-" gorm:"type:varchar(64);not null;uniqueIndex"`
+	CreatedByUserID uint       `json:"created_by_user_id" gorm:"not null"`
+	LastUsedAt      *time.Time `json:"last_used_at"`
+	CreatedAt       time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+
+	// Relasi
+	TelegramUser TelegramUser `json:"-" gorm:"foreignKey:TelegramUserID;constraint:OnDelete:CASCADE"`
+}
+
+// tablename
+func (OvertimeFeedToken) TableName() string {
+	return "overtime_feed_tokens"
+}
@@ -0,0 +1,25 @@
+package entities
+
+import "time"
+
+// OvertimeLimit menimpa batas lembur default (env OVERTIME_LIMIT_*) untuk satu karyawan (telegram user)
+type OvertimeLimit struct {
+	ID             uint     `json:"id" gorm:"primaryKey"`
+	TelegramUserID uint     `json:"telegram_user_id" gorm:"not null;uniqueIndex"`
+	DailyHours     *float64 `json:"daily_hours" gorm:"type:decimal(5,2)"`   // null = pakai default, 0 = tanpa batas
+	WeeklyHours    *float64 `json:"weekly_hours" gorm:"type:decimal(5,2)"`  // null = pakai default, 0 = tanpa batas
+	MonthlyHours   *float64 `json:"monthly_hours" gorm:"type:decimal(5,2)"` // null = pakai default, 0 = tanpa batas
+	// Mode warn, require_approval atau block, kosong = pakai default
+	Mode            string    `json:"mode" gorm:"type:varchar(20);not null;default:''"`
+	UpdatedByUserID uint      `json:"updated_by_user_id" gorm:"not null"`
+	CreatedAt       time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+
+	// Relasi
+	TelegramUser TelegramUser `json:"-" gorm:"foreignKey:TelegramUserID;constraint:OnDelete:CASCADE"`
+}
+
+// tablename
+func (OvertimeLimit) TableName() string {
+	return "overtime_limits"
+}
@@ -0,0 +1,23 @@
+package entities
+
+import "time"
+
+// OvertimeStatusHistory mencatat setiap perubahan status lembur: siapa, kapan dan alasannya
+type OvertimeStatusHistory struct {
+	ID              uint      `json:"id" gorm:"primaryKey"`
+	OvertimeID      uint      `json:"overtime_id" gorm:"not null;index"`
+	FromStatus      string    `json:"from_status" gorm:"type:varchar(20);not null"`
+	ToStatus        string    `json:"to_status" gorm:"type:varchar(20);not null"`
+	Reason          string    `json:"reason" gorm:"type:text;not null;default:''"`
+	ChangedByUserID uint      `json:"changed_by_user_id" gorm:"not null"`
+	CreatedAt       time.Time `json:"created_at" gorm:"autoCreateTime"`
+
+	// Relasi
+	Overtime      Overtime `json:"-" gorm:"foreignKey:OvertimeID;constraint:OnDelete:CASCADE"`
+	ChangedByUser User     `json:"-" gorm:"foreignKey:ChangedByUserID"`
+}
+
+// tablename
+func (OvertimeStatusHistory) TableName() string {
+	return "overtime_status_histories"
+}
@@ -0,0 +1,25 @@
+package entities
+
+import "time"
+
+// PayProfile menyimpan gaji dan skema tarif lembur per karyawan (telegram user)
+type PayProfile struct {
+	ID             uint    `json:"id" gorm:"primaryKey"`
+	TelegramUserID uint    `json:"telegram_user_id" gorm:"not null;uniqueIndex"`
+	MonthlySalary  float64 `json:"monthly_salary" gorm:"type:decimal(15,2);not null"`
+	WorkWeekDays   int     `json:"work_week_days" gorm:"not null;default:5"`                     // 5 atau 6 hari kerja per minggu
+	HourlyDivisor  float64 `json:"hourly_divisor" gorm:"type:decimal(8,2);not null;default:173"` // upah sejam = gaji sebulan / divisor
+	// Tiers berisi JSON payroll.Tiers, kosong = tarif default Kepmenakertrans 102/2004
+	Tiers           string    `json:"-" gorm:"type:text;not null;default:''"`
+	UpdatedByUserID uint      `json:"updated_by_user_id" gorm:"not null"`
+	CreatedAt       time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+
+	// Relasi
+	TelegramUser TelegramUser `json:"-" gorm:"foreignKey:TelegramUserID;constraint:OnDelete:CASCADE"`
+}
+
+// tablename
+func (PayProfile) TableName() string {
+	return "pay_profiles"
+}
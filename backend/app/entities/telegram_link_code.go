@@ -0,0 +1,20 @@
+package entities
+
+import "time"
+
+// TelegramLinkCode adalah kode sekali pakai untuk menghubungkan akun Telegram lewat t.me/<bot>?start=<code>.
+// Hanya hash SHA-256 dari kode yang disimpan.
+type TelegramLinkCode struct {
+	ID               uint       `json:"id" gorm:"primaryKey"`
+	UserID           uint       `json:"user_id" gorm:"not null;index"`
+	CodeHash         string     `json:"-" gorm:"type:varchar(64);not null;uniqueIndex"`
+	ExpiresAt        time.Time  `json:"expires_at" gorm:"not null"`
+	UsedAt           *time.Time `json:"used_at"`
+	UsedByTelegramID *int64     `json:"used_by_telegram_id"`
+	CreatedAt        time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// tablename
+func (TelegramLinkCode) TableName() string {
+	return "telegram_link_codes"
+}
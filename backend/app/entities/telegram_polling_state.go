@@ -0,0 +1,15 @@
+package entities
+
+import "time"
+
+// TelegramPollingState menyimpan offset getUpdates terakhir per bot supaya restart tidak memproses ulang atau kehilangan update
+type TelegramPollingState struct {
+	BotID     int64     `json:"bot_id" gorm:"primaryKey;autoIncrement:false"`
+	Offset    int64     `json:"offset" gorm:"not null;default:0"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// tablename
+func (TelegramPollingState) TableName() string {
+	return "telegram_polling_states"
+}
@@ -0,0 +1,15 @@
+package entities
+
+import "time"
+
+// TelegramUpdate menyimpan update_id yang sudah diproses supaya update yang dikirim ulang oleh Telegram tidak diproses dua kali
+type TelegramUpdate struct {
+	UpdateID  int64     `json:"update_id" gorm:"primaryKey;autoIncrement:false"`
+	Type      string    `json:"type" gorm:"type:varchar(50);not null"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// tablename
+func (TelegramUpdate) TableName() string {
+	return "telegram_updates"
+}
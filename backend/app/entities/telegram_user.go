@@ -3,14 +3,26 @@ package entities
 import "time"
 
 type TelegramUser struct {
-	ID         uint      `json:"id" gorm:"primaryKey"`
-	UserID     uint      `json:"user_id" gorm:"not null"`
-	TelegramID int64     `json:"telegram_id" gorm:"uniqueIndex"`
-	Username   string    `json:"username" gorm:"type:varchar(255)"`
-	FirstName  string    `json:"first_name" gorm:"type:varchar(255)"`
-	LastName   string    `json:"last_name" gorm:"type:varchar(255)"`
-	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt  time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+	ID         uint   `json:"id" gorm:"primaryKey"`
+	UserID     uint   `json:"user_id" gorm:"not null"`
+	TelegramID int64  `json:"telegram_id" gorm:"uniqueIndex"`
+	Username   string `json:"username" gorm:"type:varchar(255)"`
+	FirstName  string `json:"first_name" gorm:"type:varchar(255)"`
+	LastName   string `json:"last_name" gorm:"type:varchar(255)"`
+
+	// Pengingat harian lembur, jam tenang dalam format HH:MM (kosong = tidak ada)
+	ReminderEnabled bool       `json:"reminder_enabled" gorm:"not null;default:false"`
+	QuietHoursStart string     `json:"quiet_hours_start" gorm:"type:varchar(5);not null;default:''"`
+	QuietHoursEnd   string     `json:"quiet_hours_end" gorm:"type:varchar(5);not null;default:''"`
+	LastReminderAt  *time.Time `json:"last_reminder_at"`
+
+	// Rekap otomatis mingguan dan bulanan
+	RecapEnabled       bool       `json:"recap_enabled" gorm:"not null;default:true"`
+	LastWeeklyRecapAt  *time.Time `json:"last_weekly_recap_at"`
+	LastMonthlyRecapAt *time.Time `json:"last_monthly_recap_at"`
+
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
 }
 
 // tablename
@@ -2,11 +2,19 @@ package entities
 
 import "time"
 
+// Role user aplikasi, approver dan admin boleh menyetujui atau menolak lembur
+const (
+	RoleUser     = "user"
+	RoleApprover = "approver"
+	RoleAdmin    = "admin"
+)
+
 type User struct {
 	ID           uint         `json:"id" gorm:"primaryKey"`
 	Username     string       `json:"username" gorm:"uniqueIndex"`
 	PasswordHash string       `json:"-" gorm:"not null"`
 	Email        string       `json:"email" gorm:"uniqueIndex"`
+	Role         string       `json:"role" gorm:"type:varchar(20);not null;default:user"`
 	CreatedAt    time.Time    `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt    time.Time    `json:"updated_at" gorm:"autoUpdateTime"`
 	APIKeys      []APIKey     `json:"-"` // relasi one-to-many
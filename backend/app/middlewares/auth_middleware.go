@@ -14,7 +14,7 @@ import (
 	"github.com/gofiber/fiber/v2/log"
 )
 
-// AuthMiddleware mendukung autentikasi via API Key atau JWT Token
+// AuthMiddleware mendukung autentikasi via API Key, JWT Token atau Telegram WebApp initData
 func AuthMiddleware() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		// Cek API Key terlebih dahulu
@@ -23,8 +23,11 @@ func AuthMiddleware() fiber.Handler {
 			return validateApiKey(c, apiKey)
 		}
 
-		// Jika tidak ada API Key, cek JWT Token
+		// Jika tidak ada API Key, cek Telegram initData atau JWT Token
 		authHeader := c.Get("Authorization")
+		if isTelegramWebAppAuth(authHeader) {
+			return validateTelegramWebApp(c, authHeader)
+		}
 		if authHeader != "" {
 			return validateJWT(c, authHeader)
 		}
@@ -116,6 +119,73 @@ func validateJWT(c *fiber.Ctx, authHeader string) error {
 	return c.Next()
 }
 
+// isTelegramWebAppAuth mengecek apakah header Authorization berisi "tma <initData>"
+func isTelegramWebAppAuth(authHeader string) bool {
+	return len(authHeader) > 4 && strings.EqualFold(authHeader[:4], "tma ")
+}
+
+// validateTelegramWebApp memvalidasi initData dari Telegram Mini App
+func validateTelegramWebApp(c *fiber.Ctx, authHeader string) error {
+	initData := strings.TrimSpace(authHeader[4:])
+	botToken := helpers.GetEnv("TELEGRAM_BOT_TOKEN", "")
+	if botToken == "" {
+		helpers.Logger.Error().Msg("TELEGRAM_BOT_TOKEN is not set, telegram webapp authentication is disabled")
+		return helpers.Response(c, fiber.StatusUnauthorized, "Telegram authentication is not configured", nil)
+	}
+
+	maxAge := helpers.GetTelegramInitDataMaxAge()
+	initDataParsed, err := helpers.ValidateTelegramInitData(initData, botToken, maxAge)
+	if err != nil {
+		helpers.LogAuth("telegram_webapp_authentication_failed", "anonymous", false, map[string]interface{}{
+			"error":      err.Error(),
+			"ip_address": c.IP(),
+			"user_agent": c.Get("User-Agent"),
+			"path":       c.Path(),
+		})
+		if err == helpers.ErrInitDataExpired {
+			return helpers.Response(c, fiber.StatusForbidden, "Init data expired", nil)
+		}
+		return helpers.Response(c, fiber.StatusForbidden, "Invalid init data", nil)
+	}
+
+	// Cari telegram user yang sudah terhubung ke user aplikasi
+	telegramRepository := repositories.TelegramRepository{}
+	userRepository := repositories.UserRepository{}
+	tx := database.ClientPostgres
+	telegramUser := entities.TelegramUser{}
+	if err := telegramRepository.FindByTelegramID(initDataParsed.User.ID, &telegramUser, c, tx); err != nil {
+		helpers.Logger.Info().Int64("telegram_id", initDataParsed.User.ID).Msg("Telegram user is not linked to any user")
+		return helpers.Response(c, fiber.StatusForbidden, "Telegram user not linked", nil)
+	}
+
+	user := entities.User{}
+	if err := userRepository.FindByID(telegramUser.UserID, &user, tx); err != nil {
+		return helpers.Response(c, fiber.StatusForbidden, "User not found", nil)
+	}
+
+	expireAt := initDataParsed.AuthDate.Add(maxAge)
+
+	// Set user info ke context
+	c.Locals("user_id", user.ID)
+	c.Locals("user", user)
+	c.Locals("auth_type", "telegram_webapp")
+	c.Locals("expired_at", expireAt)
+	c.Locals("expire_at", expireAt.Unix())
+	c.Locals("telegram_id", telegramUser.TelegramID)
+	c.Locals("telegram_user", telegramUser)
+
+	helpers.LogAuth("telegram_webapp_authentication_success", strconv.Itoa(int(user.ID)), true, map[string]interface{}{
+		"user":             user,
+		"telegram_id":      telegramUser.TelegramID,
+		"telegram_user_id": telegramUser.ID,
+		"auth_date":        initDataParsed.AuthDate.Format("2006-01-02 15:04:05"),
+		"ip_address":       c.IP(),
+		"user_agent":       c.Get("User-Agent"),
+		"path":             c.Path(),
+	})
+	return c.Next()
+}
+
 // OptionalAuthMiddleware untuk endpoint yang bisa diakses tanpa auth
 func OptionalAuthMiddleware() fiber.Handler {
 	return func(c *fiber.Ctx) error {
@@ -125,8 +195,11 @@ func OptionalAuthMiddleware() fiber.Handler {
 			return validateApiKey(c, apiKey)
 		}
 
-		// Cek JWT Token
+		// Cek Telegram initData atau JWT Token
 		authHeader := c.Get("Authorization")
+		if isTelegramWebAppAuth(authHeader) {
+			return validateTelegramWebApp(c, authHeader)
+		}
 		if authHeader != "" {
 			return validateJWT(c, authHeader)
 		}
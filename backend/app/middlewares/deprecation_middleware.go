@@ -0,0 +1,32 @@
+package middlewares
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/helpers"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Deprecated menandai route lama dengan header Deprecation (RFC 9745), Sunset (RFC 8594) dan Link ke route penggantinya.
+// Route tetap dilayani sampai dihapus, setiap pemanggilan dicatat supaya client yang belum pindah bisa dilacak.
+func Deprecated(deprecatedAt time.Time, sunsetAt time.Time, successor string) fiber.Handler {
+	deprecation := "@" + strconv.FormatInt(deprecatedAt.Unix(), 10)
+	sunset := sunsetAt.UTC().Format(http.TimeFormat)
+	link := "<" + successor + `>; rel="successor-version"`
+
+	return func(c *fiber.Ctx) error {
+		c.Set("Deprecation", deprecation)
+		c.Set("Sunset", sunset)
+		c.Append(fiber.HeaderLink, link)
+
+		helpers.MyLogger("warn", "Deprecation", "Deprecated", "middleware", "deprecated route called", map[string]interface{}{
+			"method":    c.Method(),
+			"path":      c.Path(),
+			"successor": successor,
+			"sunset":    sunset,
+		}, c)
+		return c.Next()
+	}
+}
@@ -2,6 +2,7 @@ package middlewares
 
 import (
 	"strconv"
+	"strings"
 
 	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/entities"
 	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/helpers"
@@ -37,12 +38,21 @@ func RequestLogMiddleware() fiber.Handler {
 		reqBody := ""
 		if c.Method() != "GET" && c.Method() != "HEAD" {
 			body := c.Body()
-			if len(body) > 0 {
+			if strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEMultipartForm) {
+				// upload file (lampiran, import CSV) tidak disimpan di log
+				reqBody = "[" + fiber.MIMEMultipartForm + " omitted]"
+			} else if len(body) > 0 {
 				reqBody = string(body)
 			}
 		}
 
-		respBody := string(c.Response().Body())
+		// Response file (lampiran, timesheet) tidak disimpan, Body() juga akan membaca seluruh stream download
+		respBody := ""
+		if contentType := string(c.Response().Header.ContentType()); strings.HasPrefix(contentType, fiber.MIMEApplicationJSON) || strings.HasPrefix(contentType, "text/") {
+			respBody = string(c.Response().Body())
+		} else {
+			respBody = "[" + contentType + " omitted]"
+		}
 
 		log := entities.LogRequest{
 			UserID:       userID,
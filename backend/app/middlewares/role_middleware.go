@@ -0,0 +1,31 @@
+package middlewares
+
+import (
+	"strconv"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/entities"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/helpers"
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequireRole membatasi route untuk user dengan salah satu role, dipasang setelah AuthMiddleware
+func RequireRole(roles ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		user, ok := c.Locals("user").(entities.User)
+		if !ok {
+			return helpers.Response(c, fiber.StatusUnauthorized, "Authentication required", nil)
+		}
+		for _, role := range roles {
+			if user.Role == role {
+				return c.Next()
+			}
+		}
+
+		helpers.LogAuth("role_authorization_failed", strconv.Itoa(int(user.ID)), false, map[string]interface{}{
+			"role":           user.Role,
+			"required_roles": roles,
+			"path":           c.Path(),
+		})
+		return helpers.Response(c, fiber.StatusForbidden, "Insufficient role", nil)
+	}
+}
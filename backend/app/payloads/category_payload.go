@@ -0,0 +1,59 @@
+package payloads
+
+import (
+	"github.com/go-playground/validator/v10"
+)
+
+type CreateCategoryPayload struct {
+	Name             string   `json:"name" validate:"required,max=100" example:"Deploy"`
+	Color            string   `json:"color" validate:"omitempty,max=7,hexcolor" example:"#1E88E5"`   // optional
+	Active           *bool    `json:"active"`                                                        // optional, default true
+	PayMultiplier    *float64 `json:"pay_multiplier" validate:"omitempty,gt=0,lte=10" example:"2.5"` // optional, null = tarif bertingkat pay profile
+	RequiresApproval *bool    `json:"requires_approval"`                                             // optional, default true
+}
+
+// UpdateCategoryPayload only changes the fields that are sent, clear_pay_multiplier removes the multiplier
+type UpdateCategoryPayload struct {
+	Name               string   `json:"name" validate:"omitempty,max=100"`
+	Color              *string  `json:"color" validate:"omitempty,max=7,hexcolor|len=0"`
+	Active             *bool    `json:"active"`
+	PayMultiplier      *float64 `json:"pay_multiplier" validate:"omitempty,gt=0,lte=10"`
+	ClearPayMultiplier bool     `json:"clear_pay_multiplier"`
+	RequiresApproval   *bool    `json:"requires_approval"`
+}
+
+type GetCategoriesQuery struct {
+	IncludeInactive bool `query:"include_inactive"`
+}
+
+func (p *CreateCategoryPayload) CustomErrorsMessage(errors validator.ValidationErrors) []map[string]string {
+	var errorMessages []map[string]string
+	for _, err := range errors {
+		field := err.Field()
+		switch field {
+		case "Name":
+			errorMessages = append(errorMessages, map[string]string{"name": "Name is required, maximum 100 characters"})
+		case "Color":
+			errorMessages = append(errorMessages, map[string]string{"color": "Color must be a hex color like #1E88E5"})
+		case "PayMultiplier":
+			errorMessages = append(errorMessages, map[string]string{"pay_multiplier": "Pay multiplier must be greater than 0 and at most 10"})
+		}
+	}
+	return errorMessages
+}
+
+func (p *UpdateCategoryPayload) CustomErrorsMessage(errors validator.ValidationErrors) []map[string]string {
+	var errorMessages []map[string]string
+	for _, err := range errors {
+		field := err.Field()
+		switch field {
+		case "Name":
+			errorMessages = append(errorMessages, map[string]string{"name": "Name must be at most 100 characters"})
+		case "Color":
+			errorMessages = append(errorMessages, map[string]string{"color": "Color must be a hex color like #1E88E5 or empty"})
+		case "PayMultiplier":
+			errorMessages = append(errorMessages, map[string]string{"pay_multiplier": "Pay multiplier must be greater than 0 and at most 10"})
+		}
+	}
+	return errorMessages
+}
@@ -0,0 +1,74 @@
+package payloads
+
+import (
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/calendar"
+	"github.com/go-playground/validator/v10"
+)
+
+type CreateHolidayPayload struct {
+	Date string `json:"date" validate:"required" example:"2024-08-17"`
+	Name string `json:"name" validate:"required,min=3,max=255" example:"Hari Kemerdekaan"`
+	Kind string `json:"kind" validate:"omitempty,oneof=national collective_leave"` // optional, default national
+}
+
+type UpdateHolidayPayload struct {
+	Date string `json:"date" example:"2024-08-17"`                                 // optional
+	Name string `json:"name" validate:"omitempty,min=3,max=255"`                   // optional
+	Kind string `json:"kind" validate:"omitempty,oneof=national collective_leave"` // optional
+}
+
+// GetHolidaysQuery is read from the query string, start_date/end_date take precedence over year (default this year)
+type GetHolidaysQuery struct {
+	Year      int    `query:"year" validate:"omitempty,min=1900,max=2999"`
+	StartDate string `query:"start_date"`
+	EndDate   string `query:"end_date"`
+}
+
+type ImportHolidaysResponse struct {
+	Format         string               `json:"format"`
+	Imported       int                  `json:"imported"`
+	DuplicateDates []string             `json:"duplicate_dates"`
+	Skipped        []calendar.LineError `json:"skipped"`
+}
+
+func (p *CreateHolidayPayload) CustomErrorsMessage(errors validator.ValidationErrors) []map[string]string {
+	var errorMessages []map[string]string
+	for _, err := range errors {
+		field := err.Field()
+		switch field {
+		case "Date":
+			errorMessages = append(errorMessages, map[string]string{"date": "Date is required"})
+		case "Name":
+			errorMessages = append(errorMessages, map[string]string{"name": "Name is required, 3-255 characters"})
+		case "Kind":
+			errorMessages = append(errorMessages, map[string]string{"kind": "Kind must be national or collective_leave"})
+		}
+	}
+	return errorMessages
+}
+
+func (p *UpdateHolidayPayload) CustomErrorsMessage(errors validator.ValidationErrors) []map[string]string {
+	var errorMessages []map[string]string
+	for _, err := range errors {
+		field := err.Field()
+		switch field {
+		case "Name":
+			errorMessages = append(errorMessages, map[string]string{"name": "Name must be 3-255 characters"})
+		case "Kind":
+			errorMessages = append(errorMessages, map[string]string{"kind": "Kind must be national or collective_leave"})
+		}
+	}
+	return errorMessages
+}
+
+func (p *GetHolidaysQuery) CustomErrorsMessage(errors validator.ValidationErrors) []map[string]string {
+	var errorMessages []map[string]string
+	for _, err := range errors {
+		field := err.Field()
+		switch field {
+		case "Year":
+			errorMessages = append(errorMessages, map[string]string{"year": "Year must be between 1900 and 2999"})
+		}
+	}
+	return errorMessages
+}
@@ -0,0 +1,80 @@
+package payloads
+
+import "github.com/go-playground/validator/v10"
+
+// ListQuery holds the cursor pagination and sorting parameters shared by the list endpoints
+type ListQuery struct {
+	Limit  int    `query:"limit"`  // default 20, max 100
+	Cursor string `query:"cursor"` // next_cursor of the previous page
+	Sort   string `query:"sort"`   // whitelisted column, "-date" sorts descending
+	Order  string `query:"order"`  // asc or desc
+}
+
+// GetOvertimeListQuery is read from the query string of GET /v1/overtime/telegram/{telegram_id}
+type GetOvertimeListQuery struct {
+	ListQuery
+	StartDate   string   `query:"start_date"` // Format: YYYY-MM-DD
+	EndDate     string   `query:"end_date"`   // Format: YYYY-MM-DD
+	Category    string   `query:"category" validate:"omitempty,max=100"`
+	CategoryID  *uint    `query:"category_id"` // 0 = records without category
+	MinDuration *float64 `query:"min_duration" validate:"omitempty,gte=0"`
+	MaxDuration *float64 `query:"max_duration" validate:"omitempty,gte=0"`
+	Status      string   `query:"status" validate:"omitempty,oneof=draft submitted approved rejected"`
+}
+
+// GetUsersQuery is read from the query string of GET /v1/user/
+type GetUsersQuery struct {
+	ListQuery
+	Role   string `query:"role" validate:"omitempty,oneof=user approver admin"`
+	Search string `query:"q" validate:"omitempty,max=100"` // username or email contains
+}
+
+// GetTelegramUsersQuery is read from the query string of GET /v1/telegram/
+type GetTelegramUsersQuery struct {
+	ListQuery
+	Search string `query:"q" validate:"omitempty,max=100"` // username, first or last name contains
+}
+
+func (p *GetOvertimeListQuery) CustomErrorsMessage(errors validator.ValidationErrors) []map[string]string {
+	var errorMessages []map[string]string
+	for _, err := range errors {
+		field := err.Field()
+		switch field {
+		case "Category":
+			errorMessages = append(errorMessages, map[string]string{"category": "Category must be at most 100 characters"})
+		case "MinDuration":
+			errorMessages = append(errorMessages, map[string]string{"min_duration": "Min duration must be greater than or equal to 0"})
+		case "MaxDuration":
+			errorMessages = append(errorMessages, map[string]string{"max_duration": "Max duration must be greater than or equal to 0"})
+		case "Status":
+			errorMessages = append(errorMessages, map[string]string{"status": "Status must be draft, submitted, approved or rejected"})
+		}
+	}
+	return errorMessages
+}
+
+func (p *GetUsersQuery) CustomErrorsMessage(errors validator.ValidationErrors) []map[string]string {
+	var errorMessages []map[string]string
+	for _, err := range errors {
+		field := err.Field()
+		switch field {
+		case "Role":
+			errorMessages = append(errorMessages, map[string]string{"role": "Role must be user, approver or admin"})
+		case "Search":
+			errorMessages = append(errorMessages, map[string]string{"q": "Search must be at most 100 characters"})
+		}
+	}
+	return errorMessages
+}
+
+func (p *GetTelegramUsersQuery) CustomErrorsMessage(errors validator.ValidationErrors) []map[string]string {
+	var errorMessages []map[string]string
+	for _, err := range errors {
+		field := err.Field()
+		switch field {
+		case "Search":
+			errorMessages = append(errorMessages, map[string]string{"q": "Search must be at most 100 characters"})
+		}
+	}
+	return errorMessages
+}
@@ -0,0 +1,32 @@
+package payloads
+
+import (
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/entities"
+	"github.com/go-playground/validator/v10"
+)
+
+type ChangeOvertimeStatusPayload struct {
+	Reason string `json:"reason" validate:"omitempty,max=500"` // optional
+}
+
+type OvertimeStatusChangeResponse struct {
+	Overtime entities.Overtime              `json:"overtime"`
+	History  entities.OvertimeStatusHistory `json:"history"`
+}
+
+type PendingOvertimeResponse struct {
+	Overtime     entities.Overtime     `json:"overtime"`
+	TelegramUser entities.TelegramUser `json:"telegram_user"`
+}
+
+func (p *ChangeOvertimeStatusPayload) CustomErrorsMessage(errors validator.ValidationErrors) []map[string]string {
+	var errorMessages []map[string]string
+	for _, err := range errors {
+		field := err.Field()
+		switch field {
+		case "Reason":
+			errorMessages = append(errorMessages, map[string]string{"reason": "Reason must be at most 500 characters"})
+		}
+	}
+	return errorMessages
+}
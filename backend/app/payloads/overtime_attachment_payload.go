@@ -0,0 +1,8 @@
+package payloads
+
+// AttachmentUpload is one evidence file sent through the multipart endpoint or to the bot.
+// The content type is sniffed from Data, the one declared by the client is not trusted.
+type AttachmentUpload struct {
+	FileName string
+	Data     []byte
+}
@@ -0,0 +1,39 @@
+package payloads
+
+import (
+	"github.com/go-playground/validator/v10"
+)
+
+// GetOvertimeExportQuery is read from the query string of GET /v1/overtime/export
+type GetOvertimeExportQuery struct {
+	TelegramID int64  `query:"telegram_id" validate:"required,gt=0"`
+	StartDate  string `query:"start_date" validate:"required,datetime=2006-01-02" example:"2024-03-01"`
+	EndDate    string `query:"end_date" validate:"required,datetime=2006-01-02" example:"2024-03-31"`
+	Format     string `query:"format" validate:"omitempty,oneof=csv xlsx pdf" example:"pdf"` // default csv
+}
+
+// ExportFile is a rendered timesheet, sent as a download by the API and as a document by the bot
+type ExportFile struct {
+	FileName    string
+	ContentType string
+	Data        []byte
+	Records     int
+}
+
+func (p *GetOvertimeExportQuery) CustomErrorsMessage(errors validator.ValidationErrors) []map[string]string {
+	var errorMessages []map[string]string
+	for _, err := range errors {
+		field := err.Field()
+		switch field {
+		case "TelegramID":
+			errorMessages = append(errorMessages, map[string]string{"telegram_id": "Telegram ID is required"})
+		case "StartDate":
+			errorMessages = append(errorMessages, map[string]string{"start_date": "Start date is required, format YYYY-MM-DD"})
+		case "EndDate":
+			errorMessages = append(errorMessages, map[string]string{"end_date": "End date is required, format YYYY-MM-DD"})
+		case "Format":
+			errorMessages = append(errorMessages, map[string]string{"format": "Format must be one of csv, xlsx, pdf"})
+		}
+	}
+	return errorMessages
+}
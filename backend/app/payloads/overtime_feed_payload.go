@@ -0,0 +1,20 @@
+package payloads
+
+import "time"
+
+// OvertimeFeedStatusResponse tells whether a telegram user has an active calendar feed, the token itself is never shown again
+type OvertimeFeedStatusResponse struct {
+	TelegramID int64      `json:"telegram_id"`
+	Active     bool       `json:"active"`
+	CreatedAt  *time.Time `json:"created_at"`
+	RotatedAt  *time.Time `json:"rotated_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+}
+
+// OvertimeFeedTokenResponse is returned once after a rotate, URL is the address to subscribe to in a calendar app
+type OvertimeFeedTokenResponse struct {
+	TelegramID int64     `json:"telegram_id"`
+	Token      string    `json:"token"`
+	URL        string    `json:"url" example:"https://lembur.example.com/v1/overtime/feed/q3x...Zk.ics"`
+	RotatedAt  time.Time `json:"rotated_at"`
+}
@@ -0,0 +1,19 @@
+package payloads
+
+// ImportOvertimeRowError lists why one CSV row was rejected, keyed by column like the validation errors of POST /v1/overtime
+type ImportOvertimeRowError struct {
+	Line   int                 `json:"line"`
+	Errors []map[string]string `json:"errors"`
+}
+
+// ImportOvertimeResponse is returned by POST /v1/overtime/import
+type ImportOvertimeResponse struct {
+	DryRun      bool                     `json:"dry_run"`
+	SkipInvalid bool                     `json:"skip_invalid"`
+	Total       int                      `json:"total"`    // data rows in the file
+	Valid       int                      `json:"valid"`    // rows that passed every check
+	Imported    int                      `json:"imported"` // rows saved, 0 on a dry run or a rejected file
+	Skipped     int                      `json:"skipped"`  // invalid rows left out
+	IDs         []uint                   `json:"ids"`      // IDs of the saved records
+	Errors      []ImportOvertimeRowError `json:"errors"`
+}
@@ -0,0 +1,40 @@
+package payloads
+
+import (
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/entities"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/limits"
+	"github.com/go-playground/validator/v10"
+)
+
+// UpsertOvertimeLimitPayload replaces the limits of one employee, a null field falls back to the default and 0 disables that cap
+type UpsertOvertimeLimitPayload struct {
+	DailyHours   *float64 `json:"daily_hours" validate:"omitempty,gte=0,lte=24"`
+	WeeklyHours  *float64 `json:"weekly_hours" validate:"omitempty,gte=0,lte=168"`
+	MonthlyHours *float64 `json:"monthly_hours" validate:"omitempty,gte=0,lte=744"`
+	Mode         string   `json:"mode" validate:"omitempty,oneof=warn require_approval block"` // empty = default mode
+}
+
+type OvertimeLimitResponse struct {
+	TelegramID int64                   `json:"telegram_id"`
+	Default    limits.Policy           `json:"default"`
+	Override   *entities.OvertimeLimit `json:"override"` // null = no override
+	Effective  limits.Policy           `json:"effective"`
+}
+
+func (p *UpsertOvertimeLimitPayload) CustomErrorsMessage(errors validator.ValidationErrors) []map[string]string {
+	var errorMessages []map[string]string
+	for _, err := range errors {
+		field := err.Field()
+		switch field {
+		case "DailyHours":
+			errorMessages = append(errorMessages, map[string]string{"daily_hours": "Daily hours must be between 0 and 24"})
+		case "WeeklyHours":
+			errorMessages = append(errorMessages, map[string]string{"weekly_hours": "Weekly hours must be between 0 and 168"})
+		case "MonthlyHours":
+			errorMessages = append(errorMessages, map[string]string{"monthly_hours": "Monthly hours must be between 0 and 744"})
+		case "Mode":
+			errorMessages = append(errorMessages, map[string]string{"mode": "Mode must be warn, require_approval or block"})
+		}
+	}
+	return errorMessages
+}
@@ -10,9 +10,10 @@ type CreateNewRecordOvertime struct {
 	TimeStart     string  `json:"time_start" validate:"required"` // Format: "2006-01-02T15:04:05"
 	TimeStop      string  `json:"time_stop" validate:"required"`  // Format: "2006-01-02T15:04:05"
 	BreakDuration float64 `json:"break_duration" validate:"gte=0"`
-	Duration      float64 `json:"duration" validate:"required,gt=0"`
+	Duration      float64 `json:"duration" validate:"omitempty,gt=0"` // optional, dihitung dari time_start, time_stop dan break_duration
 	Description   string  `json:"description" validate:"omitempty,min=3,max=255"`
-	Category      string  `json:"category" validate:"omitempty,min=3,max=255"`
+	Category      string  `json:"category" validate:"omitempty,max=100"` // optional, nama kategori (tidak membedakan huruf besar/kecil)
+	CategoryID    *uint   `json:"category_id"`                           // optional, dipakai bila category kosong
 }
 
 type GetRecordByDateRequest struct {
@@ -26,16 +27,25 @@ type GetRecordBetweenDateRequest struct {
 	EndDate    string `json:"end_date" validate:"required" example:"2024-01-31"`
 }
 
+// GetOvertimeQuery is read from the query string of GET /v1/overtime, either date or start_date with end_date is set
+type GetOvertimeQuery struct {
+	TelegramID int64  `query:"telegram_id" validate:"required,gt=0"`
+	Date       string `query:"date" validate:"omitempty,datetime=2006-01-02" example:"2024-01-15"`
+	StartDate  string `query:"start_date" validate:"required_with=EndDate,omitempty,datetime=2006-01-02" example:"2024-01-01"`
+	EndDate    string `query:"end_date" validate:"required_with=StartDate,omitempty,datetime=2006-01-02" example:"2024-01-31"`
+}
+
 type UpdateRecordOvertime struct {
-	ID            int64   `json:"id" validate:"required"`
-	TelegramID    int64   `json:"telegram_id"`                                    // optional
-	Date          string  `json:"date" example:"2024-01-15"`                      // optional
-	TimeStart     string  `json:"time_start" example:"2024-01-15T09:00:00"`       // optional
-	TimeStop      string  `json:"time_stop" example:"2024-01-15T18:00:00"`        // optional
-	Duration      float64 `json:"duration" example:"8.0"`                         // optional
-	BreakDuration float64 `json:"break_duration" example:"1.0"`                   // optional
-	Description   string  `json:"description" validate:"omitempty,min=3,max=255"` // optional
-	Category      string  `json:"category" validate:"omitempty,min=3,max=255"`    // optional
+	ID            int64    `json:"id" validate:"required"`
+	TelegramID    int64    `json:"telegram_id"`                                             // optional
+	Date          string   `json:"date" example:"2024-01-15"`                               // optional
+	TimeStart     string   `json:"time_start" example:"2024-01-15T09:00:00"`                // optional
+	TimeStop      string   `json:"time_stop" example:"2024-01-15T18:00:00"`                 // optional
+	Duration      *float64 `json:"duration" validate:"omitempty,gt=0" example:"8.0"`        // optional, hanya dicocokkan dengan durasi hasil hitung
+	BreakDuration *float64 `json:"break_duration" validate:"omitempty,gte=0" example:"1.0"` // optional
+	Description   string   `json:"description" validate:"omitempty,min=3,max=255"`          // optional
+	Category      string   `json:"category" validate:"omitempty,max=100"`                   // optional, nama kategori
+	CategoryID    *uint    `json:"category_id"`                                             // optional, 0 = hapus kategori
 }
 
 func (p *CreateNewRecordOvertime) CustomErrorsMessage(errors validator.ValidationErrors) []map[string]string {
@@ -54,11 +64,11 @@ func (p *CreateNewRecordOvertime) CustomErrorsMessage(errors validator.Validatio
 		case "BreakDuration":
 			errorMessages = append(errorMessages, map[string]string{"break_duration": "Break duration must be greater than or equal to 0"})
 		case "Duration":
-			errorMessages = append(errorMessages, map[string]string{"duration": "Duration is required and must be greater than 0"})
+			errorMessages = append(errorMessages, map[string]string{"duration": "Duration must be greater than 0"})
 		case "Description":
 			errorMessages = append(errorMessages, map[string]string{"description": "Description must be at least 3 characters"})
 		case "Category":
-			errorMessages = append(errorMessages, map[string]string{"category": "Category must be at least 3 characters"})
+			errorMessages = append(errorMessages, map[string]string{"category": "Category must be at most 100 characters"})
 		}
 	}
 	return errorMessages
@@ -94,6 +104,32 @@ func (p *GetRecordBetweenDateRequest) CustomErrorsMessage(errors validator.Valid
 	return errorMessages
 }
 
+func (p *GetOvertimeQuery) CustomErrorsMessage(errors validator.ValidationErrors) []map[string]string {
+	var errorMessages []map[string]string
+	for _, err := range errors {
+		field := err.Field()
+		switch field {
+		case "TelegramID":
+			errorMessages = append(errorMessages, map[string]string{"telegram_id": "Telegram ID is required"})
+		case "Date":
+			errorMessages = append(errorMessages, map[string]string{"date": "Date must use format YYYY-MM-DD"})
+		case "StartDate":
+			if err.Tag() == "required_with" {
+				errorMessages = append(errorMessages, map[string]string{"start_date": "Start date is required with end date"})
+			} else {
+				errorMessages = append(errorMessages, map[string]string{"start_date": "Start date must use format YYYY-MM-DD"})
+			}
+		case "EndDate":
+			if err.Tag() == "required_with" {
+				errorMessages = append(errorMessages, map[string]string{"end_date": "End date is required with start date"})
+			} else {
+				errorMessages = append(errorMessages, map[string]string{"end_date": "End date must use format YYYY-MM-DD"})
+			}
+		}
+	}
+	return errorMessages
+}
+
 func (p *UpdateRecordOvertime) CustomErrorsMessage(errors validator.ValidationErrors) []map[string]string {
 	var errorMessages []map[string]string
 	for _, err := range errors {
@@ -104,7 +140,7 @@ func (p *UpdateRecordOvertime) CustomErrorsMessage(errors validator.ValidationEr
 		case "Description":
 			errorMessages = append(errorMessages, map[string]string{"description": "Description must be at least 3 characters and maximum 255 characters"})
 		case "Category":
-			errorMessages = append(errorMessages, map[string]string{"category": "Category must be at least 3 characters and maximum 255 characters"})
+			errorMessages = append(errorMessages, map[string]string{"category": "Category must be at most 100 characters"})
 		case "Duration":
 			errorMessages = append(errorMessages, map[string]string{"duration": "Duration must be greater than 0"})
 		case "BreakDuration":
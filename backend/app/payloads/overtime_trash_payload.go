@@ -0,0 +1,32 @@
+package payloads
+
+import (
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// GetOvertimeTrashQuery is read from the query string of GET /v1/overtime/trash
+type GetOvertimeTrashQuery struct {
+	ListQuery
+	TelegramID int64 `query:"telegram_id" validate:"required,gt=0"`
+}
+
+// OvertimeTrashInfo tells until when a deleted overtime record can be restored
+type OvertimeTrashInfo struct {
+	ID        uint       `json:"id"`
+	DeletedAt time.Time  `json:"deleted_at"`
+	PurgeAt   *time.Time `json:"purge_at"` // null = trash is never purged
+}
+
+func (p *GetOvertimeTrashQuery) CustomErrorsMessage(errors validator.ValidationErrors) []map[string]string {
+	var errorMessages []map[string]string
+	for _, err := range errors {
+		field := err.Field()
+		switch field {
+		case "TelegramID":
+			errorMessages = append(errorMessages, map[string]string{"telegram_id": "Telegram ID is required"})
+		}
+	}
+	return errorMessages
+}
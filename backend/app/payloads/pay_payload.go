@@ -0,0 +1,80 @@
+package payloads
+
+import (
+	"time"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/payroll"
+	"github.com/go-playground/validator/v10"
+)
+
+// GetOvertimePayQuery is read from the query string of GET /v1/overtime/pay
+type GetOvertimePayQuery struct {
+	TelegramID int64  `query:"telegram_id" validate:"required"`
+	StartDate  string `query:"start_date" validate:"required"` // Format: YYYY-MM-DD
+	EndDate    string `query:"end_date" validate:"required"`   // Format: YYYY-MM-DD
+}
+
+type UpsertPayProfilePayload struct {
+	MonthlySalary float64        `json:"monthly_salary" validate:"required,gt=0"`
+	WorkWeekDays  int            `json:"work_week_days" validate:"omitempty,oneof=5 6"` // default 5
+	HourlyDivisor float64        `json:"hourly_divisor" validate:"omitempty,gt=0"`      // default 173
+	Tiers         *payroll.Tiers `json:"tiers"`                                         // optional, null = Kepmenakertrans 102/2004
+}
+
+type PayProfileResponse struct {
+	TelegramID    int64         `json:"telegram_id"`
+	MonthlySalary *float64      `json:"monthly_salary,omitempty"`
+	WorkWeekDays  int           `json:"work_week_days"`
+	HourlyDivisor float64       `json:"hourly_divisor"`
+	HourlyWage    float64       `json:"hourly_wage"`
+	CustomTiers   bool          `json:"custom_tiers"`
+	Tiers         payroll.Tiers `json:"tiers"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+}
+
+type OvertimePayPeriod struct {
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+}
+
+// OvertimePayResponse only counts approved records, the rest is reported as unapproved
+type OvertimePayResponse struct {
+	TelegramID int64              `json:"telegram_id"`
+	Period     OvertimePayPeriod  `json:"period"`
+	Profile    PayProfileResponse `json:"profile"`
+	payroll.Result
+	UnapprovedRecordsCount int     `json:"unapproved_records_count"`
+	UnapprovedHours        float64 `json:"unapproved_hours"`
+}
+
+func (p *GetOvertimePayQuery) CustomErrorsMessage(errors validator.ValidationErrors) []map[string]string {
+	var errorMessages []map[string]string
+	for _, err := range errors {
+		field := err.Field()
+		switch field {
+		case "TelegramID":
+			errorMessages = append(errorMessages, map[string]string{"telegram_id": "Telegram ID is required"})
+		case "StartDate":
+			errorMessages = append(errorMessages, map[string]string{"start_date": "Start date is required"})
+		case "EndDate":
+			errorMessages = append(errorMessages, map[string]string{"end_date": "End date is required"})
+		}
+	}
+	return errorMessages
+}
+
+func (p *UpsertPayProfilePayload) CustomErrorsMessage(errors validator.ValidationErrors) []map[string]string {
+	var errorMessages []map[string]string
+	for _, err := range errors {
+		field := err.Field()
+		switch field {
+		case "MonthlySalary":
+			errorMessages = append(errorMessages, map[string]string{"monthly_salary": "Monthly salary is required and must be greater than 0"})
+		case "WorkWeekDays":
+			errorMessages = append(errorMessages, map[string]string{"work_week_days": "Work week days must be 5 or 6"})
+		case "HourlyDivisor":
+			errorMessages = append(errorMessages, map[string]string{"hourly_divisor": "Hourly divisor must be greater than 0"})
+		}
+	}
+	return errorMessages
+}
@@ -0,0 +1,72 @@
+package payloads
+
+import (
+	"github.com/go-playground/validator/v10"
+)
+
+// GetOvertimeSummaryQuery is read from the query string of GET /v1/overtime/summary.
+// telegram_id can be repeated or comma separated.
+type GetOvertimeSummaryQuery struct {
+	TelegramIDs []string `query:"telegram_id" validate:"required,min=1"`
+	GroupBy     string   `query:"group_by" validate:"omitempty,oneof=day week month category"` // default day
+	StartDate   string   `query:"start_date" validate:"required"`                              // Format: YYYY-MM-DD
+	EndDate     string   `query:"end_date" validate:"required"`                                // Format: YYYY-MM-DD
+	Status      string   `query:"status" validate:"omitempty,oneof=draft submitted approved rejected"`
+}
+
+// OvertimeSummaryTotals are computed in SQL, averages are per record except average_daily_duration (per day with overtime)
+type OvertimeSummaryTotals struct {
+	RecordsCount         int64   `json:"records_count"`
+	DaysCount            int64   `json:"days_count"`
+	TotalDuration        float64 `json:"total_duration"`
+	TotalBreakDuration   float64 `json:"total_break_duration"`
+	AverageDuration      float64 `json:"average_duration"`
+	AverageBreakDuration float64 `json:"average_break_duration"`
+	AverageDailyDuration float64 `json:"average_daily_duration"`
+}
+
+// OvertimeSummaryBucket is a day, week (Monday to Sunday), month or category. Empty buckets are left out.
+type OvertimeSummaryBucket struct {
+	StartDate  string `json:"start_date,omitempty"` // group by day/week/month
+	EndDate    string `json:"end_date,omitempty"`
+	IsHoliday  *bool  `json:"is_holiday,omitempty"` // group by day
+	IsWeekend  *bool  `json:"is_weekend,omitempty"` // group by day
+	CategoryID *uint  `json:"category_id"`          // group by category, null = no category
+	Category   string `json:"category,omitempty"`
+	OvertimeSummaryTotals
+}
+
+type OvertimeSummaryUser struct {
+	TelegramID int64                   `json:"telegram_id"`
+	Totals     OvertimeSummaryTotals   `json:"totals"`
+	Buckets    []OvertimeSummaryBucket `json:"buckets"`
+}
+
+type OvertimeSummaryResponse struct {
+	GroupBy  string                `json:"group_by"`
+	Timezone string                `json:"timezone"`
+	Period   OvertimePayPeriod     `json:"period"`
+	Status   string                `json:"status,omitempty"`
+	Totals   OvertimeSummaryTotals `json:"totals"`
+	Users    []OvertimeSummaryUser `json:"users"`
+}
+
+func (p *GetOvertimeSummaryQuery) CustomErrorsMessage(errors validator.ValidationErrors) []map[string]string {
+	var errorMessages []map[string]string
+	for _, err := range errors {
+		field := err.Field()
+		switch field {
+		case "TelegramIDs":
+			errorMessages = append(errorMessages, map[string]string{"telegram_id": "At least one telegram_id is required"})
+		case "GroupBy":
+			errorMessages = append(errorMessages, map[string]string{"group_by": "Group by must be day, week, month or category"})
+		case "StartDate":
+			errorMessages = append(errorMessages, map[string]string{"start_date": "Start date is required"})
+		case "EndDate":
+			errorMessages = append(errorMessages, map[string]string{"end_date": "End date is required"})
+		case "Status":
+			errorMessages = append(errorMessages, map[string]string{"status": "Status must be draft, submitted, approved or rejected"})
+		}
+	}
+	return errorMessages
+}
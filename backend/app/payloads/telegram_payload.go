@@ -1,30 +1,24 @@
 package payloads
 
-import "github.com/go-playground/validator/v10"
+import (
+	"time"
 
-type CreateNewTelegramPayload struct {
+	"github.com/go-playground/validator/v10"
+)
+
+// LinkTelegramAccountPayload is built by the bot from the verified sender of "/start <code>", never from client JSON
+type LinkTelegramAccountPayload struct {
+	Code       string `json:"code" validate:"required"`
 	TelegramID int64  `json:"telegram_id" validate:"required"`
-	Username   string `json:"username" validate:"required,min=3,max=50"`
-	FirstName  string `json:"first_name" validate:"min=3,max=50"`
-	LastName   string `json:"last_name" validate:"min=3,max=50"`
+	Username   string `json:"username"`
+	FirstName  string `json:"first_name"`
+	LastName   string `json:"last_name"`
 }
 
-func (p *CreateNewTelegramPayload) CustomErrorsMessage(errors validator.ValidationErrors) []map[string]string {
-	var errorMessages []map[string]string
-	for _, err := range errors {
-		field := err.Field()
-		switch field {
-		case "TelegramID":
-			errorMessages = append(errorMessages, map[string]string{"telegram_id": "Telegram ID is required"})
-		case "Username":
-			errorMessages = append(errorMessages, map[string]string{"username": "Username is required"})
-		case "FirstName":
-			errorMessages = append(errorMessages, map[string]string{"first_name": "First name must be at least 3 characters"})
-		case "LastName":
-			errorMessages = append(errorMessages, map[string]string{"last_name": "Last name must be at least 3 characters"})
-		}
-	}
-	return errorMessages
+type TelegramLinkCodeResponse struct {
+	Code      string    `json:"code"`
+	Link      string    `json:"link"`
+	ExpiresAt time.Time `json:"expires_at"`
 }
 
 type UpdateTelegramPayload struct {
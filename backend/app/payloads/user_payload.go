@@ -56,3 +56,19 @@ type ResponseCreateApiKeyPayload struct {
 	ExpiredAt   *time.Time `json:"expired_at"`
 	APIKey      string     `json:"api_key"`
 }
+
+type UpdateUserRolePayload struct {
+	Role string `json:"role" validate:"required,oneof=user approver admin"`
+}
+
+func (p *UpdateUserRolePayload) CustomErrorsMessage(errors validator.ValidationErrors) []map[string]string {
+	var errorMessages []map[string]string
+	for _, err := range errors {
+		field := err.Field()
+		switch field {
+		case "Role":
+			errorMessages = append(errorMessages, map[string]string{"role": "Role must be one of user, approver, admin"})
+		}
+	}
+	return errorMessages
+}
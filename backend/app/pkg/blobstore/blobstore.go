@@ -0,0 +1,71 @@
+// Package blobstore stores uploaded files (overtime attachments) behind one interface:
+// the local filesystem by default or an S3-compatible bucket (AWS S3, MinIO, Cloudflare R2, ...).
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/helpers"
+)
+
+// ErrNotFound is returned by Open when no object is stored under the key
+var ErrNotFound = errors.New("blobstore: object not found")
+
+// Store keeps objects under slash separated keys such as "overtime/12/3f9c.pdf"
+type Store interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// Client is the store configured by Open, used by the services
+var Client Store
+
+// Open configures Client from BLOB_STORAGE_DRIVER: local (default, BLOB_STORAGE_LOCAL_DIR) or s3 (S3_* variables)
+func Open() error {
+	store, err := FromEnv()
+	if err != nil {
+		return err
+	}
+	Client = store
+	return nil
+}
+
+// FromEnv builds the store selected by BLOB_STORAGE_DRIVER
+func FromEnv() (Store, error) {
+	switch driver := strings.ToLower(helpers.GetEnv("BLOB_STORAGE_DRIVER", "local")); driver {
+	case "local":
+		return NewLocal(helpers.GetEnv("BLOB_STORAGE_LOCAL_DIR", "./storage"))
+	case "s3":
+		store := &S3{
+			Endpoint:        strings.TrimRight(helpers.GetEnv("S3_ENDPOINT", ""), "/"),
+			Region:          helpers.GetEnv("S3_REGION", "us-east-1"),
+			Bucket:          helpers.GetEnv("S3_BUCKET", ""),
+			AccessKeyID:     helpers.GetEnv("S3_ACCESS_KEY_ID", ""),
+			SecretAccessKey: helpers.GetEnv("S3_SECRET_ACCESS_KEY", ""),
+			PathStyle:       helpers.GetEnv("S3_FORCE_PATH_STYLE", "false") == "true",
+			HTTPClient:      &http.Client{Timeout: 60 * time.Second},
+		}
+		if store.Endpoint == "" || store.Bucket == "" || store.AccessKeyID == "" || store.SecretAccessKey == "" {
+			return nil, errors.New("blobstore: S3_ENDPOINT, S3_BUCKET, S3_ACCESS_KEY_ID and S3_SECRET_ACCESS_KEY are required for the s3 driver")
+		}
+		return store, nil
+	default:
+		return nil, fmt.Errorf("blobstore: unknown BLOB_STORAGE_DRIVER %q, use local or s3", driver)
+	}
+}
+
+// validKey rejects empty keys, absolute paths and ".." so a key can never leave the store root
+func validKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, `\`) || path.Clean(key) != key || key == "." || strings.HasPrefix(key, "../") || key == ".." {
+		return fmt.Errorf("blobstore: invalid key %q", key)
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+package blobstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// emptyPayloadHash is the SHA-256 of an empty body, sent with GET and DELETE
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// S3 stores objects in a bucket of an S3-compatible service, requests are signed with AWS Signature Version 4.
+// PathStyle addresses the bucket as <endpoint>/<bucket>/<key> (MinIO), otherwise as <bucket>.<endpoint host>/<key>.
+type S3 struct {
+	Endpoint        string // e.g. https://s3.ap-southeast-1.amazonaws.com or http://minio:9000
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	PathStyle       bool
+	HTTPClient      *http.Client
+}
+
+func (s *S3) objectURL(key string) (string, error) {
+	if err := validKey(key); err != nil {
+		return "", err
+	}
+	endpoint, err := url.Parse(s.Endpoint)
+	if err != nil {
+		return "", err
+	}
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = uriEncode(segment)
+	}
+	base := strings.TrimRight(endpoint.Path, "/")
+	if s.PathStyle {
+		endpoint.Path = base + "/" + s.Bucket + "/" + key
+		endpoint.RawPath = base + "/" + uriEncode(s.Bucket) + "/" + strings.Join(segments, "/")
+	} else {
+		endpoint.Host = s.Bucket + "." + endpoint.Host
+		endpoint.Path = base + "/" + key
+		endpoint.RawPath = base + "/" + strings.Join(segments, "/")
+	}
+	return endpoint.String(), nil
+}
+
+// Put buffers the object to sign its SHA-256, attachments are small enough for that
+func (s *S3) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(body)
+	resp, err := s.do(ctx, http.MethodPut, key, body, hex.EncodeToString(sum[:]), contentType)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return s.check(resp, http.MethodPut, key)
+}
+
+func (s *S3) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	resp, err := s.do(ctx, http.MethodGet, key, nil, emptyPayloadHash, "")
+	if err != nil {
+		return nil, err
+	}
+	if err := s.check(resp, http.MethodGet, key); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Delete does not fail when the object is already gone, S3 answers 204 either way
+func (s *S3) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, key, nil, emptyPayloadHash, "")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := s.check(resp, http.MethodDelete, key); err != nil && err != ErrNotFound {
+		return err
+	}
+	return nil
+}
+
+func (s *S3) do(ctx context.Context, method string, key string, body []byte, payloadHash string, contentType string) (*http.Response, error) {
+	objectURL, err := s.objectURL(key)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, objectURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	s.sign(req, payloadHash, time.Now())
+
+	client := s.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return client.Do(req)
+}
+
+func (s *S3) check(resp *http.Response, method string, key string) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return fmt.Errorf("blobstore: s3 %s %s: %s %s", method, key, resp.Status, strings.TrimSpace(string(detail)))
+}
+
+// sign adds the x-amz-* headers and the Authorization header of Signature Version 4,
+// every header already set on req is signed together with host
+func (s *S3) sign(req *http.Request, payloadHash string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + s.Region + "/s3/aws4_request"
+	canonicalSum := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(canonicalSum[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.SecretAccessKey), date)
+	key = hmacSHA256(key, s.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+s.AccessKeyID+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func canonicalQuery(values url.Values) string {
+	pairs := make([]string, 0, len(values))
+	for name, list := range values {
+		for _, value := range list {
+			pairs = append(pairs, uriEncode(name)+"="+uriEncode(value))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode percent-encodes everything except the unreserved characters A-Z a-z 0-9 - _ . ~
+func uriEncode(value string) string {
+	var sb strings.Builder
+	for i := 0; i < len(value); i++ {
+		ch := value[i]
+		if ch >= 'A' && ch <= 'Z' || ch >= 'a' && ch <= 'z' || ch >= '0' && ch <= '9' || ch == '-' || ch == '_' || ch == '.' || ch == '~' {
+			sb.WriteByte(ch)
+			continue
+		}
+		fmt.Fprintf(&sb, "%%%02X", ch)
+	}
+	return sb.String()
+}
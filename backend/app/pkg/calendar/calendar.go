@@ -0,0 +1,277 @@
+// Package calendar reads public holiday lists from iCalendar (.ics) and CSV files and writes iCalendar feeds.
+package calendar
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// maxEventDays caps multi-day events so a broken DTEND cannot flood the table
+const maxEventDays = 31
+
+// Entry is one holiday date, multi-day events are split into one entry per day
+type Entry struct {
+	Date            time.Time // midnight in the location passed to the parser
+	Name            string
+	CollectiveLeave bool // cuti bersama
+}
+
+// LineError reports a row or event that was skipped
+type LineError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+// IsWeekend reports whether date falls on Saturday or Sunday
+func IsWeekend(date time.Time) bool {
+	weekday := date.Weekday()
+	return weekday == time.Saturday || weekday == time.Sunday
+}
+
+// IsCollectiveLeave detects cuti bersama from the holiday name
+func IsCollectiveLeave(name string) bool {
+	name = strings.ToLower(name)
+	return strings.Contains(name, "cuti bersama") || strings.Contains(name, "collective leave")
+}
+
+type icsLine struct {
+	number int
+	text   string
+}
+
+// unfold joins folded lines (RFC 5545 3.1), continuation lines start with a space or tab
+func unfold(r io.Reader) ([]icsLine, error) {
+	var lines []icsLine
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	number := 0
+	for scanner.Scan() {
+		number++
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(text, " ") || strings.HasPrefix(text, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1].text += text[1:]
+			continue
+		}
+		lines = append(lines, icsLine{number: number, text: text})
+	}
+	return lines, scanner.Err()
+}
+
+// splitProperty splits "DTSTART;VALUE=DATE:20240101" into name, params and value
+func splitProperty(text string) (string, map[string]string, string) {
+	colon := strings.Index(text, ":")
+	if colon < 0 {
+		return strings.ToUpper(text), nil, ""
+	}
+	parts := strings.Split(text[:colon], ";")
+	params := map[string]string{}
+	for _, param := range parts[1:] {
+		if key, value, ok := strings.Cut(param, "="); ok {
+			params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+		}
+	}
+	return strings.ToUpper(parts[0]), params, text[colon+1:]
+}
+
+func unescapeText(value string) string {
+	replacer := strings.NewReplacer(`\\`, `\`, `\,`, `,`, `\;`, `;`, `\n`, " ", `\N`, " ")
+	return strings.TrimSpace(replacer.Replace(value))
+}
+
+// parseICSDate returns the calendar date of a DTSTART/DTEND value and whether it was a date-time
+func parseICSDate(value string, params map[string]string, loc *time.Location) (time.Time, bool, error) {
+	if params["VALUE"] == "DATE" || len(value) == 8 {
+		date, err := time.ParseInLocation("20060102", value, loc)
+		return date, false, err
+	}
+
+	var moment time.Time
+	var err error
+	switch {
+	case strings.HasSuffix(value, "Z"):
+		moment, err = time.Parse("20060102T150405Z", value)
+	case params["TZID"] != "":
+		zone, zoneErr := time.LoadLocation(params["TZID"])
+		if zoneErr != nil {
+			zone = loc
+		}
+		moment, err = time.ParseInLocation("20060102T150405", value, zone)
+	default:
+		moment, err = time.ParseInLocation("20060102T150405", value, loc)
+	}
+	if err != nil {
+		return time.Time{}, true, err
+	}
+	moment = moment.In(loc)
+	return time.Date(moment.Year(), moment.Month(), moment.Day(), 0, 0, 0, 0, loc), moment.Hour() != 0 || moment.Minute() != 0, nil
+}
+
+// ParseICS reads the VEVENTs of an iCalendar file. All-day events use an exclusive DTEND, cancelled events are skipped.
+func ParseICS(r io.Reader, loc *time.Location) ([]Entry, []LineError, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var entries []Entry
+	var skipped []LineError
+	events := 0
+	inEvent := false
+	var eventLine int
+	var summary, status string
+	var startValue, endValue string
+	var startParams, endParams map[string]string
+
+	for _, line := range lines {
+		name, params, value := splitProperty(line.text)
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT"):
+			inEvent = true
+			eventLine = line.number
+			summary, status, startValue, endValue = "", "", "", ""
+			startParams, endParams = nil, nil
+		case name == "END" && strings.EqualFold(value, "VEVENT"):
+			if !inEvent {
+				continue
+			}
+			inEvent = false
+			events++
+			if strings.EqualFold(status, "CANCELLED") {
+				continue
+			}
+			eventEntries, err := icsEventEntries(summary, startValue, startParams, endValue, endParams, loc)
+			if err != nil {
+				skipped = append(skipped, LineError{Line: eventLine, Error: err.Error()})
+				continue
+			}
+			entries = append(entries, eventEntries...)
+		case !inEvent:
+			continue
+		case name == "SUMMARY":
+			summary = unescapeText(value)
+		case name == "STATUS":
+			status = strings.TrimSpace(value)
+		case name == "DTSTART":
+			startValue, startParams = strings.TrimSpace(value), params
+		case name == "DTEND":
+			endValue, endParams = strings.TrimSpace(value), params
+		}
+	}
+	if events == 0 {
+		return nil, nil, errors.New("no VEVENT found in iCalendar file")
+	}
+	return entries, skipped, nil
+}
+
+func icsEventEntries(summary string, startValue string, startParams map[string]string, endValue string, endParams map[string]string, loc *time.Location) ([]Entry, error) {
+	if summary == "" {
+		return nil, errors.New("event without SUMMARY")
+	}
+	if startValue == "" {
+		return nil, errors.New("event without DTSTART")
+	}
+	start, _, err := parseICSDate(startValue, startParams, loc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DTSTART %q", startValue)
+	}
+
+	// last is inclusive: an all-day DTEND (or a date-time ending at midnight) is the day after the event
+	last := start
+	if endValue != "" {
+		end, hasTime, err := parseICSDate(endValue, endParams, loc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid DTEND %q", endValue)
+		}
+		if !hasTime {
+			end = end.AddDate(0, 0, -1)
+		}
+		if end.After(start) {
+			last = end
+		}
+	}
+	if last.Sub(start) > maxEventDays*24*time.Hour {
+		return nil, fmt.Errorf("event %q is longer than %d days", summary, maxEventDays)
+	}
+
+	var entries []Entry
+	for date := start; !date.After(last); date = date.AddDate(0, 0, 1) {
+		entries = append(entries, Entry{Date: date, Name: summary, CollectiveLeave: IsCollectiveLeave(summary)})
+	}
+	return entries, nil
+}
+
+// ParseCSV reads rows of date,name[,kind]. The date is YYYY-MM-DD or DD/MM/YYYY, kind is national or
+// collective_leave (cuti bersama) and is detected from the name when empty. A header row is skipped.
+func ParseCSV(r io.Reader, loc *time.Location) ([]Entry, []LineError, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var entries []Entry
+	var skipped []LineError
+	header := true
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				skipped = append(skipped, LineError{Line: parseErr.Line, Error: parseErr.Err.Error()})
+				continue
+			}
+			return nil, nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		if len(record) == 0 || (len(record) == 1 && strings.TrimSpace(record[0]) == "") {
+			continue
+		}
+		first := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(record[0], "\ufeff")))
+		if header && (first == "date" || first == "tanggal") {
+			header = false
+			continue
+		}
+		header = false
+		if len(record) < 2 || strings.TrimSpace(record[1]) == "" {
+			skipped = append(skipped, LineError{Line: line, Error: "expected date,name[,kind]"})
+			continue
+		}
+
+		date, err := parseCSVDate(first, loc)
+		if err != nil {
+			skipped = append(skipped, LineError{Line: line, Error: fmt.Sprintf("invalid date %q, use YYYY-MM-DD or DD/MM/YYYY", record[0])})
+			continue
+		}
+		name := strings.TrimSpace(record[1])
+		collectiveLeave := IsCollectiveLeave(name)
+		if len(record) > 2 {
+			switch strings.ToLower(strings.TrimSpace(record[2])) {
+			case "":
+			case "national", "nasional", "libur nasional":
+				collectiveLeave = false
+			case "collective_leave", "cuti_bersama", "cuti bersama":
+				collectiveLeave = true
+			default:
+				skipped = append(skipped, LineError{Line: line, Error: fmt.Sprintf("unknown kind %q, use national or collective_leave", record[2])})
+				continue
+			}
+		}
+		entries = append(entries, Entry{Date: date, Name: name, CollectiveLeave: collectiveLeave})
+	}
+	return entries, skipped, nil
+}
+
+func parseCSVDate(value string, loc *time.Location) (time.Time, error) {
+	for _, layout := range []string{"2006-01-02", "02/01/2006", "2/1/2006"} {
+		if date, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", value)
+}
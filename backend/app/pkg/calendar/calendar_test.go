@@ -0,0 +1,200 @@
+package calendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+var wib = time.FixedZone("WIB", 7*60*60)
+
+type wantEntry struct {
+	date            string
+	name            string
+	collectiveLeave bool
+}
+
+func checkEntries(t *testing.T, got []Entry, want []wantEntry) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d entries, want %d: %+v", len(got), len(want), got)
+	}
+	for i, entry := range got {
+		if entry.Date.Location() != wib {
+			t.Errorf("entry %d: location = %s, want WIB", i, entry.Date.Location())
+		}
+		if date := entry.Date.Format("2006-01-02"); date != want[i].date || entry.Name != want[i].name || entry.CollectiveLeave != want[i].collectiveLeave {
+			t.Errorf("entry %d = {%s %q %v}, want {%s %q %v}", i, date, entry.Name, entry.CollectiveLeave, want[i].date, want[i].name, want[i].collectiveLeave)
+		}
+	}
+}
+
+func TestParseICS(t *testing.T) {
+	ics := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20240101",
+		"DTEND;VALUE=DATE:20240102",
+		"SUMMARY:Tahun Baru Masehi",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20240410",
+		"DTEND;VALUE=DATE:20240412",
+		"SUMMARY:Hari Raya Idul Fitri 1445 H",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20240408",
+		"SUMMARY:Cuti Bersama Idul Fitri\\, 1445 H",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART:20240817T000000",
+		"DTEND:20240818T000000",
+		"SUMMARY:Hari Kemerdekaan Republik ",
+		" Indonesia",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART:20241224T170000Z",
+		"DTEND:20241225T170000Z",
+		"SUMMARY:Hari Raya Natal",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20240501",
+		"SUMMARY:Dibatalkan",
+		"STATUS:CANCELLED",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:2024-05-09",
+		"SUMMARY:Tanggal rusak",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	entries, skipped, err := ParseICS(strings.NewReader(ics), wib)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkEntries(t, entries, []wantEntry{
+		{"2024-01-01", "Tahun Baru Masehi", false},
+		{"2024-04-10", "Hari Raya Idul Fitri 1445 H", false},
+		{"2024-04-11", "Hari Raya Idul Fitri 1445 H", false},
+		{"2024-04-08", "Cuti Bersama Idul Fitri, 1445 H", true},
+		{"2024-08-17", "Hari Kemerdekaan Republik Indonesia", false},
+		{"2024-12-25", "Hari Raya Natal", false},
+	})
+	if len(skipped) != 1 || skipped[0].Line != 33 {
+		t.Errorf("skipped = %+v, want one error at line 33", skipped)
+	}
+}
+
+func TestParseICSWithoutEvents(t *testing.T) {
+	if _, _, err := ParseICS(strings.NewReader("BEGIN:VCALENDAR\nEND:VCALENDAR\n"), wib); err == nil {
+		t.Error("expected an error for a calendar without events")
+	}
+}
+
+func TestParseCSV(t *testing.T) {
+	csv := "tanggal,nama,jenis\n" +
+		"2024-01-01,Tahun Baru Masehi,\n" +
+		"09/04/2024,Cuti Bersama Idul Fitri,\n" +
+		"2024-12-26,Libur tambahan,cuti bersama\n" +
+		"\n" +
+		"2024-13-01,Bulan salah\n" +
+		"2024-05-01\n" +
+		"2024-05-09,Kenaikan Isa Almasih,national\n" +
+		"2024-05-10,Jenis salah,lainnya\n"
+
+	entries, skipped, err := ParseCSV(strings.NewReader(csv), wib)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkEntries(t, entries, []wantEntry{
+		{"2024-01-01", "Tahun Baru Masehi", false},
+		{"2024-04-09", "Cuti Bersama Idul Fitri", true},
+		{"2024-12-26", "Libur tambahan", true},
+		{"2024-05-09", "Kenaikan Isa Almasih", false},
+	})
+
+	wantLines := []int{6, 7, 9}
+	if len(skipped) != len(wantLines) {
+		t.Fatalf("skipped = %+v, want lines %v", skipped, wantLines)
+	}
+	for i, line := range wantLines {
+		if skipped[i].Line != line {
+			t.Errorf("skipped[%d].Line = %d, want %d", i, skipped[i].Line, line)
+		}
+	}
+}
+
+func TestIsWeekend(t *testing.T) {
+	saturday := time.Date(2024, time.March, 16, 0, 0, 0, 0, wib)
+	if !IsWeekend(saturday) || !IsWeekend(saturday.AddDate(0, 0, 1)) {
+		t.Error("saturday and sunday must be weekend")
+	}
+	if IsWeekend(saturday.AddDate(0, 0, 2)) {
+		t.Error("monday must not be weekend")
+	}
+}
+
+func TestWriteICS(t *testing.T) {
+	start := time.Date(2024, time.March, 15, 23, 0, 0, 0, wib)
+	events := []Event{{
+		UID:         "overtime-7@example",
+		Start:       start,
+		End:         start.Add(150 * time.Minute),
+		Summary:     "Lembur 2.50 jam, Infra",
+		Description: "Migrasi database; cutover\nrollback siap di \\backup " + strings.Repeat("panjang ", 10),
+		Categories:  []string{"Infra"},
+		Status:      EventConfirmed,
+		Updated:     start,
+	}}
+
+	var buf strings.Builder
+	if err := WriteICS(&buf, "Lembur Budi", events); err != nil {
+		t.Fatal(err)
+	}
+	output := buf.String()
+	if !strings.HasSuffix(output, "END:VCALENDAR\r\n") {
+		t.Fatalf("output must end with CRLF terminated END:VCALENDAR, got %q", output[len(output)-20:])
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(output, "\r\n"), "\r\n") {
+		if len(line) > maxLineOctets {
+			t.Errorf("line longer than %d octets: %q", maxLineOctets, line)
+		}
+	}
+
+	lines, err := unfold(strings.NewReader(output))
+	if err != nil {
+		t.Fatal(err)
+	}
+	properties := map[string]string{}
+	for _, line := range lines {
+		name, _, value := splitProperty(line.text)
+		properties[name] = value
+	}
+	want := map[string]string{
+		"X-WR-CALNAME": "Lembur Budi",
+		"UID":          "overtime-7@example",
+		"DTSTART":      "20240315T160000Z",
+		"DTEND":        "20240315T183000Z",
+		"SUMMARY":      `Lembur 2.50 jam\, Infra`,
+		"DESCRIPTION":  `Migrasi database\; cutover\nrollback siap di \\backup ` + strings.Repeat("panjang ", 10),
+		"CATEGORIES":   "Infra",
+		"STATUS":       "CONFIRMED",
+	}
+	for name, value := range want {
+		if properties[name] != value {
+			t.Errorf("%s = %q, want %q", name, properties[name], value)
+		}
+	}
+
+	// the feed can be read back by the holiday importer, the event past midnight covers two days
+	entries, _, err := ParseICS(strings.NewReader(output), wib)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkEntries(t, entries, []wantEntry{
+		{"2024-03-15", "Lembur 2.50 jam, Infra", false},
+		{"2024-03-16", "Lembur 2.50 jam, Infra", false},
+	})
+}
@@ -0,0 +1,104 @@
+package calendar
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Event status values of RFC 5545
+const (
+	EventTentative = "TENTATIVE"
+	EventConfirmed = "CONFIRMED"
+	EventCancelled = "CANCELLED"
+)
+
+// maxLineOctets is the longest content line before it has to be folded (RFC 5545 3.1)
+const maxLineOctets = 75
+
+// Event is one VEVENT written by WriteICS, times are converted to UTC
+type Event struct {
+	UID         string
+	Start       time.Time
+	End         time.Time
+	Summary     string
+	Description string
+	Categories  []string
+	Status      string // EventTentative, EventConfirmed or EventCancelled, empty = not written
+	Updated     time.Time
+}
+
+// WriteICS writes a published iCalendar feed named name, clients are asked to refresh it every hour
+func WriteICS(w io.Writer, name string, events []Event) error {
+	writer := bufio.NewWriter(w)
+	write := func(line string) {
+		writeFolded(writer, line)
+	}
+
+	write("BEGIN:VCALENDAR")
+	write("VERSION:2.0")
+	write("PRODID:-//mini-app-bot-telegram//overtime feed//ID")
+	write("CALSCALE:GREGORIAN")
+	write("METHOD:PUBLISH")
+	write("X-WR-CALNAME:" + escapeText(name))
+	write("X-PUBLISHED-TTL:PT1H")
+	write("REFRESH-INTERVAL;VALUE=DURATION:PT1H")
+	for _, event := range events {
+		write("BEGIN:VEVENT")
+		write("UID:" + event.UID)
+		write("DTSTAMP:" + formatUTC(event.Updated))
+		write("LAST-MODIFIED:" + formatUTC(event.Updated))
+		write("DTSTART:" + formatUTC(event.Start))
+		write("DTEND:" + formatUTC(event.End))
+		write("SUMMARY:" + escapeText(event.Summary))
+		if event.Description != "" {
+			write("DESCRIPTION:" + escapeText(event.Description))
+		}
+		if len(event.Categories) > 0 {
+			categories := make([]string, len(event.Categories))
+			for i, category := range event.Categories {
+				categories[i] = escapeText(category)
+			}
+			write("CATEGORIES:" + strings.Join(categories, ","))
+		}
+		if event.Status != "" {
+			write("STATUS:" + event.Status)
+		}
+		write("TRANSP:OPAQUE")
+		write("END:VEVENT")
+	}
+	write("END:VCALENDAR")
+	return writer.Flush()
+}
+
+func formatUTC(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// escapeText escapes a TEXT value (RFC 5545 3.3.11)
+func escapeText(value string) string {
+	value = strings.ReplaceAll(value, "\r\n", "\n")
+	value = strings.ReplaceAll(value, "\r", "\n")
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(value)
+}
+
+// writeFolded writes one content line ending with CRLF, longer lines continue on lines starting with a space.
+// Lines are only split between UTF-8 characters.
+func writeFolded(w *bufio.Writer, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		w.WriteString(line[:cut])
+		w.WriteString("\r\n ")
+		line = line[cut:]
+		// the leading space counts toward the next line
+		limit = maxLineOctets - 1
+	}
+	w.WriteString(line)
+	w.WriteString("\r\n")
+}
@@ -0,0 +1,115 @@
+package database
+
+import (
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/entities"
+	"gorm.io/gorm"
+)
+
+// categoryKey groups free-text spellings: case, surrounding and repeated spaces are ignored
+func categoryKey(value string) string {
+	return strings.ToLower(strings.Join(strings.Fields(value), " "))
+}
+
+// categoryAliases reads CATEGORY_ALIASES ("deployment=deploy,rapat=meeting"), spellings on the left are merged into the right
+func categoryAliases() map[string]string {
+	aliases := map[string]string{}
+	for _, pair := range strings.Split(os.Getenv("CATEGORY_ALIASES"), ",") {
+		from, to, ok := strings.Cut(pair, "=")
+		if !ok || categoryKey(from) == "" || categoryKey(to) == "" {
+			continue
+		}
+		aliases[categoryKey(from)] = categoryKey(to)
+	}
+	return aliases
+}
+
+// defaultCategories are created on an empty categories table, they were the bot keyboard defaults before categories had a table
+var defaultCategories = []string{"Development", "Support", "Infra", "Meeting"}
+
+// migrateCategories moves the old free-text overtimes.category column into the categories table and drops it.
+// Spellings that only differ in case or spacing (plus CATEGORY_ALIASES) become one category named after the most used spelling.
+func migrateCategories(db *gorm.DB) error {
+	if !db.Migrator().HasColumn("overtimes", "category") {
+		return seedCategories(db)
+	}
+
+	type legacyValue struct {
+		Category string
+		Count    int
+	}
+	var values []legacyValue
+	err := db.Raw("SELECT category, COUNT(*) AS count FROM overtimes WHERE category IS NOT NULL AND TRIM(category) <> '' GROUP BY category").
+		Scan(&values).Error
+	if err != nil {
+		return err
+	}
+
+	aliases := categoryAliases()
+	groups := map[string][]legacyValue{}
+	for _, value := range values {
+		key := categoryKey(value.Category)
+		if alias, ok := aliases[key]; ok {
+			key = alias
+		}
+		groups[key] = append(groups[key], value)
+	}
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, key := range keys {
+			spellings := groups[key]
+			sort.Slice(spellings, func(i, j int) bool {
+				if spellings[i].Count == spellings[j].Count {
+					return spellings[i].Category < spellings[j].Category
+				}
+				return spellings[i].Count > spellings[j].Count
+			})
+
+			var category entities.Category
+			err := tx.Where("lower(name) = ?", key).First(&category).Error
+			if err == gorm.ErrRecordNotFound {
+				category = entities.Category{Name: strings.Join(strings.Fields(spellings[0].Category), " "), Active: true, RequiresApproval: true}
+				err = tx.Create(&category).Error
+			}
+			if err != nil {
+				return err
+			}
+
+			originals := make([]string, 0, len(spellings))
+			for _, spelling := range spellings {
+				originals = append(originals, spelling.Category)
+			}
+			if err := tx.Exec("UPDATE overtimes SET category_id = ? WHERE category IN ?", category.ID, originals).Error; err != nil {
+				return err
+			}
+			log.Printf("Category %q created from %d spelling(s): %s", category.Name, len(originals), strings.Join(originals, ", "))
+		}
+		if err := tx.Exec("ALTER TABLE overtimes DROP COLUMN category").Error; err != nil {
+			return err
+		}
+		return seedCategories(tx)
+	})
+}
+
+func seedCategories(db *gorm.DB) error {
+	var count int64
+	if err := db.Model(&entities.Category{}).Count(&count).Error; err != nil || count > 0 {
+		return err
+	}
+	for _, name := range defaultCategories {
+		if err := db.Create(&entities.Category{Name: name, Active: true, RequiresApproval: true}).Error; err != nil {
+			return err
+		}
+	}
+	log.Println("Default categories created: ", strings.Join(defaultCategories, ", "))
+	return nil
+}
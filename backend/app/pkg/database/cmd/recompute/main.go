@@ -0,0 +1,13 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/database"
+)
+
+func main() {
+	fix := flag.Bool("fix", false, "store the computed duration for mismatched rows (default only reports)")
+	flag.Parse()
+	database.RunRecomputeOvertimeDuration(*fix)
+}
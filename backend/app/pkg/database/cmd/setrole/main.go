@@ -0,0 +1,18 @@
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/database"
+)
+
+func main() {
+	username := flag.String("username", "", "username of the user")
+	role := flag.String("role", "", "new role: user, approver or admin")
+	flag.Parse()
+	if *username == "" || *role == "" {
+		log.Fatal("Usage: setrole -username <username> -role <user|approver|admin>")
+	}
+	database.RunSetUserRole(*username, *role)
+}
@@ -0,0 +1,61 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/entities"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// exclusionViolation is the SQLSTATE of ADD CONSTRAINT overtimes_no_overlap when existing rows already overlap
+const exclusionViolation = "23P01"
+
+// migrateConstraints adds constraints AutoMigrate cannot express. Each step is idempotent.
+func migrateConstraints(db *gorm.DB) error {
+	// btree_gist is needed to combine telegram_user_id (=) and the time range (&&) in one gist index
+	if err := db.Exec("CREATE EXTENSION IF NOT EXISTS btree_gist").Error; err != nil {
+		return err
+	}
+
+	// the constraint ignores soft deleted rows, a version created before soft delete is replaced
+	var definition string
+	if err := db.Raw("SELECT COALESCE((SELECT pg_get_constraintdef(oid) FROM pg_constraint WHERE conname = 'overtimes_no_overlap'), '')").Scan(&definition).Error; err != nil {
+		return err
+	}
+	if definition != "" && !strings.Contains(definition, "deleted_at IS NULL") {
+		if err := db.Exec("ALTER TABLE overtimes DROP CONSTRAINT overtimes_no_overlap").Error; err != nil {
+			return err
+		}
+		definition = ""
+	}
+	if definition == "" {
+		err := db.Exec("ALTER TABLE overtimes ADD CONSTRAINT overtimes_no_overlap EXCLUDE USING gist (telegram_user_id WITH =, " + entities.OvertimeRangeSQL + " WITH &&) WHERE (" + entities.OvertimeNotDeletedSQL + ")").Error
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == exclusionViolation {
+			// existing overlapping rows have to be cleaned up first, the service still rejects new overlaps
+			var pairs []struct{ ID, OtherID uint }
+			if err := db.Raw("WITH spans AS (SELECT id, telegram_user_id, " + entities.OvertimeRangeSQL + " AS span FROM overtimes WHERE " + entities.OvertimeNotDeletedSQL + ") " +
+				"SELECT a.id, b.id AS other_id FROM spans a JOIN spans b ON a.telegram_user_id = b.telegram_user_id AND a.id < b.id AND a.span && b.span " +
+				"ORDER BY a.id, b.id LIMIT 50").Scan(&pairs).Error; err != nil {
+				return err
+			}
+			conflicts := make([]string, 0, len(pairs))
+			for _, pair := range pairs {
+				conflicts = append(conflicts, fmt.Sprintf("#%d-#%d", pair.ID, pair.OtherID))
+			}
+			log.Println("Warning: overtimes_no_overlap not created, fix overlapping overtime records and migrate again:", strings.Join(conflicts, ", "))
+		} else if err != nil {
+			return err
+		}
+	}
+
+	// category names are unique regardless of case, "Deploy" and "deploy" are the same category
+	if err := db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_name_lower ON categories (lower(name))").Error; err != nil {
+		return err
+	}
+	return nil
+}
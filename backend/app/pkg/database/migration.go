@@ -20,12 +20,31 @@ func RunMigration() {
 		&entities.User{},
 		&entities.APIKey{},
 		&entities.TelegramUser{},
+		&entities.Category{},
 		&entities.Overtime{},
 		&entities.LogRequest{},
+		&entities.TelegramUpdate{},
+		&entities.TelegramPollingState{},
+		&entities.ConversationState{},
+		&entities.TelegramLinkCode{},
+		&entities.OvertimeStatusHistory{},
+		&entities.PayProfile{},
+		&entities.OvertimeLimit{},
+		&entities.Holiday{},
+		&entities.OvertimeFeedToken{},
+		&entities.OvertimeAttachment{},
 	)
 	if err != nil {
 		log.Fatal("Error migrating database: ", err)
 		return
 	}
+	if err := migrateConstraints(ClientPostgres); err != nil {
+		log.Fatal("Error migrating constraints: ", err)
+		return
+	}
+	if err := migrateCategories(ClientPostgres); err != nil {
+		log.Fatal("Error migrating overtime categories: ", err)
+		return
+	}
 	log.Println("Migration completed")
 }
\ No newline at end of file
@@ -0,0 +1,61 @@
+package database
+
+import (
+	"log"
+	"math"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/entities"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/helpers"
+	"github.com/joho/godotenv"
+	"gorm.io/gorm"
+)
+
+// RunRecomputeOvertimeDuration reports overtimes whose duration does not match time_start, time_stop
+// and break_duration. With fix the stored duration is replaced by the computed one.
+func RunRecomputeOvertimeDuration(fix bool) {
+	if err := godotenv.Load(); err != nil {
+		log.Fatal("Error loading .env file")
+	}
+	if err := PGOpen(); err != nil {
+		log.Fatal("Error connecting to database")
+		return
+	}
+
+	checked, mismatched, fixed, invalid := 0, 0, 0, 0
+	var overtimes []entities.Overtime
+	result := ClientPostgres.Order("id").FindInBatches(&overtimes, 500, func(batch *gorm.DB, _ int) error {
+		for _, overtime := range overtimes {
+			checked++
+			duration, err := helpers.ComputeOvertimeDuration(overtime.TimeStart, overtime.TimeStop, overtime.BreakDuration)
+			if err != nil {
+				invalid++
+				log.Printf("overtime %d (%s): cannot compute duration: %v", overtime.ID, overtime.Date.Format("2006-01-02"), err)
+				continue
+			}
+			if math.Abs(duration-overtime.Duration) < 0.005 {
+				continue
+			}
+			mismatched++
+			log.Printf("overtime %d (%s): stored %.2f, computed %.2f (%s-%s, break %.2f)", overtime.ID, overtime.Date.Format("2006-01-02"),
+				overtime.Duration, duration, overtime.TimeStart.Format("15:04"), overtime.TimeStop.Format("15:04"), overtime.BreakDuration)
+			if !fix {
+				continue
+			}
+			// UpdateColumn keeps updated_at, this is a data fix and not a user edit
+			if err := ClientPostgres.Model(&entities.Overtime{}).Where("id = ?", overtime.ID).UpdateColumn("duration", duration).Error; err != nil {
+				return err
+			}
+			fixed++
+		}
+		return nil
+	})
+	if result.Error != nil {
+		log.Fatal("Error recomputing overtime duration: ", result.Error)
+		return
+	}
+
+	log.Printf("Checked %d overtimes: %d mismatched, %d fixed, %d invalid", checked, mismatched, fixed, invalid)
+	if mismatched > fixed {
+		log.Println("Run again with -fix to store the computed durations")
+	}
+}
@@ -0,0 +1,32 @@
+package database
+
+import (
+	"log"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/entities"
+	"github.com/joho/godotenv"
+)
+
+// RunSetUserRole mengubah role user berdasarkan username, dipakai untuk membuat admin pertama
+func RunSetUserRole(username string, role string) {
+	if role != entities.RoleUser && role != entities.RoleApprover && role != entities.RoleAdmin {
+		log.Fatalf("Invalid role %q, use user, approver or admin", role)
+	}
+	if err := godotenv.Load(); err != nil {
+		log.Fatal("Error loading .env file")
+	}
+	if err := PGOpen(); err != nil {
+		log.Fatal("Error connecting to database")
+		return
+	}
+
+	result := ClientPostgres.Model(&entities.User{}).Where("username = ?", username).Update("role", role)
+	if result.Error != nil {
+		log.Fatal("Error updating role: ", result.Error)
+		return
+	}
+	if result.RowsAffected == 0 {
+		log.Fatalf("User %q not found", username)
+	}
+	log.Printf("Role of %s set to %s", username, role)
+}
@@ -0,0 +1,83 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/entities"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/blobstore"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/helpers"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/scheduler"
+)
+
+// OvertimeTrashRetentionDays reads OVERTIME_TRASH_RETENTION_DAYS, how long soft deleted overtimes stay restorable.
+// 0 keeps the trash forever.
+func OvertimeTrashRetentionDays() (int, error) {
+	days, err := strconv.Atoi(helpers.GetEnv("OVERTIME_TRASH_RETENTION_DAYS", "30"))
+	if err != nil || days < 0 {
+		return 0, fmt.Errorf("OVERTIME_TRASH_RETENTION_DAYS: must be a number of days, 0 = never purge")
+	}
+	return days, nil
+}
+
+// PurgeTrashedOvertimes permanently deletes overtimes that were soft deleted before cutoff, their status history
+// and attachments go with them. Stored attachment files are removed from blobstore afterwards.
+func PurgeTrashedOvertimes(ctx context.Context, cutoff time.Time) (int64, error) {
+	trashed := ClientPostgres.Unscoped().
+		Model(&entities.Overtime{}).
+		Select("id").
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff)
+	var storageKeys []string
+	if err := ClientPostgres.WithContext(ctx).
+		Model(&entities.OvertimeAttachment{}).
+		Where("overtime_id IN (?)", trashed).
+		Pluck("storage_key", &storageKeys).Error; err != nil {
+		return 0, err
+	}
+
+	result := ClientPostgres.WithContext(ctx).
+		Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+		Delete(&entities.Overtime{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	// file yang gagal dihapus hanya dicatat, record sudah terhapus permanen
+	for _, key := range storageKeys {
+		if err := blobstore.Client.Delete(ctx, key); err != nil {
+			helpers.Logger.Error().Str("type", "scheduler").Str("job", "overtime_trash_purge").Str("storage_key", key).Err(err).Msg("Error removing attachment file")
+		}
+	}
+	return result.RowsAffected, nil
+}
+
+// TrashJobs returns the daily purge of the overtime trash at OVERTIME_TRASH_PURGE_TIME (default 02:00),
+// none when the retention is 0
+func TrashJobs() ([]scheduler.Job, error) {
+	days, err := OvertimeTrashRetentionDays()
+	if err != nil {
+		return nil, err
+	}
+	if days == 0 {
+		return nil, nil
+	}
+	hour, minute, err := scheduler.ParseClock(helpers.GetEnv("OVERTIME_TRASH_PURGE_TIME", "02:00"))
+	if err != nil {
+		return nil, fmt.Errorf("OVERTIME_TRASH_PURGE_TIME: %w", err)
+	}
+	return []scheduler.Job{{
+		Name: "overtime_trash_purge",
+		Next: scheduler.DailyAt(hour, minute),
+		Run: func(ctx context.Context) error {
+			purged, err := PurgeTrashedOvertimes(ctx, time.Now().AddDate(0, 0, -days))
+			if err != nil {
+				return err
+			}
+			helpers.Logger.Info().Str("type", "scheduler").Str("job", "overtime_trash_purge").Int64("purged", purged).Int("retention_days", days).Msg("Overtime trash purged")
+			return nil
+		},
+	}}, nil
+}
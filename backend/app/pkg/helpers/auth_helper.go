@@ -37,6 +37,14 @@ func GetAuthType(c *fiber.Ctx) string {
 	return "none"
 }
 
+// GetCurrentTelegramID mendapatkan telegram ID dari context (hanya untuk auth telegram_webapp)
+func GetCurrentTelegramID(c *fiber.Ctx) int64 {
+	if telegramID, ok := c.Locals("telegram_id").(int64); ok {
+		return telegramID
+	}
+	return 0
+}
+
 // IsAuthenticated mengecek apakah user sudah terautentikasi
 func IsAuthenticated(c *fiber.Ctx) bool {
 	return GetCurrentUserID(c) != 0
@@ -18,6 +18,10 @@ func IsDuplicateKeyError(err error) bool {
 	return strings.Contains(err.Error(), "duplicate key value violates unique constraint")
 }
 
+func IsExclusionViolationError(err error) bool {
+	return strings.Contains(err.Error(), "violates exclusion constraint")
+}
+
 func IsNullConstraintError(err error) bool {
 	return strings.Contains(err.Error(), "null value in column")
 }
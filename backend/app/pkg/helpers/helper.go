@@ -16,7 +16,6 @@ type Payload interface {
 func ValidateBody(payload Payload, c *fiber.Ctx) error {
 	// log.Debug("Validating request body ", payload)
 	// Logger.Debug().Interface("payload", payload).Msg("Validating request body")
-	validate := validator.New()
 
 	// Parse the request body
 	if err := c.BodyParser(&payload); err != nil {
@@ -28,6 +27,13 @@ func ValidateBody(payload Payload, c *fiber.Ctx) error {
 	// log.Debug("Validating after body parser ", payload)
 	// Logger.Debug().Interface("payload", payload).Msg("Validating after body parser")
 
+	return ValidateStruct(payload)
+}
+
+// ValidateStruct validates a payload that did not come from a request body (e.g. built by the bot)
+func ValidateStruct(payload Payload) error {
+	validate := validator.New()
+
 	// Validate the user struct
 	if err := validate.Struct(payload); err != nil {
 		validationErrors := err.(validator.ValidationErrors)
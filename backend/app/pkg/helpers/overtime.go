@@ -0,0 +1,52 @@
+package helpers
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// ComputeOvertimeDuration menghitung durasi lembur dalam jam dari jam mulai, jam selesai dan istirahat.
+// Hanya jam dan menit yang dipakai (kolom time_start/time_stop bertipe time), jika selesai <= mulai
+// lembur dianggap melewati tengah malam. Hasil dibulatkan 2 desimal sesuai kolom decimal(4,2).
+// Dipakai create, update, import, cmd/recompute dan parser bot, jangan diduplikasi.
+func ComputeOvertimeDuration(timeStart time.Time, timeStop time.Time, breakDuration float64) (float64, error) {
+	span := overtimeSpanMinutes(timeStart, timeStop)
+	if breakDuration < 0 {
+		return 0, fmt.Errorf("break duration must be greater than or equal to 0")
+	}
+	if breakDuration*60 >= float64(span) {
+		return 0, fmt.Errorf("break duration (%.2f hours) must be shorter than the time between time_start and time_stop (%.2f hours)", breakDuration, float64(span)/60)
+	}
+	return math.Round((float64(span)/60-breakDuration)*100) / 100, nil
+}
+
+// OvertimeSpanHours returns the hours between timeStart and timeStop before the break, past midnight when stop <= start
+func OvertimeSpanHours(timeStart time.Time, timeStop time.Time) float64 {
+	return float64(overtimeSpanMinutes(timeStart, timeStop)) / 60
+}
+
+func overtimeSpanMinutes(timeStart time.Time, timeStop time.Time) int {
+	span := (timeStop.Hour()*60 + timeStop.Minute()) - (timeStart.Hour()*60 + timeStart.Minute())
+	if span <= 0 {
+		span += 24 * 60
+	}
+	return span
+}
+
+// GetOvertimeDurationTolerance returns the allowed difference in hours between a client supplied
+// duration and the computed one, from OVERTIME_DURATION_TOLERANCE in minutes (default 5)
+func GetOvertimeDurationTolerance() float64 {
+	minutes, err := strconv.ParseFloat(GetEnv("OVERTIME_DURATION_TOLERANCE", "5"), 64)
+	if err != nil || minutes < 0 {
+		minutes = 5
+	}
+	return minutes / 60
+}
+
+// DurationMatches reports whether provided is within the configured tolerance of computed
+func DurationMatches(provided float64, computed float64) bool {
+	// small epsilon so a difference of exactly the tolerance is accepted despite float rounding
+	return math.Abs(provided-computed) <= GetOvertimeDurationTolerance()+1e-9
+}
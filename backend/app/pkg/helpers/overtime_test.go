@@ -0,0 +1,81 @@
+package helpers
+
+import (
+	"testing"
+	"time"
+)
+
+func clock(hour, minute int) time.Time {
+	return time.Date(0, 1, 1, hour, minute, 0, 0, time.UTC)
+}
+
+func TestComputeOvertimeDuration(t *testing.T) {
+	tests := []struct {
+		name          string
+		start, stop   time.Time
+		breakDuration float64
+		want          float64
+		wantErr       bool
+	}{
+		{name: "same day", start: clock(18, 0), stop: clock(21, 30), want: 3.5},
+		{name: "same day with break", start: clock(17, 0), stop: clock(21, 0), breakDuration: 1.5, want: 2.5},
+		{name: "past midnight", start: clock(22, 0), stop: clock(2, 0), breakDuration: 1, want: 3},
+		{name: "stop at midnight", start: clock(20, 0), stop: clock(0, 0), want: 4},
+		{name: "zero span is a full day", start: clock(8, 0), stop: clock(8, 0), want: 24},
+		{name: "seconds are ignored", start: time.Date(0, 1, 1, 18, 0, 59, 0, time.UTC), stop: clock(19, 0), want: 1},
+		{name: "rounded to two decimals", start: clock(18, 0), stop: clock(18, 20), want: 0.33},
+		{name: "break equals span", start: clock(18, 0), stop: clock(20, 0), breakDuration: 2, wantErr: true},
+		{name: "break longer than span", start: clock(23, 0), stop: clock(1, 0), breakDuration: 3, wantErr: true},
+		{name: "negative break", start: clock(18, 0), stop: clock(20, 0), breakDuration: -0.5, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ComputeOvertimeDuration(tt.start, tt.stop, tt.breakDuration)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ComputeOvertimeDuration() = %v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ComputeOvertimeDuration() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ComputeOvertimeDuration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOvertimeSpanHours(t *testing.T) {
+	if got := OvertimeSpanHours(clock(22, 0), clock(1, 30)); got != 3.5 {
+		t.Errorf("OvertimeSpanHours(22:00, 01:30) = %v, want 3.5", got)
+	}
+}
+
+func TestDurationMatches(t *testing.T) {
+	tests := []struct {
+		name      string
+		tolerance string
+		provided  float64
+		computed  float64
+		want      bool
+	}{
+		{name: "equal", provided: 3, computed: 3, want: true},
+		{name: "exactly the default 5 minutes over", provided: 3 + 5.0/60, computed: 3, want: true},
+		{name: "exactly the default 5 minutes under", provided: 3 - 5.0/60, computed: 3, want: true},
+		{name: "6 minutes over", provided: 3 + 6.0/60, computed: 3, want: false},
+		{name: "rounded client value", provided: 0.33, computed: 1.0 / 3, want: true},
+		{name: "zero tolerance", tolerance: "0", provided: 3.01, computed: 3, want: false},
+		{name: "custom tolerance", tolerance: "15", provided: 3.25, computed: 3, want: true},
+		{name: "invalid tolerance falls back to 5 minutes", tolerance: "-1", provided: 3 + 6.0/60, computed: 3, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("OVERTIME_DURATION_TOLERANCE", tt.tolerance)
+			if got := DurationMatches(tt.provided, tt.computed); got != tt.want {
+				t.Errorf("DurationMatches(%v, %v) = %v, want %v", tt.provided, tt.computed, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package helpers
 
 import (
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/pagination"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -12,6 +13,16 @@ func Response(c *fiber.Ctx, status int, message string, data interface{}) error
 	})
 }
 
+// ResponsePage writes one page of a list, data stays an array and the cursor and total count go into pagination
+func ResponsePage(c *fiber.Ctx, status int, message string, data interface{}, page pagination.Info) error {
+	return c.Status(status).JSON(fiber.Map{
+		"code":       status,
+		"message":    message,
+		"data":       data,
+		"pagination": page,
+	})
+}
+
 func ResponseErrorInternal(c *fiber.Ctx, err any) error {
 	// log.Error("Internal server error: ", err)
 	return Response(c, fiber.StatusInternalServerError, "Internal server error", nil)
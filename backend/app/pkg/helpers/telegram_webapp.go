@@ -0,0 +1,108 @@
+package helpers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TelegramWebAppUser is the "user" field of Telegram WebApp initData
+type TelegramWebAppUser struct {
+	ID           int64  `json:"id"`
+	Username     string `json:"username"`
+	FirstName    string `json:"first_name"`
+	LastName     string `json:"last_name"`
+	LanguageCode string `json:"language_code"`
+}
+
+// TelegramInitData is the validated content of Telegram WebApp initData
+type TelegramInitData struct {
+	User     TelegramWebAppUser
+	AuthDate time.Time
+	QueryID  string
+}
+
+var (
+	ErrInitDataMissingHash = errors.New("init data hash is missing")
+	ErrInitDataInvalidHash = errors.New("init data hash is invalid")
+	ErrInitDataExpired     = errors.New("init data is expired")
+	ErrInitDataFromFuture  = errors.New("init data auth_date is in the future")
+	ErrInitDataMissingUser = errors.New("init data user is missing")
+)
+
+// telegramInitDataClockSkew is how far auth_date may be ahead of the server clock
+const telegramInitDataClockSkew = time.Minute
+
+// GetTelegramInitDataMaxAge returns how long initData is accepted after auth_date
+func GetTelegramInitDataMaxAge() time.Duration {
+	seconds, err := strconv.Atoi(GetEnv("TELEGRAM_INIT_DATA_MAX_AGE", "86400"))
+	if err != nil || seconds <= 0 {
+		seconds = 86400
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// ValidateTelegramInitData memvalidasi initData dari Telegram WebApp
+// https://core.telegram.org/bots/webapps#validating-data-received-via-the-mini-app
+func ValidateTelegramInitData(initData string, botToken string, maxAge time.Duration) (TelegramInitData, error) {
+	values, err := url.ParseQuery(initData)
+	if err != nil {
+		return TelegramInitData{}, err
+	}
+
+	hash := values.Get("hash")
+	if hash == "" {
+		return TelegramInitData{}, ErrInitDataMissingHash
+	}
+
+	// data-check-string: semua field kecuali hash, diurutkan berdasarkan key, dipisah \n
+	pairs := make([]string, 0, len(values))
+	for key := range values {
+		if key == "hash" {
+			continue
+		}
+		pairs = append(pairs, key+"="+values.Get(key))
+	}
+	sort.Strings(pairs)
+	dataCheckString := strings.Join(pairs, "\n")
+
+	secretKey := hmac.New(sha256.New, []byte("WebAppData"))
+	secretKey.Write([]byte(botToken))
+	mac := hmac.New(sha256.New, secretKey.Sum(nil))
+	mac.Write([]byte(dataCheckString))
+	expectedHash := hex.EncodeToString(mac.Sum(nil))
+
+	if !hmac.Equal([]byte(expectedHash), []byte(hash)) {
+		return TelegramInitData{}, ErrInitDataInvalidHash
+	}
+
+	authDateUnix, err := strconv.ParseInt(values.Get("auth_date"), 10, 64)
+	if err != nil {
+		return TelegramInitData{}, errors.New("init data auth_date is invalid")
+	}
+	authDate := time.Unix(authDateUnix, 0)
+	if time.Until(authDate) > telegramInitDataClockSkew {
+		return TelegramInitData{}, ErrInitDataFromFuture
+	}
+	if time.Since(authDate) > maxAge {
+		return TelegramInitData{}, ErrInitDataExpired
+	}
+
+	var user TelegramWebAppUser
+	if err := json.Unmarshal([]byte(values.Get("user")), &user); err != nil || user.ID == 0 {
+		return TelegramInitData{}, ErrInitDataMissingUser
+	}
+
+	return TelegramInitData{
+		User:     user,
+		AuthDate: authDate,
+		QueryID:  values.Get("query_id"),
+	}, nil
+}
@@ -0,0 +1,148 @@
+package helpers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testBotToken = "123456789:AAHdqTcvCH1vGWJxfSeofSAs0K5PALDsaw"
+	testUser     = `{"id":279058397,"first_name":"Vladislav","last_name":"Kibenko","username":"vdkfrost","language_code":"ru"}`
+)
+
+// signInitData builds initData for fields signed with botToken the way Telegram does
+func signInitData(fields map[string]string, botToken string) string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	values := url.Values{}
+	for _, key := range keys {
+		pairs = append(pairs, key+"="+fields[key])
+		values.Set(key, fields[key])
+	}
+	secretKey := hmac.New(sha256.New, []byte("WebAppData"))
+	secretKey.Write([]byte(botToken))
+	mac := hmac.New(sha256.New, secretKey.Sum(nil))
+	mac.Write([]byte(strings.Join(pairs, "\n")))
+	values.Set("hash", hex.EncodeToString(mac.Sum(nil)))
+	return values.Encode()
+}
+
+func initDataAt(authDate time.Time) map[string]string {
+	return map[string]string{
+		"auth_date": strconv.FormatInt(authDate.Unix(), 10),
+		"query_id":  "AAHdF6IQAAAAAN0XohDhrOrc",
+		"user":      testUser,
+	}
+}
+
+// TestValidateTelegramInitDataKnownHash checks a hash computed outside Go, so signInitData cannot hide a bug in both
+func TestValidateTelegramInitDataKnownHash(t *testing.T) {
+	initData := "auth_date=1700000000&query_id=AAHdF6IQAAAAAN0XohDhrOrc&user=" + url.QueryEscape(testUser) +
+		"&hash=21fa6a017326b723f61aaa967db6282f26f94cfe3814df290188a8d76b4f9998"
+	data, err := ValidateTelegramInitData(initData, testBotToken, 100*365*24*time.Hour)
+	if err != nil {
+		t.Fatalf("ValidateTelegramInitData() error = %v", err)
+	}
+	if data.User.ID != 279058397 || data.User.Username != "vdkfrost" || data.QueryID != "AAHdF6IQAAAAAN0XohDhrOrc" || data.AuthDate.Unix() != 1700000000 {
+		t.Errorf("ValidateTelegramInitData() = %+v", data)
+	}
+}
+
+func TestValidateTelegramInitData(t *testing.T) {
+	now := time.Now()
+	maxAge := 24 * time.Hour
+	tests := []struct {
+		name     string
+		initData func() string
+		wantErr  error
+	}{
+		{
+			name:     "valid",
+			initData: func() string { return signInitData(initDataAt(now.Add(-time.Minute)), testBotToken) },
+		},
+		{
+			name:     "small clock skew",
+			initData: func() string { return signInitData(initDataAt(now.Add(30*time.Second)), testBotToken) },
+		},
+		{
+			name: "tampered user",
+			initData: func() string {
+				values, _ := url.ParseQuery(signInitData(initDataAt(now), testBotToken))
+				values.Set("user", strings.Replace(testUser, "279058397", "1", 1))
+				return values.Encode()
+			},
+			wantErr: ErrInitDataInvalidHash,
+		},
+		{
+			name: "tampered auth_date",
+			initData: func() string {
+				values, _ := url.ParseQuery(signInitData(initDataAt(now.Add(-48*time.Hour)), testBotToken))
+				values.Set("auth_date", strconv.FormatInt(now.Unix(), 10))
+				return values.Encode()
+			},
+			wantErr: ErrInitDataInvalidHash,
+		},
+		{
+			name:     "other bot token",
+			initData: func() string { return signInitData(initDataAt(now), "987654321:OTHER") },
+			wantErr:  ErrInitDataInvalidHash,
+		},
+		{
+			name: "missing hash",
+			initData: func() string {
+				values, _ := url.ParseQuery(signInitData(initDataAt(now), testBotToken))
+				values.Del("hash")
+				return values.Encode()
+			},
+			wantErr: ErrInitDataMissingHash,
+		},
+		{
+			name:     "stale auth_date",
+			initData: func() string { return signInitData(initDataAt(now.Add(-maxAge-time.Minute)), testBotToken) },
+			wantErr:  ErrInitDataExpired,
+		},
+		{
+			name:     "future auth_date",
+			initData: func() string { return signInitData(initDataAt(now.Add(10*time.Minute)), testBotToken) },
+			wantErr:  ErrInitDataFromFuture,
+		},
+		{
+			name: "missing user",
+			initData: func() string {
+				fields := initDataAt(now)
+				delete(fields, "user")
+				return signInitData(fields, testBotToken)
+			},
+			wantErr: ErrInitDataMissingUser,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := ValidateTelegramInitData(tt.initData(), testBotToken, maxAge)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ValidateTelegramInitData() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ValidateTelegramInitData() error = %v", err)
+			}
+			if data.User.ID != 279058397 {
+				t.Errorf("User.ID = %d, want 279058397", data.User.ID)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+// Package limits checks overtime hours against daily, weekly and monthly caps.
+// The statutory caps are 4 hours a day and 18 hours a week (PP 35/2021 pasal 26), there is no statutory monthly cap.
+package limits
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Statutory caps of PP 35/2021
+const (
+	StatutoryDailyHours  = 4
+	StatutoryWeeklyHours = 18
+)
+
+// Mode decides what happens to a record that exceeds a limit
+const (
+	ModeWarn            = "warn"             // save it and return warnings
+	ModeRequireApproval = "require_approval" // save it as submitted so an approver has to decide
+	ModeBlock           = "block"            // reject it
+)
+
+const (
+	PeriodDay   = "day"
+	PeriodWeek  = "week"
+	PeriodMonth = "month"
+)
+
+// Policy holds the caps in hours, 0 disables a cap
+type Policy struct {
+	DailyHours   float64 `json:"daily_hours"`
+	WeeklyHours  float64 `json:"weekly_hours"`
+	MonthlyHours float64 `json:"monthly_hours"`
+	Mode         string  `json:"mode"`
+}
+
+// Usage is the overtime of an employee in the day, week and month of a record, including that record
+type Usage struct {
+	DayHours   float64
+	WeekHours  float64
+	MonthHours float64
+}
+
+// Violation reports one period whose hours exceed its cap
+type Violation struct {
+	Period      string  `json:"period"`
+	LimitHours  float64 `json:"limit_hours"`
+	Hours       float64 `json:"hours"`
+	ExcessHours float64 `json:"excess_hours"`
+	Message     string  `json:"message"`
+}
+
+// ValidMode reports whether mode is warn, require_approval or block
+func ValidMode(mode string) bool {
+	return mode == ModeWarn || mode == ModeRequireApproval || mode == ModeBlock
+}
+
+// Validate rejects negative caps and unknown modes
+func (p Policy) Validate() error {
+	if p.DailyHours < 0 || p.WeeklyHours < 0 || p.MonthlyHours < 0 {
+		return fmt.Errorf("limits must be greater than or equal to 0")
+	}
+	if !ValidMode(p.Mode) {
+		return fmt.Errorf("unknown limit mode %q, use warn, require_approval or block", p.Mode)
+	}
+	return nil
+}
+
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// Check returns the violated caps from the shortest period to the longest
+func (p Policy) Check(usage Usage) []Violation {
+	var violations []Violation
+	for _, period := range []struct {
+		name  string
+		label string
+		limit float64
+		hours float64
+	}{
+		{PeriodDay, "daily", p.DailyHours, usage.DayHours},
+		{PeriodWeek, "weekly", p.WeeklyHours, usage.WeekHours},
+		{PeriodMonth, "monthly", p.MonthlyHours, usage.MonthHours},
+	} {
+		hours := round(period.hours)
+		// small epsilon so a total of exactly the cap is accepted despite float rounding
+		if period.limit <= 0 || hours <= period.limit+1e-9 {
+			continue
+		}
+		violations = append(violations, Violation{
+			Period:      period.name,
+			LimitHours:  period.limit,
+			Hours:       hours,
+			ExcessHours: round(hours - period.limit),
+			Message:     fmt.Sprintf("%s overtime of %.2f hours exceeds the limit of %.2f hours", period.label, hours, period.limit),
+		})
+	}
+	return violations
+}
+
+// WeekRange returns Monday and Sunday of the week of date
+func WeekRange(date time.Time) (time.Time, time.Time) {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	offset := (int(day.Weekday()) + 6) % 7
+	start := day.AddDate(0, 0, -offset)
+	return start, start.AddDate(0, 0, 6)
+}
+
+// MonthRange returns the first and last day of the month of date
+func MonthRange(date time.Time) (time.Time, time.Time) {
+	start := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, date.Location())
+	return start, start.AddDate(0, 1, -1)
+}
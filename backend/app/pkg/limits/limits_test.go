@@ -0,0 +1,118 @@
+package limits
+
+import (
+	"testing"
+	"time"
+)
+
+var wib = time.FixedZone("WIB", 7*60*60)
+
+func TestCheck(t *testing.T) {
+	statutory := Policy{DailyHours: StatutoryDailyHours, WeeklyHours: StatutoryWeeklyHours, Mode: ModeWarn}
+
+	tests := []struct {
+		name    string
+		policy  Policy
+		usage   Usage
+		periods []string
+		excess  []float64
+	}{
+		{
+			name:   "within limits",
+			policy: statutory,
+			usage:  Usage{DayHours: 3.5, WeekHours: 12, MonthHours: 40},
+		},
+		{
+			name:   "exactly at the limits",
+			policy: statutory,
+			usage:  Usage{DayHours: 4, WeekHours: 18, MonthHours: 60},
+		},
+		{
+			name:    "daily only",
+			policy:  statutory,
+			usage:   Usage{DayHours: 5.25, WeekHours: 10, MonthHours: 20},
+			periods: []string{PeriodDay},
+			excess:  []float64{1.25},
+		},
+		{
+			name:    "daily and weekly",
+			policy:  statutory,
+			usage:   Usage{DayHours: 4.5, WeekHours: 19, MonthHours: 19},
+			periods: []string{PeriodDay, PeriodWeek},
+			excess:  []float64{0.5, 1},
+		},
+		{
+			name:    "monthly cap",
+			policy:  Policy{DailyHours: 4, WeeklyHours: 18, MonthlyHours: 56, Mode: ModeBlock},
+			usage:   Usage{DayHours: 2, WeekHours: 8, MonthHours: 58},
+			periods: []string{PeriodMonth},
+			excess:  []float64{2},
+		},
+		{
+			name:   "disabled caps",
+			policy: Policy{Mode: ModeWarn},
+			usage:  Usage{DayHours: 12, WeekHours: 60, MonthHours: 200},
+		},
+		{
+			name:   "float noise is rounded",
+			policy: statutory,
+			usage:  Usage{DayHours: 1.1 + 1.2 + 1.7, WeekHours: 4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations := tt.policy.Check(tt.usage)
+			if len(violations) != len(tt.periods) {
+				t.Fatalf("got %d violations, want %d: %+v", len(violations), len(tt.periods), violations)
+			}
+			for i, violation := range violations {
+				if violation.Period != tt.periods[i] || violation.ExcessHours != tt.excess[i] {
+					t.Errorf("violation %d = {%s %.2f}, want {%s %.2f}", i, violation.Period, violation.ExcessHours, tt.periods[i], tt.excess[i])
+				}
+				if violation.Message == "" {
+					t.Errorf("violation %d has no message", i)
+				}
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (Policy{DailyHours: 4, WeeklyHours: 18, Mode: ModeRequireApproval}).Validate(); err != nil {
+		t.Errorf("valid policy rejected: %v", err)
+	}
+	if err := (Policy{DailyHours: -1, Mode: ModeWarn}).Validate(); err == nil {
+		t.Error("negative cap accepted")
+	}
+	if err := (Policy{DailyHours: 4, Mode: "strict"}).Validate(); err == nil {
+		t.Error("unknown mode accepted")
+	}
+}
+
+func TestWeekRange(t *testing.T) {
+	tests := []struct {
+		date  time.Time
+		start string
+		end   string
+	}{
+		{time.Date(2024, time.March, 13, 18, 0, 0, 0, wib), "2024-03-11", "2024-03-17"}, // wednesday
+		{time.Date(2024, time.March, 11, 0, 0, 0, 0, wib), "2024-03-11", "2024-03-17"},  // monday
+		{time.Date(2024, time.March, 17, 23, 0, 0, 0, wib), "2024-03-11", "2024-03-17"}, // sunday
+		{time.Date(2024, time.January, 2, 0, 0, 0, 0, wib), "2024-01-01", "2024-01-07"},
+		{time.Date(2023, time.December, 31, 0, 0, 0, 0, wib), "2023-12-25", "2023-12-31"},
+	}
+	for _, tt := range tests {
+		start, end := WeekRange(tt.date)
+		if start.Format("2006-01-02") != tt.start || end.Format("2006-01-02") != tt.end {
+			t.Errorf("WeekRange(%s) = %s..%s, want %s..%s", tt.date.Format("2006-01-02"), start.Format("2006-01-02"), end.Format("2006-01-02"), tt.start, tt.end)
+		}
+	}
+}
+
+func TestMonthRange(t *testing.T) {
+	start, end := MonthRange(time.Date(2024, time.February, 14, 0, 0, 0, 0, wib))
+	if start.Format("2006-01-02") != "2024-02-01" || end.Format("2006-01-02") != "2024-02-29" {
+		t.Errorf("MonthRange = %s..%s, want 2024-02-01..2024-02-29", start.Format("2006-01-02"), end.Format("2006-01-02"))
+	}
+}
@@ -0,0 +1,167 @@
+// Package overtimecsv reads overtime records from CSV files for bulk import.
+package overtimecsv
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Columns are the canonical column names, the header row may list them in any order
+var Columns = []string{"date", "time_start", "time_stop", "break", "duration", "category", "description", "telegram_id"}
+
+// requiredColumns must be present in the header, the other columns are optional
+var requiredColumns = []string{"date", "time_start", "time_stop", "telegram_id"}
+
+// aliases maps accepted header names (Indonesian included) to the canonical column
+var aliases = map[string]string{
+	"tanggal":        "date",
+	"start":          "time_start",
+	"mulai":          "time_start",
+	"stop":           "time_stop",
+	"end":            "time_stop",
+	"selesai":        "time_stop",
+	"break_duration": "break",
+	"istirahat":      "break",
+	"durasi":         "duration",
+	"kategori":       "category",
+	"keterangan":     "description",
+	"deskripsi":      "description",
+}
+
+// Row is one data row with the raw, trimmed cell values, empty when the column is missing
+type Row struct {
+	Line        int
+	Date        string
+	TimeStart   string
+	TimeStop    string
+	Break       string
+	Duration    string
+	Category    string
+	Description string
+	TelegramID  string
+}
+
+// LineError reports a row that could not be read
+type LineError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+// Parse reads a CSV file whose first row is the header. Blank rows are ignored, malformed rows are returned as
+// LineError. A missing, duplicate or unknown header column fails the whole file.
+func Parse(r io.Reader) ([]Row, []LineError, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var rows []Row
+	var skipped []LineError
+	var header []string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) && header != nil {
+				skipped = append(skipped, LineError{Line: parseErr.Line, Error: parseErr.Err.Error()})
+				continue
+			}
+			return nil, nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		if isBlank(record) {
+			continue
+		}
+		if header == nil {
+			if header, err = parseHeader(record); err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+		if len(record) > len(header) {
+			skipped = append(skipped, LineError{Line: line, Error: fmt.Sprintf("expected %d columns, got %d", len(header), len(record))})
+			continue
+		}
+
+		row := Row{Line: line}
+		for i, value := range record {
+			value = strings.TrimSpace(value)
+			switch header[i] {
+			case "date":
+				row.Date = value
+			case "time_start":
+				row.TimeStart = value
+			case "time_stop":
+				row.TimeStop = value
+			case "break":
+				row.Break = value
+			case "duration":
+				row.Duration = value
+			case "category":
+				row.Category = value
+			case "description":
+				row.Description = value
+			case "telegram_id":
+				row.TelegramID = value
+			}
+		}
+		rows = append(rows, row)
+	}
+	if header == nil {
+		return nil, nil, fmt.Errorf("empty file, expected a header row: %s", strings.Join(Columns, ","))
+	}
+	return rows, skipped, nil
+}
+
+// parseHeader maps every header cell to its canonical column
+func parseHeader(record []string) ([]string, error) {
+	header := make([]string, len(record))
+	seen := map[string]bool{}
+	for i, cell := range record {
+		name := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(cell, "\ufeff")))
+		if canonical, ok := aliases[name]; ok {
+			name = canonical
+		}
+		if !isColumn(name) {
+			return nil, fmt.Errorf("unknown column %q, expected %s", cell, strings.Join(Columns, ","))
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate column %q", name)
+		}
+		seen[name] = true
+		header[i] = name
+	}
+	var missing []string
+	for _, name := range requiredColumns {
+		if !seen[name] {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing column %s", strings.Join(missing, ", "))
+	}
+	return header, nil
+}
+
+func isColumn(name string) bool {
+	for _, column := range Columns {
+		if column == name {
+			return true
+		}
+	}
+	return false
+}
+
+func isBlank(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
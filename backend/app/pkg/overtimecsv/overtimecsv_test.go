@@ -0,0 +1,55 @@
+package overtimecsv
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	csv := "\ufefftelegram_id,Tanggal,time_start,time_stop,break,kategori,description\n" +
+		"123456789,2024-01-15,18:00,21:00,0.5,Lembur,Deploy release\n" +
+		"\n" +
+		"123456789,2024-01-16,18:00:00,20:00:00\n" +
+		"123456789,2024-01-17,18:00,20:00,0,,note,extra\n" +
+		"987654321,2024-01-18,2024-01-18T19:00:00,2024-01-18T22:00:00,,,\"quoted, description\"\n"
+
+	rows, skipped, err := Parse(strings.NewReader(csv))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("got %d rows, want 3: %+v", len(rows), rows)
+	}
+
+	first := rows[0]
+	if first.Line != 2 || first.TelegramID != "123456789" || first.Date != "2024-01-15" || first.TimeStart != "18:00" ||
+		first.TimeStop != "21:00" || first.Break != "0.5" || first.Category != "Lembur" || first.Description != "Deploy release" {
+		t.Errorf("rows[0] = %+v", first)
+	}
+	if second := rows[1]; second.Line != 4 || second.TimeStop != "20:00:00" || second.Break != "" || second.Description != "" {
+		t.Errorf("rows[1] = %+v", second)
+	}
+	if third := rows[2]; third.Line != 6 || third.Description != "quoted, description" {
+		t.Errorf("rows[2] = %+v", third)
+	}
+
+	if len(skipped) != 1 || skipped[0].Line != 5 {
+		t.Errorf("skipped = %+v, want line 5", skipped)
+	}
+}
+
+func TestParseHeaderErrors(t *testing.T) {
+	tests := map[string]string{
+		"empty":     "\n\n",
+		"missing":   "date,time_start,time_stop\n2024-01-15,18:00,20:00\n",
+		"unknown":   "date,time_start,time_stop,telegram_id,hours\n",
+		"duplicate": "date,tanggal,time_start,time_stop,telegram_id\n",
+	}
+	for name, csv := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, _, err := Parse(strings.NewReader(csv)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+// Package pagination parses keyset (cursor) pagination parameters shared by the list endpoints.
+// A cursor is the sort value and id of the last row of a page, tied to the sort it was issued for.
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+const (
+	OrderAsc  = "asc"
+	OrderDesc = "desc"
+)
+
+// Cursor points after the last row of the previous page
+type Cursor struct {
+	Sort  string `json:"s"`
+	Order string `json:"o"`
+	Value string `json:"v"`
+	ID    uint   `json:"id"`
+}
+
+// Encode returns the opaque cursor sent to clients as next_cursor
+func (c Cursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeCursor reverses Cursor.Encode
+func DecodeCursor(value string) (Cursor, error) {
+	var cursor Cursor
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return cursor, errors.New("invalid cursor")
+	}
+	if err := json.Unmarshal(raw, &cursor); err != nil || cursor.Sort == "" || cursor.ID == 0 {
+		return cursor, errors.New("invalid cursor")
+	}
+	return cursor, nil
+}
+
+// Request is a validated page request
+type Request struct {
+	Limit int
+	Sort  string
+	Order string
+	After *Cursor // nil = first page
+}
+
+// Parse validates limit, cursor, sort and order. sort must be one of allowed, an empty sort or order uses the defaults.
+// A sort starting with "-" is descending, e.g. sort=-date is the same as sort=date&order=desc.
+func Parse(limit int, cursor string, sortKey string, order string, allowed []string, defaultSort string, defaultOrder string) (Request, error) {
+	request := Request{Limit: limit, Sort: strings.ToLower(strings.TrimSpace(sortKey)), Order: strings.ToLower(strings.TrimSpace(order))}
+
+	if request.Limit == 0 {
+		request.Limit = DefaultLimit
+	}
+	if request.Limit < 0 || request.Limit > MaxLimit {
+		return request, fmt.Errorf("limit must be between 1 and %d", MaxLimit)
+	}
+
+	if strings.HasPrefix(request.Sort, "-") {
+		request.Sort = strings.TrimPrefix(request.Sort, "-")
+		if request.Order == "" {
+			request.Order = OrderDesc
+		}
+	}
+	if request.Sort == "" {
+		request.Sort = defaultSort
+	}
+	if !contains(allowed, request.Sort) {
+		sorted := append([]string(nil), allowed...)
+		sort.Strings(sorted)
+		return request, fmt.Errorf("sort must be one of %s", strings.Join(sorted, ", "))
+	}
+	if request.Order == "" {
+		request.Order = defaultOrder
+	}
+	if request.Order != OrderAsc && request.Order != OrderDesc {
+		return request, errors.New("order must be asc or desc")
+	}
+
+	if cursor != "" {
+		after, err := DecodeCursor(cursor)
+		if err != nil {
+			return request, err
+		}
+		if after.Sort != request.Sort || after.Order != request.Order {
+			return request, errors.New("cursor was issued for another sort, start again without cursor")
+		}
+		request.After = &after
+	}
+	return request, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Info is returned next to the items of a page
+type Info struct {
+	NextCursor *string `json:"next_cursor"` // null on the last page
+	TotalCount int64   `json:"total_count"` // rows matching the filters, across all pages
+	Limit      int     `json:"limit"`
+	Sort       string  `json:"sort"`
+	Order      string  `json:"order"`
+}
+
+// Info describes the page loaded for r
+func (r Request) Info(nextCursor string, totalCount int64) Info {
+	info := Info{TotalCount: totalCount, Limit: r.Limit, Sort: r.Sort, Order: r.Order}
+	if nextCursor != "" {
+		info.NextCursor = &nextCursor
+	}
+	return info
+}
@@ -0,0 +1,60 @@
+package pagination
+
+import "testing"
+
+var sorts = []string{"id", "date", "duration"}
+
+func TestParseDefaults(t *testing.T) {
+	request, err := Parse(0, "", "", "", sorts, "id", OrderDesc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if request.Limit != DefaultLimit || request.Sort != "id" || request.Order != OrderDesc || request.After != nil {
+		t.Errorf("got %+v, want default limit, id desc without cursor", request)
+	}
+}
+
+func TestParseDescendingPrefix(t *testing.T) {
+	request, err := Parse(10, "", "-Date", "", sorts, "id", OrderAsc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if request.Sort != "date" || request.Order != OrderDesc {
+		t.Errorf("got %s %s, want date desc", request.Sort, request.Order)
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	tests := []struct {
+		name   string
+		limit  int
+		cursor string
+		sort   string
+		order  string
+	}{
+		{"limit too large", MaxLimit + 1, "", "", ""},
+		{"negative limit", -1, "", "", ""},
+		{"unknown sort", 10, "", "password_hash", ""},
+		{"unknown order", 10, "", "date", "sideways"},
+		{"broken cursor", 10, "not-a-cursor", "", ""},
+		{"cursor of another sort", 10, Cursor{Sort: "date", Order: OrderDesc, Value: "2024-03-01", ID: 4}.Encode(), "duration", "desc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(tt.limit, tt.cursor, tt.sort, tt.order, sorts, "id", OrderDesc); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	cursor := Cursor{Sort: "date", Order: OrderAsc, Value: "2024-03-01", ID: 42}
+	request, err := Parse(5, cursor.Encode(), "date", "asc", sorts, "id", OrderDesc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if request.After == nil || *request.After != cursor {
+		t.Errorf("after = %+v, want %+v", request.After, cursor)
+	}
+}
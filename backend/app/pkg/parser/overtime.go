@@ -0,0 +1,492 @@
+// Package parser turns free-text bot messages into overtime payloads.
+//
+// Example: "lembur kemarin 18:00-21:30 istirahat 30m deploy server #infra"
+package parser
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/payloads"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/helpers"
+)
+
+// ParseError is returned for input that cannot be turned into an overtime record.
+// Message is written for the bot user.
+type ParseError struct {
+	Message string
+}
+
+func (e *ParseError) Error() string {
+	return e.Message
+}
+
+func parseErrorf(format string, args ...interface{}) *ParseError {
+	return &ParseError{Message: fmt.Sprintf(format, args...)}
+}
+
+var (
+	// 18:00-21:30, 18.00-21.30, 18-21
+	timeRangePattern = regexp.MustCompile(`^(\d{1,2}(?:[:.]\d{2})?)-(\d{1,2}(?:[:.]\d{2})?)$`)
+	// 18, 18:00, 18.00
+	clockPattern = regexp.MustCompile(`^\d{1,2}(?:[:.]\d{2})?$`)
+	// 18:00, 18.00 (minutes required, used when the range has no separator)
+	strictClockPattern = regexp.MustCompile(`^\d{1,2}[:.]\d{2}$`)
+	// 30m, 1j, 1.5h, 1,5jam, 1j30m
+	compactDurationPattern = regexp.MustCompile(`^(?:(\d+(?:[.,]\d+)?)(j|jam|h|hr|hour|hours))?(?:(\d+)(m|mnt|menit|min|minute|minutes))?$`)
+	numberPattern          = regexp.MustCompile(`^\d+(?:[.,]\d+)?$`)
+	// 12/03, 12/03/2024
+	dayMonthPattern = regexp.MustCompile(`^(\d{1,2})/(\d{1,2})(?:/(\d{4}))?$`)
+	// 2024-03-12
+	isoDatePattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+)
+
+var rangeSeparators = map[string]bool{"-": true, "s/d": true, "sd": true, "sampai": true, "hingga": true, "to": true}
+
+var breakKeywords = map[string]bool{"istirahat": true, "break": true, "rehat": true}
+
+var hourUnits = map[string]bool{"j": true, "jam": true, "h": true, "hr": true, "hour": true, "hours": true}
+
+var minuteUnits = map[string]bool{"m": true, "mnt": true, "menit": true, "min": true, "minute": true, "minutes": true}
+
+var weekdays = map[string]time.Weekday{
+	"minggu": time.Sunday,
+	"ahad":   time.Sunday,
+	"senin":  time.Monday,
+	"selasa": time.Tuesday,
+	"rabu":   time.Wednesday,
+	"kamis":  time.Thursday,
+	"jumat":  time.Friday,
+	"jum'at": time.Friday,
+	"sabtu":  time.Saturday,
+}
+
+type tokens struct {
+	raw      []string
+	lower    []string
+	consumed []bool
+}
+
+func (t *tokens) free(i int) bool {
+	return i >= 0 && i < len(t.raw) && !t.consumed[i]
+}
+
+func (t *tokens) consume(from, to int) {
+	for i := from; i < to; i++ {
+		t.consumed[i] = true
+	}
+}
+
+// ParseOvertime parses a free-text overtime message relative to now (its location is used for dates).
+// Duration is computed from the time range minus the break, shifts past midnight end on the next day.
+func ParseOvertime(text string, telegramID int64, now time.Time) (payloads.CreateNewRecordOvertime, error) {
+	t := newTokens(text)
+	if len(t.raw) > 0 && (t.lower[0] == "lembur" || t.lower[0] == "/lembur") {
+		t.consume(0, 1)
+	}
+
+	breakHours, err := parseBreak(t)
+	if err != nil {
+		return payloads.CreateNewRecordOvertime{}, err
+	}
+
+	date, err := parseDate(t, now)
+	if err != nil {
+		return payloads.CreateNewRecordOvertime{}, err
+	}
+
+	start, stop, err := parseTimeRange(t)
+	if err != nil {
+		return payloads.CreateNewRecordOvertime{}, err
+	}
+
+	category, err := parseCategory(t)
+	if err != nil {
+		return payloads.CreateNewRecordOvertime{}, err
+	}
+
+	duration, err := ComputeDuration(formatClock(start), formatClock(stop), breakHours)
+	if err != nil {
+		return payloads.CreateNewRecordOvertime{}, err
+	}
+
+	return payloads.CreateNewRecordOvertime{
+		TelegramID:    telegramID,
+		Date:          date.Format("2006-01-02"),
+		TimeStart:     formatClock(start),
+		TimeStop:      formatClock(stop),
+		BreakDuration: round2(breakHours),
+		Duration:      duration,
+		Description:   t.leftover(),
+		Category:      category,
+	}, nil
+}
+
+// parseBreak finds "istirahat <durasi>" or a standalone compact duration like "30m"
+func parseBreak(t *tokens) (float64, error) {
+	found := false
+	var hours float64
+	for i := range t.raw {
+		if !t.free(i) || !breakKeywords[t.lower[i]] {
+			continue
+		}
+		if found {
+			return 0, parseErrorf("Istirahat ditulis lebih dari satu kali")
+		}
+		value, next, ok := parseDuration(t, i+1)
+		if !ok {
+			return 0, parseErrorf("Durasi istirahat tidak dikenali, contoh: 30m, 1j, 1.5h atau 1 jam 30 menit")
+		}
+		t.consume(i, next)
+		hours, found = value, true
+	}
+
+	for i := range t.raw {
+		if !t.free(i) || numberPattern.MatchString(t.lower[i]) {
+			continue
+		}
+		if value, next, ok := parseDuration(t, i); ok && next == i+1 {
+			if found {
+				return 0, parseErrorf("Durasi istirahat ambigu: %q", t.raw[i])
+			}
+			t.consume(i, next)
+			hours, found = value, true
+		}
+	}
+	return hours, nil
+}
+
+// parseDuration reads a duration starting at token i and returns the index after it
+func parseDuration(t *tokens, i int) (float64, int, bool) {
+	var hours float64
+	parsed := false
+	for t.free(i) {
+		token := t.lower[i]
+		if matches := compactDurationPattern.FindStringSubmatch(token); matches != nil && token != "" {
+			if matches[1] != "" {
+				hours += parseNumber(matches[1])
+			}
+			if matches[3] != "" {
+				hours += parseNumber(matches[3]) / 60
+			}
+			parsed = true
+			i++
+			continue
+		}
+		if numberPattern.MatchString(token) && t.free(i+1) {
+			unit := t.lower[i+1]
+			if hourUnits[unit] {
+				hours += parseNumber(token)
+			} else if minuteUnits[unit] {
+				hours += parseNumber(token) / 60
+			} else {
+				break
+			}
+			parsed = true
+			i += 2
+			continue
+		}
+		break
+	}
+	return hours, i, parsed && hours >= 0
+}
+
+func parseNumber(value string) float64 {
+	number, _ := strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+	return number
+}
+
+// parseDate understands kemarin, hari ini, weekday names, DD/MM[/YYYY] and YYYY-MM-DD. Defaults to today.
+func parseDate(t *tokens, now time.Time) (time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	var dates []time.Time
+	var raws []string
+
+	for i := range t.raw {
+		if !t.free(i) {
+			continue
+		}
+		token := t.lower[i]
+		from := i
+		if (token == "tgl" || token == "tanggal") && t.free(i+1) {
+			i++
+			token = t.lower[i]
+		}
+
+		var date time.Time
+		next := i + 1
+		switch {
+		case token == "kemarin":
+			date = today.AddDate(0, 0, -1)
+		case token == "hariini" || (token == "hari" && t.free(i+1) && t.lower[i+1] == "ini"):
+			date = today
+			if token == "hari" {
+				next = i + 2
+			}
+		case isWeekday(token):
+			weekday := weekdays[token]
+			diff := (int(today.Weekday()) - int(weekday) + 7) % 7
+			date = today.AddDate(0, 0, -diff)
+			if t.free(i+1) && t.lower[i+1] == "lalu" {
+				if diff == 0 {
+					date = date.AddDate(0, 0, -7)
+				}
+				next = i + 2
+			}
+		case isoDatePattern.MatchString(token):
+			parsed, err := time.ParseInLocation("2006-01-02", token, now.Location())
+			if err != nil {
+				return time.Time{}, parseErrorf("Tanggal %q tidak valid", t.raw[i])
+			}
+			date = parsed
+		case dayMonthPattern.MatchString(token):
+			parsed, err := parseDayMonth(token, today)
+			if err != nil {
+				return time.Time{}, err
+			}
+			date = parsed
+		default:
+			continue
+		}
+
+		t.consume(from, next)
+		dates = append(dates, date)
+		raws = append(raws, strings.Join(t.raw[from:next], " "))
+	}
+
+	switch len(dates) {
+	case 0:
+		return today, nil
+	case 1:
+		if dates[0].After(today) {
+			return time.Time{}, parseErrorf("Tanggal %s belum terjadi", dates[0].Format("2006-01-02"))
+		}
+		return dates[0], nil
+	default:
+		return time.Time{}, parseErrorf("Tanggal ambigu, ditemukan lebih dari satu tanggal: %s", strings.Join(raws, ", "))
+	}
+}
+
+func isWeekday(token string) bool {
+	_, ok := weekdays[token]
+	return ok
+}
+
+// parseDayMonth parses DD/MM[/YYYY]. Without a year the date is assumed to be in the past year if it would be in the future.
+func parseDayMonth(token string, today time.Time) (time.Time, error) {
+	matches := dayMonthPattern.FindStringSubmatch(token)
+	day, _ := strconv.Atoi(matches[1])
+	month, _ := strconv.Atoi(matches[2])
+	year := today.Year()
+	explicitYear := matches[3] != ""
+	if explicitYear {
+		year, _ = strconv.Atoi(matches[3])
+	}
+	if month < 1 || month > 12 || day < 1 || day > 31 {
+		return time.Time{}, parseErrorf("Tanggal %q tidak valid, gunakan format DD/MM", token)
+	}
+	date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, today.Location())
+	if date.Day() != day {
+		return time.Time{}, parseErrorf("Tanggal %q tidak valid", token)
+	}
+	if !explicitYear && date.After(today) {
+		date = date.AddDate(-1, 0, 0)
+	}
+	return date, nil
+}
+
+// parseTimeRange returns start and stop in minutes since midnight
+func parseTimeRange(t *tokens) (int, int, error) {
+	type timeRange struct {
+		start, stop int
+		raw         string
+	}
+	var ranges []timeRange
+
+	for i := range t.raw {
+		if !t.free(i) {
+			continue
+		}
+		var startRaw, stopRaw string
+		next := i + 1
+		switch {
+		case timeRangePattern.MatchString(t.lower[i]):
+			matches := timeRangePattern.FindStringSubmatch(t.lower[i])
+			startRaw, stopRaw = matches[1], matches[2]
+		case clockPattern.MatchString(t.lower[i]) && t.free(i+1) && rangeSeparators[t.lower[i+1]] && t.free(i+2) && clockPattern.MatchString(t.lower[i+2]):
+			startRaw, stopRaw = t.lower[i], t.lower[i+2]
+			next = i + 3
+		case strictClockPattern.MatchString(t.lower[i]) && t.free(i+1) && strictClockPattern.MatchString(t.lower[i+1]):
+			startRaw, stopRaw = t.lower[i], t.lower[i+1]
+			next = i + 2
+		case strictClockPattern.MatchString(t.lower[i]):
+			return 0, 0, parseErrorf("Jam selesai tidak ditemukan setelah %q, contoh: 18:00-21:30", t.raw[i])
+		default:
+			continue
+		}
+
+		start, err := parseClock(startRaw)
+		if err != nil {
+			return 0, 0, err
+		}
+		stop, err := parseClock(stopRaw)
+		if err != nil {
+			return 0, 0, err
+		}
+		if start == stop {
+			return 0, 0, parseErrorf("Jam mulai dan jam selesai sama (%s)", formatClock(start))
+		}
+		t.consume(i, next)
+		ranges = append(ranges, timeRange{start: start, stop: stop, raw: strings.Join(t.raw[i:next], " ")})
+	}
+
+	switch len(ranges) {
+	case 0:
+		return 0, 0, parseErrorf("Jam lembur tidak ditemukan, contoh: lembur 18:00-21:30 istirahat 30m deskripsi #kategori")
+	case 1:
+		return ranges[0].start, ranges[0].stop, nil
+	default:
+		raws := make([]string, 0, len(ranges))
+		for _, r := range ranges {
+			raws = append(raws, r.raw)
+		}
+		return 0, 0, parseErrorf("Jam lembur ambigu, ditemukan lebih dari satu rentang: %s", strings.Join(raws, ", "))
+	}
+}
+
+func parseClock(value string) (int, error) {
+	value = strings.Replace(value, ".", ":", 1)
+	parts := strings.SplitN(value, ":", 2)
+	hour, _ := strconv.Atoi(parts[0])
+	minute := 0
+	if len(parts) == 2 {
+		minute, _ = strconv.Atoi(parts[1])
+	}
+	if hour > 23 || minute > 59 {
+		return 0, parseErrorf("Jam %q tidak valid", value)
+	}
+	return hour*60 + minute, nil
+}
+
+func parseCategory(t *tokens) (string, error) {
+	var categories []string
+	for i := range t.raw {
+		if t.free(i) && strings.HasPrefix(t.raw[i], "#") && len(t.raw[i]) > 1 {
+			categories = append(categories, strings.TrimPrefix(t.raw[i], "#"))
+			t.consume(i, i+1)
+		}
+	}
+	if len(categories) > 1 {
+		return "", parseErrorf("Kategori ambigu, gunakan satu #kategori saja (ditemukan: #%s)", strings.Join(categories, ", #"))
+	}
+	if len(categories) == 1 {
+		return categories[0], nil
+	}
+	return "", nil
+}
+
+func formatClock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+func formatHours(hours float64) string {
+	return strconv.FormatFloat(round2(hours), 'f', -1, 64) + " jam"
+}
+
+func round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+func newTokens(text string) *tokens {
+	fields := strings.Fields(text)
+	t := &tokens{raw: fields, lower: make([]string, len(fields)), consumed: make([]bool, len(fields))}
+	for i, field := range fields {
+		t.lower[i] = strings.ToLower(field)
+	}
+	return t
+}
+
+// leftover returns the tokens that were not understood
+func (t *tokens) leftover() string {
+	var rest []string
+	for i, field := range t.raw {
+		if !t.consumed[i] {
+			rest = append(rest, field)
+		}
+	}
+	return strings.Join(rest, " ")
+}
+
+// ParseDate parses a single date answer such as "kemarin", "senin" or "12/03", used by the bot wizard
+func ParseDate(text string, now time.Time) (time.Time, error) {
+	t := newTokens(text)
+	if len(t.raw) == 0 {
+		return time.Time{}, parseErrorf("Tanggal kosong")
+	}
+	date, err := parseDate(t, now)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if rest := t.leftover(); rest != "" {
+		return time.Time{}, parseErrorf("Tanggal %q tidak dikenali, contoh: kemarin, senin, 12/03 atau 2024-03-12", rest)
+	}
+	return date, nil
+}
+
+// ParseClock parses a single time answer such as "18:00", "18.30" or "18" into HH:MM
+func ParseClock(text string) (string, error) {
+	value := strings.TrimSpace(text)
+	if !clockPattern.MatchString(value) {
+		return "", parseErrorf("Jam %q tidak dikenali, contoh: 18:00", value)
+	}
+	minutes, err := parseClock(value)
+	if err != nil {
+		return "", err
+	}
+	return formatClock(minutes), nil
+}
+
+// ParseBreak parses a single break answer such as "30m", "1j", "1.5h", "1 jam 30 menit" or "0" into hours
+func ParseBreak(text string) (float64, error) {
+	value := strings.TrimSpace(strings.ToLower(text))
+	if value == "0" || value == "-" || value == "tidak" || value == "tanpa" {
+		return 0, nil
+	}
+	t := newTokens(value)
+	hours, next, ok := parseDuration(t, 0)
+	if !ok || next != len(t.raw) {
+		return 0, parseErrorf("Durasi istirahat tidak dikenali, contoh: 30m, 1j, 1.5h atau 1 jam 30 menit")
+	}
+	return round2(hours), nil
+}
+
+// ComputeDuration returns the overtime duration in hours for HH:MM start and stop, shifts past midnight end on the next day
+func ComputeDuration(timeStart string, timeStop string, breakHours float64) (float64, error) {
+	start, err := parseClock(timeStart)
+	if err != nil {
+		return 0, err
+	}
+	stop, err := parseClock(timeStop)
+	if err != nil {
+		return 0, err
+	}
+	// the arithmetic is shared with the API so the bot and the server never disagree on a duration
+	startTime, stopTime := clockTime(start), clockTime(stop)
+	duration, err := helpers.ComputeOvertimeDuration(startTime, stopTime, breakHours)
+	if err != nil {
+		if breakHours < 0 {
+			return 0, parseErrorf("Durasi istirahat tidak boleh negatif")
+		}
+		return 0, parseErrorf("Istirahat (%s) tidak boleh sama atau lebih lama dari jam lembur (%s)", formatHours(breakHours), formatHours(helpers.OvertimeSpanHours(startTime, stopTime)))
+	}
+	return duration, nil
+}
+
+// clockTime turns minutes since midnight into the time of day helpers.ComputeOvertimeDuration reads
+func clockTime(minutes int) time.Time {
+	return time.Date(0, 1, 1, minutes/60, minutes%60, 0, 0, time.UTC)
+}
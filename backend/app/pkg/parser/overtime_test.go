@@ -0,0 +1,201 @@
+package parser
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/payloads"
+)
+
+// now is Wednesday 13 March 2024, 10:00 WIB
+var now = time.Date(2024, time.March, 13, 10, 0, 0, 0, time.FixedZone("WIB", 7*60*60))
+
+func TestParseOvertime(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want payloads.CreateNewRecordOvertime
+	}{
+		{
+			name: "full message",
+			text: "lembur 18:00-21:30 istirahat 30m deploy server #infra",
+			want: payloads.CreateNewRecordOvertime{Date: "2024-03-13", TimeStart: "18:00", TimeStop: "21:30", BreakDuration: 0.5, Duration: 3, Description: "deploy server", Category: "infra"},
+		},
+		{
+			name: "without keyword and break",
+			text: "18:00-20:00 rapat",
+			want: payloads.CreateNewRecordOvertime{Date: "2024-03-13", TimeStart: "18:00", TimeStop: "20:00", Duration: 2, Description: "rapat"},
+		},
+		{
+			name: "slash command keyword",
+			text: "/lembur 18:00-20:00",
+			want: payloads.CreateNewRecordOvertime{Date: "2024-03-13", TimeStart: "18:00", TimeStop: "20:00", Duration: 2},
+		},
+		{
+			name: "dotted clock and bare hours",
+			text: "lembur 18.15-21 cek backup",
+			want: payloads.CreateNewRecordOvertime{Date: "2024-03-13", TimeStart: "18:15", TimeStop: "21:00", Duration: 2.75, Description: "cek backup"},
+		},
+		{
+			name: "range with separator words",
+			text: "lembur 18:00 sampai 20:00",
+			want: payloads.CreateNewRecordOvertime{Date: "2024-03-13", TimeStart: "18:00", TimeStop: "20:00", Duration: 2},
+		},
+		{
+			name: "range with spaced dash",
+			text: "lembur 18:00 - 20:00",
+			want: payloads.CreateNewRecordOvertime{Date: "2024-03-13", TimeStart: "18:00", TimeStop: "20:00", Duration: 2},
+		},
+		{
+			name: "two clocks without separator",
+			text: "lembur 18:00 20:00",
+			want: payloads.CreateNewRecordOvertime{Date: "2024-03-13", TimeStart: "18:00", TimeStop: "20:00", Duration: 2},
+		},
+		{
+			name: "cross midnight",
+			text: "lembur 22:00-02:00 istirahat 1j maintenance",
+			want: payloads.CreateNewRecordOvertime{Date: "2024-03-13", TimeStart: "22:00", TimeStop: "02:00", BreakDuration: 1, Duration: 3, Description: "maintenance"},
+		},
+		{
+			name: "break in decimal hours",
+			text: "lembur 17:00-21:00 istirahat 1.5h",
+			want: payloads.CreateNewRecordOvertime{Date: "2024-03-13", TimeStart: "17:00", TimeStop: "21:00", BreakDuration: 1.5, Duration: 2.5},
+		},
+		{
+			name: "break with decimal comma",
+			text: "lembur 17:00-21:00 istirahat 1,5 jam",
+			want: payloads.CreateNewRecordOvertime{Date: "2024-03-13", TimeStart: "17:00", TimeStop: "21:00", BreakDuration: 1.5, Duration: 2.5},
+		},
+		{
+			name: "break in words",
+			text: "lembur 17:00-21:00 istirahat 1 jam 30 menit",
+			want: payloads.CreateNewRecordOvertime{Date: "2024-03-13", TimeStart: "17:00", TimeStop: "21:00", BreakDuration: 1.5, Duration: 2.5},
+		},
+		{
+			name: "compact compound break",
+			text: "lembur 17:00-21:00 break 1j30m",
+			want: payloads.CreateNewRecordOvertime{Date: "2024-03-13", TimeStart: "17:00", TimeStop: "21:00", BreakDuration: 1.5, Duration: 2.5},
+		},
+		{
+			name: "standalone compact break",
+			text: "lembur 17:00-21:00 15m laporan",
+			want: payloads.CreateNewRecordOvertime{Date: "2024-03-13", TimeStart: "17:00", TimeStop: "21:00", BreakDuration: 0.25, Duration: 3.75, Description: "laporan"},
+		},
+		{
+			name: "yesterday",
+			text: "lembur kemarin 18:00-20:00",
+			want: payloads.CreateNewRecordOvertime{Date: "2024-03-12", TimeStart: "18:00", TimeStop: "20:00", Duration: 2},
+		},
+		{
+			name: "today in words",
+			text: "lembur hari ini 18:00-20:00",
+			want: payloads.CreateNewRecordOvertime{Date: "2024-03-13", TimeStart: "18:00", TimeStop: "20:00", Duration: 2},
+		},
+		{
+			name: "weekday is the most recent one",
+			text: "lembur senin 18:00-20:00",
+			want: payloads.CreateNewRecordOvertime{Date: "2024-03-11", TimeStart: "18:00", TimeStop: "20:00", Duration: 2},
+		},
+		{
+			name: "weekday of today",
+			text: "lembur Rabu 18:00-20:00",
+			want: payloads.CreateNewRecordOvertime{Date: "2024-03-13", TimeStart: "18:00", TimeStop: "20:00", Duration: 2},
+		},
+		{
+			name: "weekday last week",
+			text: "lembur rabu lalu 18:00-20:00",
+			want: payloads.CreateNewRecordOvertime{Date: "2024-03-06", TimeStart: "18:00", TimeStop: "20:00", Duration: 2},
+		},
+		{
+			name: "later weekday goes back a week",
+			text: "lembur jumat 18:00-20:00",
+			want: payloads.CreateNewRecordOvertime{Date: "2024-03-08", TimeStart: "18:00", TimeStop: "20:00", Duration: 2},
+		},
+		{
+			name: "day and month",
+			text: "lembur 12/03 18:00-20:00",
+			want: payloads.CreateNewRecordOvertime{Date: "2024-03-12", TimeStart: "18:00", TimeStop: "20:00", Duration: 2},
+		},
+		{
+			name: "day and month in the future is last year",
+			text: "lembur tgl 24/12 18:00-20:00",
+			want: payloads.CreateNewRecordOvertime{Date: "2023-12-24", TimeStart: "18:00", TimeStop: "20:00", Duration: 2},
+		},
+		{
+			name: "full date",
+			text: "lembur 01/02/2024 18:00-20:00",
+			want: payloads.CreateNewRecordOvertime{Date: "2024-02-01", TimeStart: "18:00", TimeStop: "20:00", Duration: 2},
+		},
+		{
+			name: "iso date",
+			text: "lembur 2024-03-01 18:00-20:00",
+			want: payloads.CreateNewRecordOvertime{Date: "2024-03-01", TimeStart: "18:00", TimeStop: "20:00", Duration: 2},
+		},
+		{
+			name: "order does not matter",
+			text: "#Support bantu user 18:00-19:30 kemarin istirahat 15 menit",
+			want: payloads.CreateNewRecordOvertime{Date: "2024-03-12", TimeStart: "18:00", TimeStop: "19:30", BreakDuration: 0.25, Duration: 1.25, Description: "bantu user", Category: "Support"},
+		},
+		{
+			name: "description keeps numbers without unit",
+			text: "lembur 18:00-20:00 deploy 2 server",
+			want: payloads.CreateNewRecordOvertime{Date: "2024-03-13", TimeStart: "18:00", TimeStop: "20:00", Duration: 2, Description: "deploy 2 server"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.want.TelegramID = 42
+			got, err := ParseOvertime(tt.text, 42, now)
+			if err != nil {
+				t.Fatalf("ParseOvertime(%q) returned error: %v", tt.text, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseOvertime(%q)\n got  %+v\n want %+v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseOvertimeErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		message string
+	}{
+		{name: "empty", text: "lembur", message: "Jam lembur tidak ditemukan"},
+		{name: "no time range", text: "lembur deploy server", message: "Jam lembur tidak ditemukan"},
+		{name: "missing stop time", text: "lembur 18:00 deploy", message: "Jam selesai tidak ditemukan"},
+		{name: "two time ranges", text: "lembur 08:00-10:00 18:00-20:00", message: "lebih dari satu rentang"},
+		{name: "invalid hour", text: "lembur 18:00-25:00", message: "tidak valid"},
+		{name: "invalid minute", text: "lembur 18:75-20:00", message: "tidak valid"},
+		{name: "same start and stop", text: "lembur 18:00-18:00", message: "sama"},
+		{name: "two dates", text: "lembur kemarin senin 18:00-20:00", message: "lebih dari satu tanggal"},
+		{name: "future date", text: "lembur 2024-03-20 18:00-20:00", message: "belum terjadi"},
+		{name: "invalid day", text: "lembur 31/02 18:00-20:00", message: "tidak valid"},
+		{name: "invalid month", text: "lembur 12/13 18:00-20:00", message: "tidak valid"},
+		{name: "break without duration", text: "lembur 18:00-20:00 istirahat sebentar", message: "Durasi istirahat tidak dikenali"},
+		{name: "two breaks", text: "lembur 18:00-20:00 istirahat 10m istirahat 5m", message: "lebih dari satu kali"},
+		{name: "break and standalone duration", text: "lembur 18:00-20:00 istirahat 10m makan 5m", message: "ambigu"},
+		{name: "break longer than overtime", text: "lembur 18:00-19:00 istirahat 1j", message: "tidak boleh"},
+		{name: "two categories", text: "lembur 18:00-20:00 #infra #support", message: "Kategori ambigu"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseOvertime(tt.text, 42, now)
+			if err == nil {
+				t.Fatalf("ParseOvertime(%q) expected error", tt.text)
+			}
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("ParseOvertime(%q) error %T is not a *ParseError", tt.text, err)
+			}
+			if !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("ParseOvertime(%q) error %q does not contain %q", tt.text, err.Error(), tt.message)
+			}
+		})
+	}
+}
@@ -0,0 +1,296 @@
+// Package payroll turns overtime hours into pay.
+//
+// The default rates follow Kepmenakertrans No. KEP.102/MEN/VI/2004:
+//   - hourly wage is 1/173 of the monthly salary (Pasal 8)
+//   - workday: 1.5x for the first hour, 2x for every next hour (Pasal 11a)
+//   - rest day or public holiday, 6-day week: 2x for the first 7 hours, 3x for the 8th, 4x for the 9th and 10th;
+//     on the shortest workday 2x for the first 5 hours, 3x for the 6th, 4x for the 7th and 8th (Pasal 11b)
+//   - rest day or public holiday, 5-day week: 2x for the first 8 hours, 3x for the 9th, 4x for the 10th and 11th (Pasal 11c)
+package payroll
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// DefaultHourlyDivisor turns a monthly salary into an hourly wage (upah sejam = 1/173 x upah sebulan)
+const DefaultHourlyDivisor = 173
+
+type DayType string
+
+const (
+	DayWorkday                DayType = "workday"
+	DayRestDay                DayType = "rest_day"
+	DayPublicHoliday          DayType = "public_holiday"
+	DayShortestWorkdayHoliday DayType = "shortest_workday_holiday"
+)
+
+// Tier pays Hours hours at Multiplier times the hourly wage. Hours 0 covers all remaining hours and is only valid last.
+type Tier struct {
+	Hours      float64 `json:"hours"`
+	Multiplier float64 `json:"multiplier"`
+}
+
+// Tiers holds the multiplier ladder of every day type
+type Tiers struct {
+	Workday                []Tier `json:"workday"`
+	RestDay                []Tier `json:"rest_day"`
+	PublicHoliday          []Tier `json:"public_holiday"`
+	ShortestWorkdayHoliday []Tier `json:"shortest_workday_holiday,omitempty"`
+}
+
+// Profile is the pay configuration of one employee
+type Profile struct {
+	MonthlySalary   float64      `json:"monthly_salary"`
+	WorkWeekDays    int          `json:"work_week_days"`
+	HourlyDivisor   float64      `json:"hourly_divisor"`
+	ShortestWorkday time.Weekday `json:"-"`
+	Tiers           Tiers        `json:"tiers"`
+}
+
+// DefaultTiers returns the Kepmenakertrans 102/2004 ladders for a 5-day or 6-day work week
+func DefaultTiers(workWeekDays int) Tiers {
+	workday := []Tier{{Hours: 1, Multiplier: 1.5}, {Hours: 0, Multiplier: 2}}
+	if workWeekDays == 6 {
+		restDay := []Tier{{Hours: 7, Multiplier: 2}, {Hours: 1, Multiplier: 3}, {Hours: 0, Multiplier: 4}}
+		return Tiers{
+			Workday:                workday,
+			RestDay:                restDay,
+			PublicHoliday:          restDay,
+			ShortestWorkdayHoliday: []Tier{{Hours: 5, Multiplier: 2}, {Hours: 1, Multiplier: 3}, {Hours: 0, Multiplier: 4}},
+		}
+	}
+	restDay := []Tier{{Hours: 8, Multiplier: 2}, {Hours: 1, Multiplier: 3}, {Hours: 0, Multiplier: 4}}
+	return Tiers{
+		Workday:       workday,
+		RestDay:       restDay,
+		PublicHoliday: restDay,
+	}
+}
+
+// DefaultProfile returns the statutory profile for a monthly salary and work week (5 or 6 days)
+func DefaultProfile(monthlySalary float64, workWeekDays int) Profile {
+	return Profile{
+		MonthlySalary:   monthlySalary,
+		WorkWeekDays:    workWeekDays,
+		HourlyDivisor:   DefaultHourlyDivisor,
+		ShortestWorkday: time.Saturday,
+		Tiers:           DefaultTiers(workWeekDays),
+	}
+}
+
+// Validate checks the profile before it is stored or used
+func (p Profile) Validate() error {
+	if p.MonthlySalary < 0 {
+		return fmt.Errorf("monthly salary must not be negative")
+	}
+	if p.WorkWeekDays != 5 && p.WorkWeekDays != 6 {
+		return fmt.Errorf("work week must be 5 or 6 days")
+	}
+	if p.HourlyDivisor <= 0 {
+		return fmt.Errorf("hourly divisor must be greater than 0")
+	}
+	ladders := map[string][]Tier{
+		"workday":        p.Tiers.Workday,
+		"rest_day":       p.Tiers.RestDay,
+		"public_holiday": p.Tiers.PublicHoliday,
+	}
+	if p.WorkWeekDays == 6 {
+		ladders["shortest_workday_holiday"] = p.Tiers.ShortestWorkdayHoliday
+	}
+	for name, tiers := range ladders {
+		if len(tiers) == 0 {
+			return fmt.Errorf("tiers %s must not be empty", name)
+		}
+		for i, tier := range tiers {
+			if tier.Multiplier <= 0 {
+				return fmt.Errorf("tiers %s[%d]: multiplier must be greater than 0", name, i)
+			}
+			if tier.Hours < 0 || (tier.Hours == 0 && i != len(tiers)-1) {
+				return fmt.Errorf("tiers %s[%d]: hours must be greater than 0, only the last tier may be open ended", name, i)
+			}
+		}
+	}
+	return nil
+}
+
+// HourlyWage is the monthly salary divided by HourlyDivisor
+func (p Profile) HourlyWage() float64 {
+	return p.MonthlySalary / p.HourlyDivisor
+}
+
+// DayTypeOf classifies date. Rest days are Saturday and Sunday in a 5-day week and Sunday in a 6-day week.
+func (p Profile) DayTypeOf(date time.Time, isHoliday bool) DayType {
+	weekday := date.Weekday()
+	switch {
+	case isHoliday && p.WorkWeekDays == 6 && weekday == p.ShortestWorkday:
+		return DayShortestWorkdayHoliday
+	case isHoliday:
+		return DayPublicHoliday
+	case weekday == time.Sunday, p.WorkWeekDays == 5 && weekday == time.Saturday:
+		return DayRestDay
+	}
+	return DayWorkday
+}
+
+func (p Profile) tiersOf(dayType DayType) []Tier {
+	switch dayType {
+	case DayRestDay:
+		return p.Tiers.RestDay
+	case DayPublicHoliday:
+		return p.Tiers.PublicHoliday
+	case DayShortestWorkdayHoliday:
+		return p.Tiers.ShortestWorkdayHoliday
+	}
+	return p.Tiers.Workday
+}
+
+// Entry is the overtime of one record, hours after break.
+// Multiplier is a flat rate (e.g. from the record's category) that replaces the ladder, 0 = use the ladder.
+type Entry struct {
+	RecordID   uint
+	Date       time.Time
+	Hours      float64
+	Multiplier float64
+}
+
+type Segment struct {
+	Hours      float64 `json:"hours"`
+	Multiplier float64 `json:"multiplier"`
+	Amount     float64 `json:"amount"`
+}
+
+type Day struct {
+	Date          string    `json:"date"`
+	Weekday       string    `json:"weekday"`
+	DayType       DayType   `json:"day_type"`
+	IsHoliday     bool      `json:"is_holiday"`
+	Hours         float64   `json:"hours"`
+	WeightedHours float64   `json:"weighted_hours"`
+	Pay           float64   `json:"pay"`
+	Segments      []Segment `json:"segments"`
+	RecordIDs     []uint    `json:"record_ids"`
+}
+
+type Result struct {
+	HourlyWage         float64 `json:"hourly_wage"`
+	Days               []Day   `json:"days"`
+	TotalHours         float64 `json:"total_hours"`
+	TotalWeightedHours float64 `json:"total_weighted_hours"`
+	TotalPay           float64 `json:"total_pay"`
+}
+
+// CalculateDay spreads hours of one day over the ladder of its day type
+func (p Profile) CalculateDay(date time.Time, hours float64, isHoliday bool) Day {
+	dayType := p.DayTypeOf(date, isHoliday)
+	day := Day{
+		Date:      date.Format("2006-01-02"),
+		Weekday:   date.Weekday().String(),
+		DayType:   dayType,
+		IsHoliday: isHoliday,
+		Hours:     round2(hours),
+		Segments:  []Segment{},
+		RecordIDs: []uint{},
+	}
+
+	hourlyWage := p.HourlyWage()
+	remaining := hours
+	tiers := p.tiersOf(dayType)
+	for i, tier := range tiers {
+		if remaining <= 0 {
+			break
+		}
+		segmentHours := remaining
+		// hours beyond the ladder are paid at the last multiplier
+		if tier.Hours > 0 && tier.Hours < remaining && i != len(tiers)-1 {
+			segmentHours = tier.Hours
+		}
+		remaining -= segmentHours
+		amount := round2(segmentHours * tier.Multiplier * hourlyWage)
+		day.Segments = append(day.Segments, Segment{Hours: round2(segmentHours), Multiplier: tier.Multiplier, Amount: amount})
+		day.WeightedHours += segmentHours * tier.Multiplier
+		day.Pay += amount
+	}
+	day.WeightedHours = round2(day.WeightedHours)
+	day.Pay = round2(day.Pay)
+	return day
+}
+
+// Calculate groups entries per date (the first-hour rule applies per day, not per record) and sums the pay.
+// Entries with a flat multiplier do not use up the ladder, they are added as their own segment per multiplier.
+// isHoliday may be nil when there are no public holidays.
+func (p Profile) Calculate(entries []Entry, isHoliday func(date time.Time) bool) Result {
+	type dayTotal struct {
+		date      time.Time
+		hours     float64
+		flatHours map[float64]float64
+		recordIDs []uint
+	}
+	totals := map[string]*dayTotal{}
+	for _, entry := range entries {
+		key := entry.Date.Format("2006-01-02")
+		total, ok := totals[key]
+		if !ok {
+			total = &dayTotal{date: entry.Date, flatHours: map[float64]float64{}}
+			totals[key] = total
+		}
+		if entry.Multiplier > 0 {
+			total.flatHours[entry.Multiplier] += entry.Hours
+		} else {
+			total.hours += entry.Hours
+		}
+		total.recordIDs = append(total.recordIDs, entry.RecordID)
+	}
+
+	keys := make([]string, 0, len(totals))
+	for key := range totals {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := Result{HourlyWage: round2(p.HourlyWage()), Days: []Day{}}
+	for _, key := range keys {
+		total := totals[key]
+		holiday := isHoliday != nil && isHoliday(total.date)
+		day := p.CalculateDay(total.date, total.hours, holiday)
+		p.addFlatSegments(&day, total.flatHours)
+		sort.Slice(total.recordIDs, func(i, j int) bool { return total.recordIDs[i] < total.recordIDs[j] })
+		day.RecordIDs = total.recordIDs
+		result.Days = append(result.Days, day)
+		result.TotalHours += day.Hours
+		result.TotalWeightedHours += day.WeightedHours
+		result.TotalPay += day.Pay
+	}
+	result.TotalHours = round2(result.TotalHours)
+	result.TotalWeightedHours = round2(result.TotalWeightedHours)
+	result.TotalPay = round2(result.TotalPay)
+	return result
+}
+
+// addFlatSegments appends one segment per flat multiplier, lowest multiplier first
+func (p Profile) addFlatSegments(day *Day, flatHours map[float64]float64) {
+	if len(flatHours) == 0 {
+		return
+	}
+	multipliers := make([]float64, 0, len(flatHours))
+	for multiplier := range flatHours {
+		multipliers = append(multipliers, multiplier)
+	}
+	sort.Float64s(multipliers)
+
+	hourlyWage := p.HourlyWage()
+	for _, multiplier := range multipliers {
+		hours := flatHours[multiplier]
+		amount := round2(hours * multiplier * hourlyWage)
+		day.Segments = append(day.Segments, Segment{Hours: round2(hours), Multiplier: multiplier, Amount: amount})
+		day.Hours = round2(day.Hours + hours)
+		day.WeightedHours = round2(day.WeightedHours + hours*multiplier)
+		day.Pay = round2(day.Pay + amount)
+	}
+}
+
+func round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
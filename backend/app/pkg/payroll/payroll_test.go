@@ -0,0 +1,157 @@
+package payroll
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// go test ./app/pkg/payroll -update rewrites testdata/*.golden
+var update = flag.Bool("update", false, "update golden files")
+
+// goldenInput is one testdata/*.json case
+type goldenInput struct {
+	MonthlySalary float64  `json:"monthly_salary"`
+	WorkWeekDays  int      `json:"work_week_days"`
+	HourlyDivisor float64  `json:"hourly_divisor"`
+	Tiers         *Tiers   `json:"tiers"`
+	Holidays      []string `json:"holidays"`
+	Entries       []struct {
+		RecordID   uint    `json:"record_id"`
+		Date       string  `json:"date"`
+		Hours      float64 `json:"hours"`
+		Multiplier float64 `json:"multiplier"`
+	} `json:"entries"`
+}
+
+func TestCalculateGolden(t *testing.T) {
+	inputs, err := filepath.Glob(filepath.Join("testdata", "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inputs) == 0 {
+		t.Fatal("no golden inputs in testdata")
+	}
+
+	for _, inputPath := range inputs {
+		name := strings.TrimSuffix(filepath.Base(inputPath), ".json")
+		t.Run(name, func(t *testing.T) {
+			raw, err := os.ReadFile(inputPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var input goldenInput
+			if err := json.Unmarshal(raw, &input); err != nil {
+				t.Fatal(err)
+			}
+
+			profile := DefaultProfile(input.MonthlySalary, input.WorkWeekDays)
+			if input.HourlyDivisor > 0 {
+				profile.HourlyDivisor = input.HourlyDivisor
+			}
+			if input.Tiers != nil {
+				profile.Tiers = *input.Tiers
+			}
+			if err := profile.Validate(); err != nil {
+				t.Fatalf("invalid profile: %v", err)
+			}
+
+			holidays := map[string]bool{}
+			for _, holiday := range input.Holidays {
+				holidays[holiday] = true
+			}
+			entries := make([]Entry, 0, len(input.Entries))
+			for _, entry := range input.Entries {
+				date, err := time.Parse("2006-01-02", entry.Date)
+				if err != nil {
+					t.Fatal(err)
+				}
+				entries = append(entries, Entry{RecordID: entry.RecordID, Date: date, Hours: entry.Hours, Multiplier: entry.Multiplier})
+			}
+
+			result := profile.Calculate(entries, func(date time.Time) bool {
+				return holidays[date.Format("2006-01-02")]
+			})
+			got, err := json.MarshalIndent(result, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, '\n')
+
+			goldenPath := filepath.Join("testdata", name+".golden")
+			if *update {
+				if err := os.WriteFile(goldenPath, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(goldenPath)
+			if err != nil {
+				t.Fatalf("%v (run with -update to create it)", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("result differs from %s\ngot:\n%s\nwant:\n%s", goldenPath, got, want)
+			}
+		})
+	}
+}
+
+func TestDayTypeOf(t *testing.T) {
+	saturday := time.Date(2024, time.March, 16, 0, 0, 0, 0, time.UTC)
+	sunday := saturday.AddDate(0, 0, 1)
+	monday := saturday.AddDate(0, 0, 2)
+
+	tests := []struct {
+		name         string
+		workWeekDays int
+		date         time.Time
+		holiday      bool
+		want         DayType
+	}{
+		{"5-day monday", 5, monday, false, DayWorkday},
+		{"5-day saturday", 5, saturday, false, DayRestDay},
+		{"5-day sunday", 5, sunday, false, DayRestDay},
+		{"5-day holiday on saturday", 5, saturday, true, DayPublicHoliday},
+		{"6-day saturday", 6, saturday, false, DayWorkday},
+		{"6-day sunday", 6, sunday, false, DayRestDay},
+		{"6-day holiday on monday", 6, monday, true, DayPublicHoliday},
+		{"6-day holiday on shortest workday", 6, saturday, true, DayShortestWorkdayHoliday},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DefaultProfile(5000000, tt.workWeekDays).DayTypeOf(tt.date, tt.holiday)
+			if got != tt.want {
+				t.Errorf("DayTypeOf() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(p *Profile)
+		wantErr bool
+	}{
+		{"default", func(p *Profile) {}, false},
+		{"negative salary", func(p *Profile) { p.MonthlySalary = -1 }, true},
+		{"4-day week", func(p *Profile) { p.WorkWeekDays = 4 }, true},
+		{"zero divisor", func(p *Profile) { p.HourlyDivisor = 0 }, true},
+		{"empty workday tiers", func(p *Profile) { p.Tiers.Workday = nil }, true},
+		{"open tier not last", func(p *Profile) { p.Tiers.RestDay = []Tier{{Hours: 0, Multiplier: 2}, {Hours: 1, Multiplier: 3}} }, true},
+		{"zero multiplier", func(p *Profile) { p.Tiers.PublicHoliday = []Tier{{Hours: 0, Multiplier: 0}} }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := DefaultProfile(5000000, 5)
+			tt.mutate(&profile)
+			if err := profile.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
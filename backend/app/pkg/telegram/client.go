@@ -0,0 +1,444 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/helpers"
+)
+
+const DefaultBaseURL = "https://api.telegram.org"
+
+const (
+	DefaultMaxRetries   = 3
+	DefaultRetryBackoff = 500 * time.Millisecond
+)
+
+// Client is the outbound Telegram Bot API client. BaseURL can point to a local fake server for testing.
+// Messages are spaced by Limiter, 429 responses are retried after retry_after and 5xx/network errors with backoff.
+// Every call is reported through helpers.LogTelegramBotAction and helpers.LogTelegramPerformance.
+type Client struct {
+	BaseURL      string
+	Token        string
+	HTTPClient   *http.Client
+	Limiter      *RateLimiter
+	MaxRetries   int
+	RetryBackoff time.Duration
+}
+
+// NewClient builds a client from TELEGRAM_BOT_TOKEN and TELEGRAM_API_BASE_URL
+func NewClient() *Client {
+	return &Client{
+		BaseURL:      strings.TrimRight(helpers.GetEnv("TELEGRAM_API_BASE_URL", DefaultBaseURL), "/"),
+		Token:        helpers.GetEnv("TELEGRAM_BOT_TOKEN", ""),
+		HTTPClient:   &http.Client{Timeout: 30 * time.Second},
+		Limiter:      NewRateLimiter(),
+		MaxRetries:   DefaultMaxRetries,
+		RetryBackoff: DefaultRetryBackoff,
+	}
+}
+
+// BotID returns the numeric bot ID embedded in the token ("<bot_id>:<secret>")
+func (c *Client) BotID() int64 {
+	botID, _ := strconv.ParseInt(strings.SplitN(c.Token, ":", 2)[0], 10, 64)
+	return botID
+}
+
+// APIResponse is the envelope of every Bot API response
+type APIResponse struct {
+	Ok          bool                `json:"ok"`
+	Result      json.RawMessage     `json:"result"`
+	ErrorCode   int                 `json:"error_code"`
+	Description string              `json:"description"`
+	Parameters  *ResponseParameters `json:"parameters,omitempty"`
+}
+
+type ResponseParameters struct {
+	RetryAfter      int   `json:"retry_after"`
+	MigrateToChatID int64 `json:"migrate_to_chat_id"`
+}
+
+// APIError is returned when Telegram answers with ok=false
+type APIError struct {
+	Method      string
+	Code        int
+	Description string
+	RetryAfter  int
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("telegram %s: %d %s", e.Method, e.Code, e.Description)
+}
+
+// chatScoped is implemented by params that target a chat, those calls are rate limited per chat
+type chatScoped interface {
+	targetChatID() int64
+}
+
+func chatIDOf(params interface{}) int64 {
+	if scoped, ok := params.(chatScoped); ok {
+		return scoped.targetChatID()
+	}
+	return 0
+}
+
+func (c *Client) methodURL(method string) string {
+	return fmt.Sprintf("%s/bot%s/%s", c.BaseURL, c.Token, method)
+}
+
+// Call sends a Bot API method with a JSON body and decodes the result into result (may be nil)
+func (c *Client) Call(ctx context.Context, method string, params interface{}, result interface{}) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	return c.execute(ctx, method, chatIDOf(params), func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.methodURL(method), bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	}, result)
+}
+
+// execute runs a request with rate limiting and retries and reports the outcome
+func (c *Client) execute(ctx context.Context, method string, chatID int64, newRequest func() (*http.Request, error), result interface{}) error {
+	started := time.Now()
+	attempts := 0
+	var err error
+	for {
+		attempts++
+		if chatID != 0 && c.Limiter != nil {
+			if err = c.Limiter.Wait(ctx, chatID); err != nil {
+				break
+			}
+		}
+		var req *http.Request
+		if req, err = newRequest(); err != nil {
+			break
+		}
+		err = c.do(req, method, result)
+
+		wait, retry := c.retryDelay(ctx, err, attempts)
+		if !retry {
+			break
+		}
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 && c.Limiter != nil {
+			c.Limiter.Delay(chatID, wait)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			err = ctx.Err()
+		case <-timer.C:
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	c.report(method, chatID, attempts, time.Since(started), err)
+	return err
+}
+
+// retryDelay decides whether a failed attempt is retried: 429 after retry_after, 5xx and network errors with exponential backoff
+func (c *Client) retryDelay(ctx context.Context, err error, attempts int) (time.Duration, bool) {
+	if err == nil || attempts > c.MaxRetries || ctx.Err() != nil {
+		return 0, false
+	}
+	backoff := c.RetryBackoff * time.Duration(1<<(attempts-1))
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch {
+		case apiErr.RetryAfter > 0:
+			return time.Duration(apiErr.RetryAfter) * time.Second, true
+		case apiErr.Code >= 500:
+			return backoff, true
+		default:
+			return 0, false
+		}
+	}
+	var netErr *networkError
+	if errors.As(err, &netErr) {
+		return backoff, true
+	}
+	return 0, false
+}
+
+func (c *Client) report(method string, chatID int64, attempts int, elapsed time.Duration, err error) {
+	details := map[string]interface{}{
+		"method":   method,
+		"attempts": attempts,
+	}
+	if err != nil {
+		details["error"] = err.Error()
+	}
+	helpers.LogTelegramBotAction(method, chatID, 0, err == nil, details)
+	helpers.LogTelegramPerformance("telegram_api_"+method, elapsed, chatID, 0, map[string]interface{}{
+		"attempts": attempts,
+		"success":  err == nil,
+	})
+}
+
+// networkError wraps transport failures, the underlying *url.Error is dropped because its message contains the bot token
+type networkError struct {
+	Method string
+	Err    error
+}
+
+func (e *networkError) Error() string {
+	return fmt.Sprintf("telegram %s: %v", e.Method, e.Err)
+}
+
+func (e *networkError) Unwrap() error {
+	return e.Err
+}
+
+func (c *Client) do(req *http.Request, method string, result interface{}) error {
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return &networkError{Method: method, Err: err}
+	}
+	defer resp.Body.Close()
+
+	var apiResponse APIResponse
+	if err := json.NewDecoder(resp.Body).Decode(&apiResponse); err != nil {
+		if resp.StatusCode >= 500 {
+			return &APIError{Method: method, Code: resp.StatusCode, Description: resp.Status}
+		}
+		return fmt.Errorf("telegram %s: decode response: %w", method, err)
+	}
+	if !apiResponse.Ok {
+		apiErr := &APIError{Method: method, Code: apiResponse.ErrorCode, Description: apiResponse.Description}
+		if apiResponse.Parameters != nil {
+			apiErr.RetryAfter = apiResponse.Parameters.RetryAfter
+		}
+		return apiErr
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(apiResponse.Result, result)
+}
+
+type GetUpdatesParams struct {
+	Offset         int64    `json:"offset,omitempty"`
+	Limit          int      `json:"limit,omitempty"`
+	Timeout        int      `json:"timeout,omitempty"`
+	AllowedUpdates []string `json:"allowed_updates,omitempty"`
+}
+
+// GetUpdates long-polls Telegram for new updates. The HTTP timeout is extended to cover the long-poll timeout.
+// Failures are not retried here, the poller has its own backoff.
+func (c *Client) GetUpdates(ctx context.Context, params GetUpdatesParams) ([]Update, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(params.Timeout)*time.Second+c.HTTPClient.Timeout)
+	defer cancel()
+
+	httpClient := *c.HTTPClient
+	httpClient.Timeout = 0
+	client := *c
+	client.HTTPClient = &httpClient
+	client.MaxRetries = 0
+
+	var updates []Update
+	if err := client.Call(ctx, "getUpdates", params, &updates); err != nil {
+		return nil, err
+	}
+	return updates, nil
+}
+
+const (
+	ParseModeHTML = "HTML"
+)
+
+// MaxMessageLength is the longest text sendMessage accepts, longer messages are rejected with 400
+const MaxMessageLength = 4096
+
+type SendMessageParams struct {
+	ChatID      int64                 `json:"chat_id"`
+	Text        string                `json:"text"`
+	ParseMode   string                `json:"parse_mode,omitempty"`
+	ReplyMarkup *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+}
+
+func (p SendMessageParams) targetChatID() int64 { return p.ChatID }
+
+func (c *Client) SendMessage(ctx context.Context, params SendMessageParams) (*Message, error) {
+	var message Message
+	if err := c.Call(ctx, "sendMessage", params, &message); err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+type EditMessageTextParams struct {
+	ChatID      int64                 `json:"chat_id"`
+	MessageID   int64                 `json:"message_id"`
+	Text        string                `json:"text"`
+	ParseMode   string                `json:"parse_mode,omitempty"`
+	ReplyMarkup *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+}
+
+func (p EditMessageTextParams) targetChatID() int64 { return p.ChatID }
+
+func (c *Client) EditMessageText(ctx context.Context, params EditMessageTextParams) error {
+	return c.Call(ctx, "editMessageText", params, nil)
+}
+
+type AnswerCallbackQueryParams struct {
+	CallbackQueryID string `json:"callback_query_id"`
+	Text            string `json:"text,omitempty"`
+	ShowAlert       bool   `json:"show_alert,omitempty"`
+}
+
+func (c *Client) AnswerCallbackQuery(ctx context.Context, params AnswerCallbackQueryParams) error {
+	return c.Call(ctx, "answerCallbackQuery", params, nil)
+}
+
+// SendDocumentParams uploads Data as a new file named FileName
+type SendDocumentParams struct {
+	ChatID    int64
+	FileName  string
+	Data      []byte
+	Caption   string
+	ParseMode string
+}
+
+func (c *Client) SendDocument(ctx context.Context, params SendDocumentParams) (*Message, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	fields := map[string]string{
+		"chat_id":    strconv.FormatInt(params.ChatID, 10),
+		"caption":    params.Caption,
+		"parse_mode": params.ParseMode,
+	}
+	for name, value := range fields {
+		if value == "" {
+			continue
+		}
+		if err := writer.WriteField(name, value); err != nil {
+			return nil, err
+		}
+	}
+	part, err := writer.CreateFormFile("document", params.FileName)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := part.Write(params.Data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	var message Message
+	err = c.execute(ctx, "sendDocument", params.ChatID, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.methodURL("sendDocument"), bytes.NewReader(body.Bytes()))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		return req, nil
+	}, &message)
+	if err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+// MaxDownloadSize is the largest file the Bot API lets a bot download with getFile (20 MB)
+const MaxDownloadSize = 20 << 20
+
+type GetFileParams struct {
+	FileID string `json:"file_id"`
+}
+
+func (c *Client) GetFile(ctx context.Context, params GetFileParams) (*File, error) {
+	var file File
+	if err := c.Call(ctx, "getFile", params, &file); err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
+
+// DownloadFile fetches the content of a file returned by GetFile, files larger than maxSize bytes are rejected
+func (c *Client) DownloadFile(ctx context.Context, file *File, maxSize int64) ([]byte, error) {
+	if file.FilePath == "" {
+		return nil, errors.New("telegram downloadFile: file has no file_path")
+	}
+	if file.FileSize > maxSize {
+		return nil, fmt.Errorf("telegram downloadFile: file is %d bytes, max %d", file.FileSize, maxSize)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/file/bot%s/%s", c.BaseURL, c.Token, file.FilePath), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, &networkError{Method: "downloadFile", Err: err}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, &APIError{Method: "downloadFile", Code: resp.StatusCode, Description: resp.Status}
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return nil, &networkError{Method: "downloadFile", Err: err}
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("telegram downloadFile: file is larger than %d bytes", maxSize)
+	}
+	return data, nil
+}
+
+type SetMyCommandsParams struct {
+	Commands     []BotCommand `json:"commands"`
+	LanguageCode string       `json:"language_code,omitempty"`
+}
+
+func (c *Client) SetMyCommands(ctx context.Context, params SetMyCommandsParams) error {
+	return c.Call(ctx, "setMyCommands", params, nil)
+}
+
+type SetWebhookParams struct {
+	URL                string   `json:"url"`
+	SecretToken        string   `json:"secret_token,omitempty"`
+	AllowedUpdates     []string `json:"allowed_updates,omitempty"`
+	DropPendingUpdates bool     `json:"drop_pending_updates,omitempty"`
+}
+
+func (c *Client) SetWebhook(ctx context.Context, params SetWebhookParams) error {
+	return c.Call(ctx, "setWebhook", params, nil)
+}
+
+type DeleteWebhookParams struct {
+	DropPendingUpdates bool `json:"drop_pending_updates,omitempty"`
+}
+
+// DeleteWebhook is needed before getUpdates can be used on a bot that had a webhook
+func (c *Client) DeleteWebhook(ctx context.Context, params DeleteWebhookParams) error {
+	return c.Call(ctx, "deleteWebhook", params, nil)
+}
@@ -0,0 +1,288 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeBotAPI is a minimal Bot API server, handler answers one method call
+type fakeBotAPI struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func newFakeBotAPI(t *testing.T, handler func(method string, r *http.Request, call int) (int, string)) (*fakeBotAPI, *Client) {
+	t.Helper()
+	fake := &fakeBotAPI{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/bot123:secret/") {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `{"ok":false,"error_code":404,"description":"Not Found"}`)
+			return
+		}
+		method := strings.TrimPrefix(r.URL.Path, "/bot123:secret/")
+		fake.mu.Lock()
+		fake.calls = append(fake.calls, method)
+		call := len(fake.calls)
+		fake.mu.Unlock()
+
+		status, body := handler(method, r, call)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = io.WriteString(w, body)
+	}))
+	t.Cleanup(server.Close)
+
+	t.Setenv("TELEGRAM_API_BASE_URL", server.URL+"/")
+	t.Setenv("TELEGRAM_BOT_TOKEN", "123:secret")
+	client := NewClient()
+	client.RetryBackoff = time.Millisecond
+	return fake, client
+}
+
+func (f *fakeBotAPI) Calls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.calls...)
+}
+
+func TestNewClientFromEnv(t *testing.T) {
+	_, client := newFakeBotAPI(t, func(string, *http.Request, int) (int, string) {
+		return http.StatusOK, `{"ok":true,"result":true}`
+	})
+	if strings.HasSuffix(client.BaseURL, "/") {
+		t.Errorf("BaseURL %q should not end with a slash", client.BaseURL)
+	}
+	if client.BotID() != 123 {
+		t.Errorf("BotID() = %d, want 123", client.BotID())
+	}
+}
+
+func TestSendMessage(t *testing.T) {
+	var got SendMessageParams
+	fake, client := newFakeBotAPI(t, func(method string, r *http.Request, call int) (int, string) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		return http.StatusOK, `{"ok":true,"result":{"message_id":7,"chat":{"id":42,"type":"private"},"text":"halo"}}`
+	})
+
+	message, err := client.SendMessage(context.Background(), SendMessageParams{ChatID: 42, Text: "halo", ParseMode: ParseModeHTML})
+	if err != nil {
+		t.Fatalf("SendMessage returned error: %v", err)
+	}
+	if message.MessageID != 7 || message.Chat.ID != 42 {
+		t.Errorf("unexpected message %+v", message)
+	}
+	if got.ChatID != 42 || got.Text != "halo" || got.ParseMode != ParseModeHTML {
+		t.Errorf("unexpected request %+v", got)
+	}
+	if calls := fake.Calls(); len(calls) != 1 || calls[0] != "sendMessage" {
+		t.Errorf("calls = %v", calls)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	fake, client := newFakeBotAPI(t, func(method string, r *http.Request, call int) (int, string) {
+		if call == 1 {
+			return http.StatusTooManyRequests, `{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 1","parameters":{"retry_after":1}}`
+		}
+		return http.StatusOK, `{"ok":true,"result":true}`
+	})
+
+	started := time.Now()
+	if err := client.EditMessageText(context.Background(), EditMessageTextParams{ChatID: 42, MessageID: 1, Text: "x"}); err != nil {
+		t.Fatalf("EditMessageText returned error: %v", err)
+	}
+	if elapsed := time.Since(started); elapsed < time.Second {
+		t.Errorf("retry happened after %s, want at least retry_after (1s)", elapsed)
+	}
+	if calls := fake.Calls(); len(calls) != 2 {
+		t.Errorf("calls = %v, want 2", calls)
+	}
+}
+
+func TestRetryServerErrors(t *testing.T) {
+	fake, client := newFakeBotAPI(t, func(method string, r *http.Request, call int) (int, string) {
+		return http.StatusBadGateway, `bad gateway`
+	})
+
+	err := client.AnswerCallbackQuery(context.Background(), AnswerCallbackQueryParams{CallbackQueryID: "1"})
+	apiErr, ok := err.(*APIError)
+	if !ok || apiErr.Code != http.StatusBadGateway {
+		t.Fatalf("error = %v, want APIError 502", err)
+	}
+	if calls := fake.Calls(); len(calls) != DefaultMaxRetries+1 {
+		t.Errorf("calls = %d, want %d", len(calls), DefaultMaxRetries+1)
+	}
+}
+
+func TestNoRetryOnClientErrors(t *testing.T) {
+	fake, client := newFakeBotAPI(t, func(method string, r *http.Request, call int) (int, string) {
+		return http.StatusBadRequest, `{"ok":false,"error_code":400,"description":"Bad Request: chat not found"}`
+	})
+
+	err := client.SetWebhook(context.Background(), SetWebhookParams{URL: "https://example.com/v1/telegram/webhook"})
+	apiErr, ok := err.(*APIError)
+	if !ok || apiErr.Code != http.StatusBadRequest || apiErr.Method != "setWebhook" {
+		t.Fatalf("error = %v, want APIError 400", err)
+	}
+	if calls := fake.Calls(); len(calls) != 1 {
+		t.Errorf("calls = %d, want 1", len(calls))
+	}
+}
+
+func TestSendDocument(t *testing.T) {
+	_, client := newFakeBotAPI(t, func(method string, r *http.Request, call int) (int, string) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("parse multipart: %v", err)
+			return http.StatusBadRequest, `{"ok":false,"error_code":400,"description":"bad"}`
+		}
+		if r.FormValue("chat_id") != "42" || r.FormValue("caption") != "rekap" {
+			t.Errorf("unexpected fields %v", r.MultipartForm.Value)
+		}
+		file, header, err := r.FormFile("document")
+		if err != nil {
+			t.Errorf("missing document: %v", err)
+			return http.StatusBadRequest, `{"ok":false,"error_code":400,"description":"bad"}`
+		}
+		defer file.Close()
+		data, _ := io.ReadAll(file)
+		if header.Filename != "lembur.csv" || string(data) != "a,b\n" {
+			t.Errorf("unexpected document %q %q", header.Filename, data)
+		}
+		return http.StatusOK, `{"ok":true,"result":{"message_id":9,"chat":{"id":42,"type":"private"}}}`
+	})
+
+	message, err := client.SendDocument(context.Background(), SendDocumentParams{ChatID: 42, FileName: "lembur.csv", Data: []byte("a,b\n"), Caption: "rekap"})
+	if err != nil {
+		t.Fatalf("SendDocument returned error: %v", err)
+	}
+	if message.MessageID != 9 {
+		t.Errorf("unexpected message %+v", message)
+	}
+}
+
+func TestSetMyCommands(t *testing.T) {
+	var got SetMyCommandsParams
+	_, client := newFakeBotAPI(t, func(method string, r *http.Request, call int) (int, string) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		return http.StatusOK, `{"ok":true,"result":true}`
+	})
+
+	err := client.SetMyCommands(context.Background(), SetMyCommandsParams{Commands: []BotCommand{{Command: "help", Description: "Bantuan"}}})
+	if err != nil {
+		t.Fatalf("SetMyCommands returned error: %v", err)
+	}
+	if len(got.Commands) != 1 || got.Commands[0].Command != "help" {
+		t.Errorf("unexpected request %+v", got)
+	}
+}
+
+func TestErrorDoesNotLeakToken(t *testing.T) {
+	t.Setenv("TELEGRAM_API_BASE_URL", "http://127.0.0.1:1")
+	t.Setenv("TELEGRAM_BOT_TOKEN", "123:secret")
+	client := NewClient()
+	client.MaxRetries = 0
+
+	_, err := client.SendMessage(context.Background(), SendMessageParams{ChatID: 42, Text: "x"})
+	if err == nil {
+		t.Fatal("expected network error")
+	}
+	if strings.Contains(err.Error(), "secret") {
+		t.Errorf("error %q contains the bot token", err.Error())
+	}
+}
+
+func TestRateLimiterPerChat(t *testing.T) {
+	limiter := &RateLimiter{GlobalInterval: time.Millisecond, ChatInterval: time.Second, GroupInterval: 3 * time.Second}
+	now := time.Now()
+
+	if delay := limiter.reserve(42, now); delay != 0 {
+		t.Errorf("first message delayed by %s", delay)
+	}
+	if delay := limiter.reserve(42, now); delay < time.Second || delay > time.Second+10*time.Millisecond {
+		t.Errorf("second message to the same chat delayed by %s, want 1s", delay)
+	}
+	if delay := limiter.reserve(43, now); delay != 2*time.Millisecond {
+		t.Errorf("message to another chat delayed by %s, want only the global interval", delay)
+	}
+	limiter.reserve(-100, now)
+	if delay := limiter.reserve(-100, now); delay < 3*time.Second || delay > 3*time.Second+10*time.Millisecond {
+		t.Errorf("second message to a group delayed by %s, want 3s", delay)
+	}
+}
+
+func TestRateLimiterGlobal(t *testing.T) {
+	limiter := &RateLimiter{GlobalInterval: DefaultGlobalInterval, ChatInterval: time.Second, GroupInterval: time.Second}
+	now := time.Now()
+
+	var last time.Duration
+	for chatID := int64(1); chatID <= 31; chatID++ {
+		last = limiter.reserve(chatID, now)
+	}
+	// the 31st message to distinct chats waits until the second second
+	if last < 30*DefaultGlobalInterval {
+		t.Errorf("31st message delayed by %s, want at least %s", last, 30*DefaultGlobalInterval)
+	}
+}
+
+func TestRateLimiterWaitHonoursContext(t *testing.T) {
+	limiter := &RateLimiter{GlobalInterval: time.Millisecond, ChatInterval: time.Hour, GroupInterval: time.Hour}
+	_ = limiter.Wait(context.Background(), 42)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx, 42); err != context.DeadlineExceeded {
+		t.Errorf("Wait = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestGetFileAndDownload(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/bot123:secret/getFile":
+			var params GetFileParams
+			_ = json.NewDecoder(r.Body).Decode(&params)
+			_, _ = io.WriteString(w, `{"ok":true,"result":{"file_id":"`+params.FileID+`","file_unique_id":"u1","file_size":5,"file_path":"photos/file_1.jpg"}}`)
+		case "/file/bot123:secret/photos/file_1.jpg":
+			_, _ = io.WriteString(w, "hello")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("TELEGRAM_API_BASE_URL", server.URL)
+	t.Setenv("TELEGRAM_BOT_TOKEN", "123:secret")
+	client := NewClient()
+
+	file, err := client.GetFile(context.Background(), GetFileParams{FileID: "abc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.FileID != "abc" || file.FilePath != "photos/file_1.jpg" {
+		t.Fatalf("unexpected file %+v", file)
+	}
+	data, err := client.DownloadFile(context.Background(), file, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "hello" {
+		t.Errorf("DownloadFile = %q, want hello", data)
+	}
+
+	if _, err := client.DownloadFile(context.Background(), &File{FilePath: "photos/file_1.jpg"}, 4); err == nil {
+		t.Error("expected an error for a file larger than maxSize")
+	}
+	_, err = client.DownloadFile(context.Background(), &File{FilePath: "photos/missing.jpg"}, 5)
+	if err == nil || strings.Contains(err.Error(), "secret") {
+		t.Errorf("DownloadFile missing = %v, want an error without the bot token", err)
+	}
+}
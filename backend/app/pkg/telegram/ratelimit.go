@@ -0,0 +1,107 @@
+package telegram
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Telegram limits: about 30 messages per second overall, 1 per second per private chat and 20 per minute per group
+const (
+	DefaultGlobalInterval = time.Second / 30
+	DefaultChatInterval   = time.Second
+	DefaultGroupInterval  = time.Minute / 20
+)
+
+// RateLimiter spaces outgoing messages so the bot stays under the global and per-chat limits.
+// Slots are reserved up front, so concurrent callers queue in order instead of bursting.
+type RateLimiter struct {
+	GlobalInterval time.Duration
+	ChatInterval   time.Duration
+	GroupInterval  time.Duration
+
+	mu         sync.Mutex
+	nextGlobal time.Time
+	nextChat   map[int64]time.Time
+}
+
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{
+		GlobalInterval: DefaultGlobalInterval,
+		ChatInterval:   DefaultChatInterval,
+		GroupInterval:  DefaultGroupInterval,
+		nextChat:       make(map[int64]time.Time),
+	}
+}
+
+// Wait blocks until a message to chatID may be sent. Negative chat IDs are groups and channels.
+func (l *RateLimiter) Wait(ctx context.Context, chatID int64) error {
+	delay := l.reserve(chatID, time.Now())
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Delay pushes the next slot of chatID (or every chat when chatID is 0) back, used after a 429 response
+func (l *RateLimiter) Delay(chatID int64, d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	until := time.Now().Add(d)
+	if chatID == 0 {
+		if l.nextGlobal.Before(until) {
+			l.nextGlobal = until
+		}
+		return
+	}
+	if l.nextChat == nil {
+		l.nextChat = make(map[int64]time.Time)
+	}
+	if l.nextChat[chatID].Before(until) {
+		l.nextChat[chatID] = until
+	}
+}
+
+func (l *RateLimiter) reserve(chatID int64, now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.nextChat == nil {
+		l.nextChat = make(map[int64]time.Time)
+	}
+
+	// the global slot is taken now even when the chat itself has to wait, a slow chat must not hold up the others
+	globalAt := now
+	if l.nextGlobal.After(globalAt) {
+		globalAt = l.nextGlobal
+	}
+	l.nextGlobal = globalAt.Add(l.GlobalInterval)
+
+	at := globalAt
+	if chatID != 0 {
+		if next := l.nextChat[chatID]; next.After(at) {
+			at = next
+		}
+		interval := l.ChatInterval
+		if chatID < 0 {
+			interval = l.GroupInterval
+		}
+		l.nextChat[chatID] = at.Add(interval)
+	}
+
+	// forget chats that have been idle, the map would grow with every chat otherwise
+	if len(l.nextChat) > 1024 {
+		for id, next := range l.nextChat {
+			if next.Before(now) {
+				delete(l.nextChat, id)
+			}
+		}
+	}
+	return at.Sub(now)
+}
@@ -0,0 +1,181 @@
+package telegram
+
+// Subset of the Telegram Bot API types used by this backend
+// https://core.telegram.org/bots/api#available-types
+
+const (
+	UpdateTypeMessage       = "message"
+	UpdateTypeEditedMessage = "edited_message"
+	UpdateTypeCallbackQuery = "callback_query"
+	UpdateTypeInlineQuery   = "inline_query"
+	UpdateTypeMyChatMember  = "my_chat_member"
+	UpdateTypeUnknown       = "unknown"
+)
+
+type Update struct {
+	UpdateID      int64              `json:"update_id"`
+	Message       *Message           `json:"message,omitempty"`
+	EditedMessage *Message           `json:"edited_message,omitempty"`
+	CallbackQuery *CallbackQuery     `json:"callback_query,omitempty"`
+	InlineQuery   *InlineQuery       `json:"inline_query,omitempty"`
+	MyChatMember  *ChatMemberUpdated `json:"my_chat_member,omitempty"`
+}
+
+// Type returns the kind of payload carried by the update
+func (u *Update) Type() string {
+	switch {
+	case u.Message != nil:
+		return UpdateTypeMessage
+	case u.EditedMessage != nil:
+		return UpdateTypeEditedMessage
+	case u.CallbackQuery != nil:
+		return UpdateTypeCallbackQuery
+	case u.InlineQuery != nil:
+		return UpdateTypeInlineQuery
+	case u.MyChatMember != nil:
+		return UpdateTypeMyChatMember
+	default:
+		return UpdateTypeUnknown
+	}
+}
+
+// Sender returns the user who triggered the update, nil if unknown
+func (u *Update) Sender() *User {
+	switch {
+	case u.Message != nil:
+		return u.Message.From
+	case u.EditedMessage != nil:
+		return u.EditedMessage.From
+	case u.CallbackQuery != nil:
+		return &u.CallbackQuery.From
+	case u.InlineQuery != nil:
+		return &u.InlineQuery.From
+	case u.MyChatMember != nil:
+		return &u.MyChatMember.From
+	default:
+		return nil
+	}
+}
+
+// ChatID returns the chat where the update happened, 0 if there is no chat
+func (u *Update) ChatID() int64 {
+	switch {
+	case u.Message != nil:
+		return u.Message.Chat.ID
+	case u.EditedMessage != nil:
+		return u.EditedMessage.Chat.ID
+	case u.CallbackQuery != nil && u.CallbackQuery.Message != nil:
+		return u.CallbackQuery.Message.Chat.ID
+	case u.MyChatMember != nil:
+		return u.MyChatMember.Chat.ID
+	default:
+		return 0
+	}
+}
+
+type User struct {
+	ID           int64  `json:"id"`
+	IsBot        bool   `json:"is_bot"`
+	FirstName    string `json:"first_name"`
+	LastName     string `json:"last_name,omitempty"`
+	Username     string `json:"username,omitempty"`
+	LanguageCode string `json:"language_code,omitempty"`
+}
+
+type Chat struct {
+	ID        int64  `json:"id"`
+	Type      string `json:"type"`
+	Title     string `json:"title,omitempty"`
+	Username  string `json:"username,omitempty"`
+	FirstName string `json:"first_name,omitempty"`
+	LastName  string `json:"last_name,omitempty"`
+}
+
+type Message struct {
+	MessageID      int64           `json:"message_id"`
+	From           *User           `json:"from,omitempty"`
+	Chat           Chat            `json:"chat"`
+	Date           int64           `json:"date"`
+	EditDate       int64           `json:"edit_date,omitempty"`
+	Text           string          `json:"text,omitempty"`
+	Entities       []MessageEntity `json:"entities,omitempty"`
+	Caption        string          `json:"caption,omitempty"`
+	Photo          []PhotoSize     `json:"photo,omitempty"`
+	Document       *Document       `json:"document,omitempty"`
+	ReplyToMessage *Message        `json:"reply_to_message,omitempty"`
+}
+
+type MessageEntity struct {
+	Type   string `json:"type"`
+	Offset int    `json:"offset"`
+	Length int    `json:"length"`
+}
+
+type PhotoSize struct {
+	FileID       string `json:"file_id"`
+	FileUniqueID string `json:"file_unique_id"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+	FileSize     int64  `json:"file_size,omitempty"`
+}
+
+type Document struct {
+	FileID       string `json:"file_id"`
+	FileUniqueID string `json:"file_unique_id"`
+	FileName     string `json:"file_name,omitempty"`
+	MimeType     string `json:"mime_type,omitempty"`
+	FileSize     int64  `json:"file_size,omitempty"`
+}
+
+// File is the result of getFile, FilePath is valid for at least one hour
+type File struct {
+	FileID       string `json:"file_id"`
+	FileUniqueID string `json:"file_unique_id"`
+	FileSize     int64  `json:"file_size,omitempty"`
+	FilePath     string `json:"file_path,omitempty"`
+}
+
+type CallbackQuery struct {
+	ID              string   `json:"id"`
+	From            User     `json:"from"`
+	Message         *Message `json:"message,omitempty"`
+	InlineMessageID string   `json:"inline_message_id,omitempty"`
+	ChatInstance    string   `json:"chat_instance"`
+	Data            string   `json:"data,omitempty"`
+}
+
+type InlineQuery struct {
+	ID       string `json:"id"`
+	From     User   `json:"from"`
+	Query    string `json:"query"`
+	Offset   string `json:"offset"`
+	ChatType string `json:"chat_type,omitempty"`
+}
+
+type ChatMemberUpdated struct {
+	Chat          Chat       `json:"chat"`
+	From          User       `json:"from"`
+	Date          int64      `json:"date"`
+	OldChatMember ChatMember `json:"old_chat_member"`
+	NewChatMember ChatMember `json:"new_chat_member"`
+}
+
+type ChatMember struct {
+	Status string `json:"status"`
+	User   User   `json:"user"`
+}
+
+type InlineKeyboardMarkup struct {
+	InlineKeyboard [][]InlineKeyboardButton `json:"inline_keyboard"`
+}
+
+type InlineKeyboardButton struct {
+	Text         string `json:"text"`
+	CallbackData string `json:"callback_data,omitempty"`
+	URL          string `json:"url,omitempty"`
+}
+
+type BotCommand struct {
+	Command     string `json:"command"`
+	Description string `json:"description"`
+}
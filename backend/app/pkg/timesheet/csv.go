@@ -0,0 +1,25 @@
+package timesheet
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// WriteCSV writes the timesheet as CSV with a UTF-8 BOM so spreadsheet apps detect the encoding
+func WriteCSV(w io.Writer, sheet *Sheet) error {
+	if _, err := io.WriteString(w, "\ufeff"); err != nil {
+		return err
+	}
+	writer := csv.NewWriter(w)
+	for _, line := range layout(sheet) {
+		record := make([]string, len(line.cells))
+		for i, cell := range line.cells {
+			record[i] = cell.String()
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,205 @@
+package timesheet
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A4 landscape in points, text is set in the standard Helvetica fonts so nothing has to be embedded
+const (
+	pdfPageWidth  = 842.0
+	pdfPageHeight = 595.0
+	pdfMargin     = 40.0
+	pdfFontSize   = 9.0
+	pdfLeading    = 14.0
+	pdfTitleSize  = 14.0
+	// pdfCharWidth approximates the average Helvetica glyph width as a share of the font size
+	pdfCharWidth = 0.5
+)
+
+// pdfTableWidths follow tableHeader, the description takes the rest of the line
+var pdfTableWidths = []float64{25, 60, 38, 42, 70, 60, 95, 60, pdfPageWidth - 2*pdfMargin - 450}
+
+// pdfSummaryX are the columns of the period header and the category subtotals
+var pdfSummaryX = []float64{pdfMargin, pdfMargin + 160, pdfMargin + 260, pdfMargin + 360}
+
+// pdfDocument collects the content stream of every page
+type pdfDocument struct {
+	pages  []*bytes.Buffer
+	page   *bytes.Buffer
+	y      float64
+	header []cell // table header, repeated when the records continue on the next page
+}
+
+func (d *pdfDocument) newPage() {
+	d.page = &bytes.Buffer{}
+	d.pages = append(d.pages, d.page)
+	d.y = pdfPageHeight - pdfMargin
+}
+
+// ensure starts a new page when less than height is left above the footer
+func (d *pdfDocument) ensure(height float64) bool {
+	if d.page != nil && d.y-height >= pdfMargin+20 {
+		return false
+	}
+	d.newPage()
+	return true
+}
+
+func (d *pdfDocument) text(x float64, y float64, bold bool, size float64, value string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(d.page, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, pdfString(value))
+}
+
+func (d *pdfDocument) rule(y float64) {
+	fmt.Fprintf(d.page, "0.5 w %.2f %.2f m %.2f %.2f l S\n", pdfMargin, y, pdfPageWidth-pdfMargin, y)
+}
+
+func (d *pdfDocument) tableRow(cells []cell, bold bool) {
+	x := pdfMargin
+	for i, cell := range cells {
+		if i >= len(pdfTableWidths) {
+			break
+		}
+		d.text(x, d.y, bold, pdfFontSize, truncate(cell.String(), pdfTableWidths[i]))
+		x += pdfTableWidths[i]
+	}
+}
+
+// WritePDF writes the timesheet as a printable PDF ending with signature lines for the employee and HR
+func WritePDF(w io.Writer, sheet *Sheet) error {
+	doc := &pdfDocument{}
+	for _, line := range layout(sheet) {
+		switch line.kind {
+		case lineTitle:
+			doc.ensure(pdfLeading * 2)
+			doc.text(pdfMargin, doc.y, true, pdfTitleSize, line.cells[0].String())
+			doc.y -= pdfLeading * 1.6
+		case lineBlank:
+			doc.y -= pdfLeading / 2
+		case lineMeta, lineSubtotalHeader, lineSubtotal:
+			doc.ensure(pdfLeading)
+			for i, cell := range line.cells {
+				bold := line.kind == lineSubtotalHeader || (line.kind == lineMeta && i == 0)
+				doc.text(pdfSummaryX[i], doc.y, bold, pdfFontSize, cell.String())
+			}
+			doc.y -= pdfLeading
+		case lineHeader:
+			doc.header = line.cells
+			doc.ensure(pdfLeading * 2)
+			doc.tableRow(line.cells, true)
+			doc.rule(doc.y - 4)
+			doc.y -= pdfLeading
+		case lineData:
+			if doc.ensure(pdfLeading) {
+				doc.tableRow(doc.header, true)
+				doc.rule(doc.y - 4)
+				doc.y -= pdfLeading
+			}
+			doc.tableRow(line.cells, false)
+			doc.y -= pdfLeading
+		case lineTotal:
+			doc.ensure(pdfLeading)
+			doc.rule(doc.y + pdfLeading - 4)
+			doc.tableRow(line.cells, true)
+			doc.y -= pdfLeading
+		}
+	}
+
+	// signature block
+	doc.ensure(pdfLeading * 7)
+	doc.y -= pdfLeading * 2
+	rightX := pdfPageWidth - pdfMargin - 200
+	doc.text(pdfMargin, doc.y, false, pdfFontSize, "Karyawan,")
+	doc.text(rightX, doc.y, false, pdfFontSize, "Mengetahui (Atasan / HR),")
+	doc.y -= pdfLeading * 4
+	doc.text(pdfMargin, doc.y, false, pdfFontSize, "( "+sheet.Employee+" )")
+	doc.text(rightX, doc.y, false, pdfFontSize, "( ______________________ )")
+
+	footer := sheet.Employee + " - " + sheet.Start.Format("2006-01-02") + " s/d " + sheet.End.Format("2006-01-02")
+	for i, page := range doc.pages {
+		fmt.Fprintf(page, "BT /F1 8.0 Tf %.2f %.2f Td (%s) Tj ET\n", pdfMargin, pdfMargin-10, pdfString(footer))
+		fmt.Fprintf(page, "BT /F1 8.0 Tf %.2f %.2f Td (%s) Tj ET\n", pdfPageWidth-pdfMargin-70, pdfMargin-10, pdfString(fmt.Sprintf("Halaman %d dari %d", i+1, len(doc.pages))))
+	}
+	return writePDFObjects(w, doc.pages)
+}
+
+// writePDFObjects assembles catalog, page tree, fonts and one page plus content stream per page, followed by the xref table
+func writePDFObjects(w io.Writer, pages []*bytes.Buffer) error {
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for i, page := range pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, 6+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// winAnsi maps the characters outside Latin-1 that WinAnsiEncoding (cp1252) still has
+var winAnsi = map[rune]byte{
+	'€': 0x80, '…': 0x85, '‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97,
+}
+
+// pdfString encodes value for a literal string in WinAnsiEncoding, characters the standard fonts lack become ?
+func pdfString(value string) string {
+	var buf strings.Builder
+	for _, r := range value {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			buf.WriteByte('\\')
+			buf.WriteRune(r)
+		case r < 0x20:
+			buf.WriteByte(' ')
+		case r < 0x80 || (r >= 0xa0 && r <= 0xff):
+			buf.WriteByte(byte(r))
+		default:
+			if b, ok := winAnsi[r]; ok {
+				buf.WriteByte(b)
+			} else {
+				buf.WriteByte('?')
+			}
+		}
+	}
+	return buf.String()
+}
+
+// truncate shortens value to roughly fit width points at the table font size
+func truncate(value string, width float64) string {
+	limit := int(width/(pdfFontSize*pdfCharWidth)) - 1
+	runes := []rune(value)
+	if len(runes) <= limit {
+		return value
+	}
+	if limit < 3 {
+		return string(runes[:limit])
+	}
+	return string(runes[:limit-2]) + ".."
+}
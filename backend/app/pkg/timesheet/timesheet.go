@@ -0,0 +1,209 @@
+// Package timesheet renders the monthly overtime timesheet of one employee as CSV, XLSX or PDF.
+// Only the standard library is used, the XLSX and PDF writers produce the minimal valid files.
+package timesheet
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+	FormatPDF  = "pdf"
+)
+
+// Formats lists the supported export formats
+var Formats = []string{FormatCSV, FormatXLSX, FormatPDF}
+
+var contentTypes = map[string]string{
+	FormatCSV:  "text/csv; charset=utf-8",
+	FormatXLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	FormatPDF:  "application/pdf",
+}
+
+// ContentType returns the MIME type of format, empty for an unknown format
+func ContentType(format string) string {
+	return contentTypes[format]
+}
+
+// Uncategorized names the subtotal of records without a category
+const Uncategorized = "Tanpa kategori"
+
+// Row is one overtime record
+type Row struct {
+	ID            uint
+	Date          time.Time
+	TimeStart     string // HH:MM
+	TimeStop      string // HH:MM
+	BreakDuration float64
+	Duration      float64
+	Category      string
+	Status        string
+	Description   string
+}
+
+// Sheet is the timesheet of one employee over a period, rows are rendered in the given order
+type Sheet struct {
+	Employee    string
+	TelegramID  int64
+	Start       time.Time
+	End         time.Time
+	GeneratedAt time.Time
+	Rows        []Row
+}
+
+// Subtotal sums the records of one category
+type Subtotal struct {
+	Category string
+	Records  int
+	Break    float64
+	Duration float64
+}
+
+// Totals sums every row
+func (s *Sheet) Totals() Subtotal {
+	total := Subtotal{Category: "Total", Records: len(s.Rows)}
+	for _, row := range s.Rows {
+		total.Break += row.BreakDuration
+		total.Duration += row.Duration
+	}
+	return total
+}
+
+// Subtotals sums the rows per category by name, records without a category come last
+func (s *Sheet) Subtotals() []Subtotal {
+	index := map[string]int{}
+	var subtotals []Subtotal
+	for _, row := range s.Rows {
+		category := row.Category
+		if category == "" {
+			category = Uncategorized
+		}
+		i, ok := index[category]
+		if !ok {
+			i = len(subtotals)
+			index[category] = i
+			subtotals = append(subtotals, Subtotal{Category: category})
+		}
+		subtotals[i].Records++
+		subtotals[i].Break += row.BreakDuration
+		subtotals[i].Duration += row.Duration
+	}
+	sort.Slice(subtotals, func(i, j int) bool {
+		if (subtotals[i].Category == Uncategorized) != (subtotals[j].Category == Uncategorized) {
+			return subtotals[j].Category == Uncategorized
+		}
+		return subtotals[i].Category < subtotals[j].Category
+	})
+	return subtotals
+}
+
+// Render writes sheet in format
+func Render(w io.Writer, format string, sheet *Sheet) error {
+	switch format {
+	case FormatCSV:
+		return WriteCSV(w, sheet)
+	case FormatXLSX:
+		return WriteXLSX(w, sheet)
+	case FormatPDF:
+		return WritePDF(w, sheet)
+	}
+	return fmt.Errorf("unsupported format %q", format)
+}
+
+// lineKind tells the writers how to style a line of the layout
+type lineKind int
+
+const (
+	lineTitle lineKind = iota
+	lineMeta
+	lineBlank
+	lineHeader
+	lineData
+	lineTotal
+	lineSubtotalHeader
+	lineSubtotal
+)
+
+type cellKind int
+
+const (
+	cellText cellKind = iota
+	cellHours
+	cellCount
+)
+
+type cell struct {
+	kind   cellKind
+	text   string
+	number float64
+}
+
+func text(value string) cell { return cell{kind: cellText, text: value} }
+
+func hours(value float64) cell { return cell{kind: cellHours, number: value} }
+
+func count(value int) cell { return cell{kind: cellCount, number: float64(value)} }
+
+// String formats the cell like the CSV and PDF writers show it
+func (c cell) String() string {
+	switch c.kind {
+	case cellHours:
+		return strconv.FormatFloat(c.number, 'f', 2, 64)
+	case cellCount:
+		return strconv.FormatFloat(c.number, 'f', 0, 64)
+	}
+	return c.text
+}
+
+type line struct {
+	kind  lineKind
+	cells []cell
+}
+
+var tableHeader = []string{"No", "Tanggal", "Mulai", "Selesai", "Istirahat (jam)", "Durasi (jam)", "Kategori", "Status", "Keterangan"}
+
+// layout is shared by every format: period header, one line per record, total and subtotals per category
+func layout(sheet *Sheet) []line {
+	lines := []line{
+		{kind: lineTitle, cells: []cell{text("Timesheet Lembur")}},
+		{kind: lineMeta, cells: []cell{text("Karyawan"), text(sheet.Employee)}},
+		{kind: lineMeta, cells: []cell{text("Telegram ID"), text(strconv.FormatInt(sheet.TelegramID, 10))}},
+		{kind: lineMeta, cells: []cell{text("Periode"), text(sheet.Start.Format("2006-01-02") + " s/d " + sheet.End.Format("2006-01-02"))}},
+		{kind: lineMeta, cells: []cell{text("Dibuat"), text(sheet.GeneratedAt.Format("2006-01-02 15:04"))}},
+		{kind: lineBlank},
+	}
+
+	header := make([]cell, len(tableHeader))
+	for i, title := range tableHeader {
+		header[i] = text(title)
+	}
+	lines = append(lines, line{kind: lineHeader, cells: header})
+	for i, row := range sheet.Rows {
+		lines = append(lines, line{kind: lineData, cells: []cell{
+			count(i + 1),
+			text(row.Date.Format("2006-01-02")),
+			text(row.TimeStart),
+			text(row.TimeStop),
+			hours(row.BreakDuration),
+			hours(row.Duration),
+			text(row.Category),
+			text(row.Status),
+			text(row.Description),
+		}})
+	}
+	totals := sheet.Totals()
+	lines = append(lines,
+		line{kind: lineTotal, cells: []cell{text("Total"), text(fmt.Sprintf("%d catatan", totals.Records)), text(""), text(""), hours(totals.Break), hours(totals.Duration)}},
+		line{kind: lineBlank},
+		line{kind: lineSubtotalHeader, cells: []cell{text("Kategori"), text("Jumlah catatan"), text("Istirahat (jam)"), text("Durasi (jam)")}},
+	)
+	for _, subtotal := range sheet.Subtotals() {
+		lines = append(lines, line{kind: lineSubtotal, cells: []cell{text(subtotal.Category), count(subtotal.Records), hours(subtotal.Break), hours(subtotal.Duration)}})
+	}
+	return lines
+}
@@ -0,0 +1,197 @@
+package timesheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+var wib = time.FixedZone("WIB", 7*60*60)
+
+func testSheet(rows int) *Sheet {
+	sheet := &Sheet{
+		Employee:    "Budi (Ops) Santoso",
+		TelegramID:  123456789,
+		Start:       time.Date(2024, time.March, 1, 0, 0, 0, 0, wib),
+		End:         time.Date(2024, time.March, 31, 0, 0, 0, 0, wib),
+		GeneratedAt: time.Date(2024, time.April, 1, 8, 0, 0, 0, wib),
+	}
+	categories := []string{"Infra", "", "Deploy"}
+	for i := 0; i < rows; i++ {
+		sheet.Rows = append(sheet.Rows, Row{
+			ID:            uint(i + 1),
+			Date:          time.Date(2024, time.March, 1+i%31, 0, 0, 0, 0, wib),
+			TimeStart:     "18:00",
+			TimeStop:      "21:00",
+			BreakDuration: 0.5,
+			Duration:      2.5,
+			Category:      categories[i%len(categories)],
+			Status:        "approved",
+			Description:   fmt.Sprintf("pekerjaan <%d> & “deploy” – server", i+1),
+		})
+	}
+	return sheet
+}
+
+func TestSubtotals(t *testing.T) {
+	sheet := testSheet(7)
+	subtotals := sheet.Subtotals()
+	want := []Subtotal{
+		{Category: "Deploy", Records: 2, Break: 1, Duration: 5},
+		{Category: "Infra", Records: 3, Break: 1.5, Duration: 7.5},
+		{Category: Uncategorized, Records: 2, Break: 1, Duration: 5},
+	}
+	if len(subtotals) != len(want) {
+		t.Fatalf("subtotals = %+v, want %+v", subtotals, want)
+	}
+	for i := range want {
+		if subtotals[i] != want[i] {
+			t.Errorf("subtotals[%d] = %+v, want %+v", i, subtotals[i], want[i])
+		}
+	}
+	if totals := sheet.Totals(); totals.Records != 7 || totals.Duration != 17.5 || totals.Break != 3.5 {
+		t.Errorf("totals = %+v", totals)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, testSheet(3)); err != nil {
+		t.Fatal(err)
+	}
+	reader := csv.NewReader(strings.NewReader(strings.TrimPrefix(buf.String(), "\ufeff")))
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	find := func(first string) []string {
+		for _, record := range records {
+			if len(record) > 0 && record[0] == first {
+				return record
+			}
+		}
+		t.Fatalf("no row starting with %q in %v", first, records)
+		return nil
+	}
+	if row := find("Karyawan"); row[1] != "Budi (Ops) Santoso" {
+		t.Errorf("employee row = %v", row)
+	}
+	if row := find("Periode"); row[1] != "2024-03-01 s/d 2024-03-31" {
+		t.Errorf("period row = %v", row)
+	}
+	if row := find("2"); row[1] != "2024-03-02" || row[5] != "2.50" || row[6] != "" {
+		t.Errorf("second record = %v", row)
+	}
+	if row := find("Total"); row[1] != "3 catatan" || row[4] != "1.50" || row[5] != "7.50" {
+		t.Errorf("total row = %v", row)
+	}
+	if row := find(Uncategorized); row[1] != "1" || row[3] != "2.50" {
+		t.Errorf("subtotal row = %v", row)
+	}
+}
+
+func TestWriteXLSX(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteXLSX(&buf, testSheet(3)); err != nil {
+		t.Fatal(err)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := map[string]string{}
+	for _, file := range archive.File {
+		reader, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := io.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		parts[file.Name] = string(content)
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml", "xl/worksheets/sheet1.xml"} {
+		content, ok := parts[name]
+		if !ok {
+			t.Fatalf("missing part %s", name)
+		}
+		decoder := xml.NewDecoder(strings.NewReader(content))
+		for {
+			if _, err := decoder.Token(); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatalf("%s is not well-formed: %v", name, err)
+			}
+		}
+	}
+	worksheet := parts["xl/worksheets/sheet1.xml"]
+	for _, want := range []string{
+		`<t xml:space="preserve">Budi (Ops) Santoso</t>`,
+		`pekerjaan &lt;1&gt; &amp; “deploy” – server`,
+		`<c r="F8" s="2"><v>2.5</v></c>`,
+	} {
+		if !strings.Contains(worksheet, want) {
+			t.Errorf("worksheet does not contain %s", want)
+		}
+	}
+}
+
+func TestWritePDF(t *testing.T) {
+	var buf bytes.Buffer
+	// enough rows for more than one page
+	if err := WritePDF(&buf, testSheet(60)); err != nil {
+		t.Fatal(err)
+	}
+	pdf := buf.String()
+	if !strings.HasPrefix(pdf, "%PDF-1.4\n") || !strings.HasSuffix(pdf, "%%EOF\n") {
+		t.Fatal("missing PDF header or trailer")
+	}
+
+	// every xref entry must point at the start of its object
+	xrefAt := strings.LastIndex(pdf, "startxref\n")
+	start, err := strconv.Atoi(strings.TrimSpace(strings.TrimSuffix(pdf[xrefAt+len("startxref\n"):], "%%EOF\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := regexp.MustCompile(`(\d{10}) 00000 n`).FindAllStringSubmatch(pdf[start:], -1)
+	if len(entries) == 0 {
+		t.Fatal("empty xref table")
+	}
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(entry[1])
+		if want := fmt.Sprintf("%d 0 obj", i+1); !strings.HasPrefix(pdf[offset:], want) {
+			t.Errorf("xref entry %d points at %q, want %q", i+1, pdf[offset:offset+10], want)
+		}
+	}
+
+	match := regexp.MustCompile(`/Count (\d+)`).FindStringSubmatch(pdf)
+	if match == nil || match[1] == "1" {
+		t.Fatalf("expected more than one page, got %v", match)
+	}
+	// the table header is repeated on the next page
+	if got := strings.Count(pdf, "(Keterangan)"); got < 2 {
+		t.Errorf("table header drawn %d times", got)
+	}
+	for _, want := range []string{`(Budi \(Ops\) Santoso)`, "Halaman " + match[1] + " dari " + match[1], "\x93deploy\x94 \x96 server"} {
+		if !strings.Contains(pdf, want) {
+			t.Errorf("pdf does not contain %q", want)
+		}
+	}
+}
+
+func TestRenderUnknownFormat(t *testing.T) {
+	if err := Render(io.Discard, "docx", testSheet(1)); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
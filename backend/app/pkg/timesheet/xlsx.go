@@ -0,0 +1,130 @@
+package timesheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strconv"
+)
+
+// xlsxParts are the fixed parts of a one sheet workbook, the sheet itself is generated
+var xlsxParts = []struct {
+	name    string
+	content string
+}{
+	{"[Content_Types].xml", xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`},
+	{"xl/workbook.xml", xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="Timesheet" sheetId="1" r:id="rId1"/></sheets>` +
+		`</workbook>`},
+	{"xl/_rels/workbook.xml.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+		`</Relationships>`},
+	// cellXfs: 0 default, 1 bold, 2 hours (0.00), 3 bold hours, 4 bold title
+	{"xl/styles.xml", xml.Header + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<fonts count="3"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="14"/><name val="Calibri"/></font></fonts>` +
+		`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+		`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+		`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+		`<cellXfs count="5">` +
+		`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+		`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+		`<xf numFmtId="2" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+		`<xf numFmtId="2" fontId="1" fillId="0" borderId="0" xfId="0" applyNumberFormat="1" applyFont="1"/>` +
+		`<xf numFmtId="0" fontId="2" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+		`</cellXfs>` +
+		`</styleSheet>`},
+}
+
+// xlsxColumnWidths follow tableHeader
+var xlsxColumnWidths = []int{6, 12, 8, 8, 15, 13, 18, 12, 48}
+
+// WriteXLSX writes the timesheet as an Office Open XML workbook with one sheet, hours are numeric cells
+func WriteXLSX(w io.Writer, sheet *Sheet) error {
+	archive := zip.NewWriter(w)
+	for _, part := range xlsxParts {
+		file, err := archive.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(file, part.content); err != nil {
+			return err
+		}
+	}
+	file, err := archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(worksheetXML(sheet)); err != nil {
+		return err
+	}
+	return archive.Close()
+}
+
+func worksheetXML(sheet *Sheet) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><cols>`)
+	for i, width := range xlsxColumnWidths {
+		column := strconv.Itoa(i + 1)
+		buf.WriteString(`<col min="` + column + `" max="` + column + `" width="` + strconv.Itoa(width) + `" customWidth="1"/>`)
+	}
+	buf.WriteString(`</cols><sheetData>`)
+	for i, line := range layout(sheet) {
+		rowNumber := strconv.Itoa(i + 1)
+		buf.WriteString(`<row r="` + rowNumber + `">`)
+		for j, cell := range line.cells {
+			ref := columnName(j) + rowNumber
+			style := xlsxStyle(line.kind, cell.kind, j)
+			if cell.kind == cellText {
+				if cell.text == "" {
+					continue
+				}
+				buf.WriteString(`<c r="` + ref + `" t="inlineStr"` + style + `><is><t xml:space="preserve">`)
+				xml.EscapeText(&buf, []byte(cell.text))
+				buf.WriteString(`</t></is></c>`)
+				continue
+			}
+			buf.WriteString(`<c r="` + ref + `"` + style + `><v>` + strconv.FormatFloat(cell.number, 'f', -1, 64) + `</v></c>`)
+		}
+		buf.WriteString(`</row>`)
+	}
+	buf.WriteString(`</sheetData></worksheet>`)
+	return buf.Bytes()
+}
+
+// xlsxStyle picks the cellXfs index of styles.xml
+func xlsxStyle(line lineKind, kind cellKind, column int) string {
+	bold := line == lineHeader || line == lineTotal || line == lineSubtotalHeader || (line == lineMeta && column == 0)
+	switch {
+	case line == lineTitle:
+		return ` s="4"`
+	case kind == cellHours && bold:
+		return ` s="3"`
+	case kind == cellHours:
+		return ` s="2"`
+	case bold:
+		return ` s="1"`
+	}
+	return ""
+}
+
+// columnName converts a zero based column index to A, B, ... Z, AA
+func columnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
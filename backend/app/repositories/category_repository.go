@@ -0,0 +1,88 @@
+package repositories
+
+import (
+	"strings"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/entities"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type CategoryRepository struct{}
+
+// Create menambahkan satu kategori
+func (r *CategoryRepository) Create(category *entities.Category, c *fiber.Ctx, tx *gorm.DB) error {
+	err := tx.WithContext(c.Context()).Create(&category).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindAll mengambil kategori urut nama, activeOnly melewati kategori nonaktif
+func (r *CategoryRepository) FindAll(activeOnly bool, categories *[]entities.Category, c *fiber.Ctx, tx *gorm.DB) error {
+	query := tx.WithContext(c.Context())
+	if activeOnly {
+		query = query.Where("active = ?", true)
+	}
+	err := query.Order("lower(name) ASC").Find(&categories).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindByID mengambil kategori berdasarkan ID
+func (r *CategoryRepository) FindByID(id uint, category *entities.Category, c *fiber.Ctx, tx *gorm.DB) error {
+	err := tx.WithContext(c.Context()).First(&category, id).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindByName mengambil kategori berdasarkan nama tanpa membedakan huruf besar/kecil
+func (r *CategoryRepository) FindByName(name string, category *entities.Category, c *fiber.Ctx, tx *gorm.DB) error {
+	err := tx.WithContext(c.Context()).
+		Where("lower(name) = ?", strings.ToLower(strings.TrimSpace(name))).
+		First(&category).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// CountOvertimes menghitung lembur yang memakai kategori, termasuk yang ada di trash agar restore tidak kehilangan kategorinya
+func (r *CategoryRepository) CountOvertimes(id uint, c *fiber.Ctx, tx *gorm.DB) (int64, error) {
+	var count int64
+	err := tx.WithContext(c.Context()).
+		Unscoped().
+		Model(&entities.Overtime{}).
+		Where("category_id = ?", id).
+		Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// Update mengubah kolom kategori
+func (r *CategoryRepository) Update(id uint, updates map[string]interface{}, c *fiber.Ctx, tx *gorm.DB) error {
+	err := tx.WithContext(c.Context()).
+		Model(&entities.Category{}).
+		Where("id = ?", id).
+		Updates(updates).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Delete menghapus kategori
+func (r *CategoryRepository) Delete(id uint, c *fiber.Ctx, tx *gorm.DB) error {
+	err := tx.WithContext(c.Context()).Delete(&entities.Category{}, id).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package repositories
+
+import (
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/entities"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type ConversationStateRepository struct{}
+
+func (r *ConversationStateRepository) FindByChatID(chatID int64, state *entities.ConversationState, c *fiber.Ctx, tx *gorm.DB) error {
+	err := tx.WithContext(c.Context()).Where("chat_id = ?", chatID).First(&state).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Save insert atau update state percakapan untuk chat
+func (r *ConversationStateRepository) Save(state *entities.ConversationState, c *fiber.Ctx, tx *gorm.DB) error {
+	err := tx.WithContext(c.Context()).Save(&state).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *ConversationStateRepository) DeleteByChatID(chatID int64, c *fiber.Ctx, tx *gorm.DB) error {
+	err := tx.WithContext(c.Context()).Where("chat_id = ?", chatID).Delete(&entities.ConversationState{}).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/entities"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type HolidayRepository struct{}
+
+// Create menambahkan satu hari libur
+func (r *HolidayRepository) Create(holiday *entities.Holiday, c *fiber.Ctx, tx *gorm.DB) error {
+	err := tx.WithContext(c.Context()).Create(&holiday).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindByID mengambil hari libur berdasarkan ID
+func (r *HolidayRepository) FindByID(id uint, holiday *entities.Holiday, c *fiber.Ctx, tx *gorm.DB) error {
+	err := tx.WithContext(c.Context()).First(&holiday, id).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindBetween mengambil hari libur antara dua tanggal (inklusif), urut tanggal
+func (r *HolidayRepository) FindBetween(startDate time.Time, endDate time.Time, holidays *[]entities.Holiday, c *fiber.Ctx, tx *gorm.DB) error {
+	err := tx.WithContext(c.Context()).
+		Where("date BETWEEN ? AND ?", startDate.Format("2006-01-02"), endDate.Format("2006-01-02")).
+		Order("date ASC").
+		Find(&holidays).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Update mengubah kolom hari libur
+func (r *HolidayRepository) Update(id uint, updates map[string]interface{}, c *fiber.Ctx, tx *gorm.DB) error {
+	err := tx.WithContext(c.Context()).
+		Model(&entities.Holiday{}).
+		Where("id = ?", id).
+		Updates(updates).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Delete menghapus hari libur
+func (r *HolidayRepository) Delete(id uint, c *fiber.Ctx, tx *gorm.DB) error {
+	err := tx.WithContext(c.Context()).Delete(&entities.Holiday{}, id).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// UpsertMany menyimpan hasil import, tanggal yang sudah ada ditimpa nama, jenis dan sumbernya
+func (r *HolidayRepository) UpsertMany(holidays []entities.Holiday, c *fiber.Ctx, tx *gorm.DB) error {
+	err := tx.WithContext(c.Context()).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "date"}},
+			DoUpdates: clause.AssignmentColumns([]string{"name", "kind", "source", "updated_at"}),
+		}).
+		CreateInBatches(&holidays, 200).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/entities"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type OvertimeAttachmentRepository struct{}
+
+// Create menyimpan metadata lampiran, file sudah disimpan di blobstore sebelumnya
+func (r *OvertimeAttachmentRepository) Create(attachment *entities.OvertimeAttachment, c *fiber.Ctx, tx *gorm.DB) error {
+	err := tx.WithContext(c.Context()).Create(&attachment).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// ListByOvertimeID mengambil lampiran satu record lembur, urut dari yang pertama diunggah
+func (r *OvertimeAttachmentRepository) ListByOvertimeID(overtimeID uint, attachments *[]entities.OvertimeAttachment, c *fiber.Ctx, tx *gorm.DB) error {
+	err := tx.WithContext(c.Context()).
+		Where("overtime_id = ?", overtimeID).
+		Order("id ASC").
+		Find(&attachments).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// CountByOvertimeID menghitung lampiran satu record lembur untuk batas jumlah lampiran
+func (r *OvertimeAttachmentRepository) CountByOvertimeID(overtimeID uint, c *fiber.Ctx, tx *gorm.DB) (int64, error) {
+	var count int64
+	err := tx.WithContext(c.Context()).
+		Model(&entities.OvertimeAttachment{}).
+		Where("overtime_id = ?", overtimeID).
+		Count(&count).Error
+	return count, err
+}
+
+// FindByID mengambil lampiran milik record lembur overtimeID
+func (r *OvertimeAttachmentRepository) FindByID(overtimeID uint, ID uint, attachment *entities.OvertimeAttachment, c *fiber.Ctx, tx *gorm.DB) error {
+	err := tx.WithContext(c.Context()).
+		Where("id = ? AND overtime_id = ?", ID, overtimeID).
+		First(&attachment).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Delete menghapus metadata lampiran, file di blobstore dihapus oleh service
+func (r *OvertimeAttachmentRepository) Delete(ID uint, c *fiber.Ctx, tx *gorm.DB) error {
+	err := tx.WithContext(c.Context()).
+		Delete(&entities.OvertimeAttachment{}, ID).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/entities"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OvertimeFeedTokenRepository struct{}
+
+// FindByTelegramUserID mengambil token feed aktif milik telegram user
+func (r *OvertimeFeedTokenRepository) FindByTelegramUserID(telegramUserID uint, token *entities.OvertimeFeedToken, c *fiber.Ctx, tx *gorm.DB) error {
+	err := tx.WithContext(c.Context()).
+		Where("telegram_user_id = ?", telegramUserID).
+		First(&token).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindByTokenHash mengambil token feed beserta telegram user pemiliknya
+func (r *OvertimeFeedTokenRepository) FindByTokenHash(tokenHash string, token *entities.OvertimeFeedToken, c *fiber.Ctx, tx *gorm.DB) error {
+	err := tx.WithContext(c.Context()).
+		Preload("TelegramUser").
+		Where("token_hash = ?", tokenHash).
+		First(&token).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Upsert membuat token feed baru atau menggantinya untuk telegram user yang sama, token lama langsung tidak berlaku
+func (r *OvertimeFeedTokenRepository) Upsert(token *entities.OvertimeFeedToken, c *fiber.Ctx, tx *gorm.DB) error {
+	err := tx.WithContext(c.Context()).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "telegram_user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"token_hash", "created_by_user_id", "last_used_at", "updated_at"}),
+		}).
+		Create(&token).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeleteByTelegramUserID mencabut token feed, URL feed lama dibalas 404
+func (r *OvertimeFeedTokenRepository) DeleteByTelegramUserID(telegramUserID uint, c *fiber.Ctx, tx *gorm.DB) (int64, error) {
+	result := tx.WithContext(c.Context()).
+		Where("telegram_user_id = ?", telegramUserID).
+		Delete(&entities.OvertimeFeedToken{})
+	return result.RowsAffected, result.Error
+}
+
+// TouchLastUsed mencatat kapan feed terakhir diambil aplikasi kalender
+func (r *OvertimeFeedTokenRepository) TouchLastUsed(ID uint, usedAt time.Time, c *fiber.Ctx, tx *gorm.DB) error {
+	return tx.WithContext(c.Context()).
+		Model(&entities.OvertimeFeedToken{}).
+		Where("id = ?", ID).
+		UpdateColumn("last_used_at", usedAt).Error
+}
@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/entities"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OvertimeLimitRepository struct{}
+
+// FindByTelegramUserID mengambil batas lembur khusus milik telegram user
+func (r *OvertimeLimitRepository) FindByTelegramUserID(telegramUserID uint, limit *entities.OvertimeLimit, c *fiber.Ctx, tx *gorm.DB) error {
+	err := tx.WithContext(c.Context()).
+		Where("telegram_user_id = ?", telegramUserID).
+		First(&limit).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Upsert membuat batas lembur khusus baru atau menimpa yang sudah ada untuk telegram user yang sama
+func (r *OvertimeLimitRepository) Upsert(limit *entities.OvertimeLimit, c *fiber.Ctx, tx *gorm.DB) error {
+	err := tx.WithContext(c.Context()).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "telegram_user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"daily_hours", "weekly_hours", "monthly_hours", "mode", "updated_by_user_id", "updated_at"}),
+		}).
+		Create(&limit).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeleteByTelegramUserID menghapus batas lembur khusus, telegram user kembali memakai batas default
+func (r *OvertimeLimitRepository) DeleteByTelegramUserID(telegramUserID uint, c *fiber.Ctx, tx *gorm.DB) (int64, error) {
+	result := tx.WithContext(c.Context()).
+		Where("telegram_user_id = ?", telegramUserID).
+		Delete(&entities.OvertimeLimit{})
+	return result.RowsAffected, result.Error
+}
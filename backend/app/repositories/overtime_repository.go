@@ -1,11 +1,16 @@
 package repositories
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/entities"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/pagination"
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type OvertimeRepository struct{}
@@ -31,19 +36,64 @@ func (o *OvertimeRepository) CreateNewRecordOvertime(payload *entities.Overtime,
 	return nil
 }
 
-// GetAllRecordOvertimeByTelegramID retrieves all overtime records by telegram ID
-func (o *OvertimeRepository) GetAllRecordOvertimeByTelegramID(telegramID int64, overtimes *[]entities.Overtime, c *fiber.Ctx, tx *gorm.DB) error {
-	err := tx.WithContext(c.Context()).
-		Preload("User").
-		Preload("TelegramUser").
+// OvertimeListFilter narrows ListRecordOvertimeByTelegramID, zero values are ignored
+type OvertimeListFilter struct {
+	TelegramID   int64
+	StartDate    *time.Time
+	EndDate      *time.Time
+	CategoryName string // case-insensitive
+	CategoryID   *uint  // 0 = records without category
+	MinDuration  *float64
+	MaxDuration  *float64
+	Status       string
+}
+
+// overtimeSorts whitelists the sort keys of overtime lists
+var overtimeSorts = map[string]SortColumn[entities.Overtime]{
+	"id":         {"overtimes.id", func(o entities.Overtime) string { return strconv.FormatUint(uint64(o.ID), 10) }},
+	"date":       {"overtimes.date", func(o entities.Overtime) string { return o.Date.Format("2006-01-02") }},
+	"duration":   {"overtimes.duration", func(o entities.Overtime) string { return strconv.FormatFloat(o.Duration, 'f', -1, 64) }},
+	"created_at": {"overtimes.created_at", func(o entities.Overtime) string { return o.CreatedAt.Format(time.RFC3339Nano) }},
+	"updated_at": {"overtimes.updated_at", func(o entities.Overtime) string { return o.UpdatedAt.Format(time.RFC3339Nano) }},
+}
+
+// OvertimeSortKeys returns the sort keys accepted by ListRecordOvertimeByTelegramID
+func (o *OvertimeRepository) OvertimeSortKeys() []string {
+	return sortKeys(overtimeSorts)
+}
+
+// ListRecordOvertimeByTelegramID loads one page of overtime records of a telegram user and counts all matching records
+func (o *OvertimeRepository) ListRecordOvertimeByTelegramID(filter OvertimeListFilter, page pagination.Request, overtimes *[]entities.Overtime, total *int64, c *fiber.Ctx, tx *gorm.DB) (string, error) {
+	query := tx.WithContext(c.Context()).
+		Model(&entities.Overtime{}).
 		Joins("JOIN telegram_users ON telegram_users.id = overtimes.telegram_user_id").
-		Where("telegram_users.telegram_id = ?", telegramID).
-		Order("overtimes.id DESC").
-		Find(&overtimes).Error
-	if err != nil {
-		return err
+		Where("telegram_users.telegram_id = ?", filter.TelegramID)
+	if filter.StartDate != nil {
+		query = query.Where("overtimes.date >= ?", filter.StartDate.Format("2006-01-02"))
 	}
-	return nil
+	if filter.EndDate != nil {
+		query = query.Where("overtimes.date <= ?", filter.EndDate.Format("2006-01-02"))
+	}
+	if filter.CategoryName != "" {
+		query = query.Where("overtimes.category_id IN (SELECT id FROM categories WHERE lower(name) = lower(?))", filter.CategoryName)
+	}
+	if filter.CategoryID != nil {
+		if *filter.CategoryID == 0 {
+			query = query.Where("overtimes.category_id IS NULL")
+		} else {
+			query = query.Where("overtimes.category_id = ?", *filter.CategoryID)
+		}
+	}
+	if filter.MinDuration != nil {
+		query = query.Where("overtimes.duration >= ?", *filter.MinDuration)
+	}
+	if filter.MaxDuration != nil {
+		query = query.Where("overtimes.duration <= ?", *filter.MaxDuration)
+	}
+	if filter.Status != "" {
+		query = query.Where("overtimes.status = ?", filter.Status)
+	}
+	return findPage(query, overtimeSorts, "overtimes.id", func(o entities.Overtime) uint { return o.ID }, page, overtimes, total, "Category")
 }
 
 // GetRecordByDateByTelegramID retrieves overtime record by specific date and telegram ID
@@ -54,6 +104,7 @@ func (o *OvertimeRepository) GetRecordByDateByTelegramID(telegramID int64, date
 	err := tx.WithContext(c.Context()).
 		Preload("User").
 		Preload("TelegramUser").
+		Preload("Category").
 		Joins("JOIN telegram_users ON telegram_users.id = overtimes.telegram_user_id").
 		Where("telegram_users.telegram_id = ? AND DATE(overtimes.date) = ?", telegramID, dateStr).
 		Find(&overtime).Error
@@ -72,6 +123,7 @@ func (o *OvertimeRepository) GetRecordBetweenDateByTelegramId(telegramID int64,
 	err := tx.WithContext(c.Context()).
 		Preload("User").
 		Preload("TelegramUser").
+		Preload("Category").
 		Joins("JOIN telegram_users ON telegram_users.id = overtimes.telegram_user_id").
 		Where("telegram_users.telegram_id = ? AND DATE(overtimes.date) BETWEEN ? AND ?", telegramID, startDateStr, endDateStr).
 		Order("overtimes.id DESC").
@@ -82,11 +134,37 @@ func (o *OvertimeRepository) GetRecordBetweenDateByTelegramId(telegramID int64,
 	return nil
 }
 
+// ListForFeed retrieves the records of a telegram user dated since or later for the calendar feed, oldest first
+func (o *OvertimeRepository) ListForFeed(telegramUserID uint, since time.Time, overtimes *[]entities.Overtime, c *fiber.Ctx, tx *gorm.DB) error {
+	err := tx.WithContext(c.Context()).
+		Preload("Category").
+		Where("telegram_user_id = ? AND date >= ?", telegramUserID, since.Format("2006-01-02")).
+		Order("date ASC, time_start ASC").
+		Find(&overtimes).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
 // GetRecordByID retrieves overtime record by ID
 func (o *OvertimeRepository) GetRecordByID(id uint, overtime *entities.Overtime, c *fiber.Ctx, tx *gorm.DB) error {
 	err := tx.WithContext(c.Context()).
 		Preload("User").
 		Preload("TelegramUser").
+		Preload("Category").
+		Where("id = ?", id).
+		First(&overtime).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetRecordByIDForUpdate mengunci baris lembur (SELECT ... FOR UPDATE) agar perubahan status tidak balapan
+func (o *OvertimeRepository) GetRecordByIDForUpdate(id uint, overtime *entities.Overtime, c *fiber.Ctx, tx *gorm.DB) error {
+	err := tx.WithContext(c.Context()).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
 		Where("id = ?", id).
 		First(&overtime).Error
 	if err != nil {
@@ -95,6 +173,20 @@ func (o *OvertimeRepository) GetRecordByID(id uint, overtime *entities.Overtime,
 	return nil
 }
 
+// GetRecordsByStatus retrieves overtime records with a status, oldest date first
+func (o *OvertimeRepository) GetRecordsByStatus(status string, overtimes *[]entities.Overtime, c *fiber.Ctx, tx *gorm.DB) error {
+	err := tx.WithContext(c.Context()).
+		Preload("TelegramUser").
+		Preload("Category").
+		Where("status = ?", status).
+		Order("date ASC, id ASC").
+		Find(&overtimes).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
 // UpdateRecordOvertime updates an existing overtime record
 func (o *OvertimeRepository) UpdateRecordOvertime(id uint, payload *entities.Overtime, c *fiber.Ctx, tx *gorm.DB) error {
 	err := tx.WithContext(c.Context()).
@@ -107,6 +199,55 @@ func (o *OvertimeRepository) UpdateRecordOvertime(id uint, payload *entities.Ove
 	return nil
 }
 
+// FindOverlappingIDs mencari ID lembur milik telegram user yang sama yang rentang waktunya beririsan dengan [start, stop).
+// excludeID dipakai saat update agar record itu sendiri tidak dihitung (0 = tidak ada).
+func (o *OvertimeRepository) FindOverlappingIDs(telegramUserID uint, start time.Time, stop time.Time, excludeID uint, ids *[]uint, c *fiber.Ctx, tx *gorm.DB) error {
+	err := tx.WithContext(c.Context()).
+		Model(&entities.Overtime{}).
+		Where("telegram_user_id = ? AND id <> ?", telegramUserID, excludeID).
+		Where(entities.OvertimeRangeSQL+" && tstzrange(?, ?, '[)')", start, stop).
+		Order("id").
+		Pluck("id", ids).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// OvertimeUsageRow is the overtime of a telegram user in the day, week and month around a date
+type OvertimeUsageRow struct {
+	DayHours   float64
+	WeekHours  float64
+	MonthHours float64
+}
+
+// SumDurationsAround menjumlahkan durasi lembur telegram user pada hari, minggu (senin-minggu) dan bulan yang sama dengan date.
+// Lembur rejected tidak dihitung, excludeID dipakai saat update agar record itu sendiri tidak dihitung (0 = tidak ada).
+func (o *OvertimeRepository) SumDurationsAround(telegramUserID uint, date time.Time, weekStart time.Time, weekEnd time.Time, monthStart time.Time, monthEnd time.Time, excludeID uint, usage *OvertimeUsageRow, c *fiber.Ctx, tx *gorm.DB) error {
+	from, to := weekStart, weekEnd
+	if monthStart.Before(from) {
+		from = monthStart
+	}
+	if monthEnd.After(to) {
+		to = monthEnd
+	}
+
+	const layout = "2006-01-02"
+	err := tx.WithContext(c.Context()).
+		Model(&entities.Overtime{}).
+		Select("COALESCE(SUM(duration) FILTER (WHERE date = ?), 0) AS day_hours, "+
+			"COALESCE(SUM(duration) FILTER (WHERE date BETWEEN ? AND ?), 0) AS week_hours, "+
+			"COALESCE(SUM(duration) FILTER (WHERE date BETWEEN ? AND ?), 0) AS month_hours",
+			date.Format(layout), weekStart.Format(layout), weekEnd.Format(layout), monthStart.Format(layout), monthEnd.Format(layout)).
+		Where("telegram_user_id = ? AND id <> ? AND status <> ?", telegramUserID, excludeID, entities.OvertimeStatusRejected).
+		Where("date BETWEEN ? AND ?", from.Format(layout), to.Format(layout)).
+		Scan(usage).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
 // UpdateRecordOvertimePartial updates only specified fields of an overtime record
 func (o *OvertimeRepository) UpdateRecordOvertimePartial(id uint, updates map[string]interface{}, c *fiber.Ctx, tx *gorm.DB) error {
 	err := tx.WithContext(c.Context()).
@@ -119,11 +260,140 @@ func (o *OvertimeRepository) UpdateRecordOvertimePartial(id uint, updates map[st
 	return nil
 }
 
-// DeleteRecordOvertime deletes an overtime record
-func (o *OvertimeRepository) DeleteRecordOvertime(id uint, c *fiber.Ctx, tx *gorm.DB) error {
+// DeleteRecordOvertime moves an overtime record to the trash (soft delete), deletedByUserID is kept for the audit
+func (o *OvertimeRepository) DeleteRecordOvertime(id uint, deletedByUserID uint, c *fiber.Ctx, tx *gorm.DB) error {
 	err := tx.WithContext(c.Context()).
+		Model(&entities.Overtime{}).
 		Where("id = ?", id).
-		Delete(&entities.Overtime{}).Error
+		Updates(map[string]interface{}{
+			"deleted_at":         time.Now(),
+			"deleted_by_user_id": deletedByUserID,
+		}).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// trashSorts whitelists the sort keys of the trash, deleted_at is never null there
+var trashSorts = map[string]SortColumn[entities.Overtime]{
+	"id":         overtimeSorts["id"],
+	"date":       overtimeSorts["date"],
+	"deleted_at": {"overtimes.deleted_at", func(o entities.Overtime) string { return o.DeletedAt.Time.Format(time.RFC3339Nano) }},
+}
+
+// TrashSortKeys returns the sort keys accepted by ListTrashByTelegramID
+func (o *OvertimeRepository) TrashSortKeys() []string {
+	return sortKeys(trashSorts)
+}
+
+// ListTrashByTelegramID loads one page of the soft deleted overtime records of a telegram user
+func (o *OvertimeRepository) ListTrashByTelegramID(telegramID int64, page pagination.Request, overtimes *[]entities.Overtime, total *int64, c *fiber.Ctx, tx *gorm.DB) (string, error) {
+	query := tx.WithContext(c.Context()).
+		Unscoped().
+		Model(&entities.Overtime{}).
+		Joins("JOIN telegram_users ON telegram_users.id = overtimes.telegram_user_id").
+		Where("telegram_users.telegram_id = ? AND overtimes.deleted_at IS NOT NULL", telegramID)
+	return findPage(query, trashSorts, "overtimes.id", func(o entities.Overtime) uint { return o.ID }, page, overtimes, total, "Category")
+}
+
+// GetTrashedRecordByIDForUpdate mengunci baris lembur yang ada di trash, record yang belum dihapus tidak ditemukan
+func (o *OvertimeRepository) GetTrashedRecordByIDForUpdate(id uint, overtime *entities.Overtime, c *fiber.Ctx, tx *gorm.DB) error {
+	err := tx.WithContext(c.Context()).
+		Unscoped().
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		First(&overtime).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// RestoreRecordOvertime takes an overtime record out of the trash
+func (o *OvertimeRepository) RestoreRecordOvertime(id uint, c *fiber.Ctx, tx *gorm.DB) error {
+	err := tx.WithContext(c.Context()).
+		Unscoped().
+		Model(&entities.Overtime{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Updates(map[string]interface{}{
+			"deleted_at":         nil,
+			"deleted_by_user_id": nil,
+		}).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// OvertimeSummaryRow is one row of Summarize: a bucket of one telegram user, the subtotal of a telegram user
+// (AllBuckets) or the grand total (AllUsers and AllBuckets)
+type OvertimeSummaryRow struct {
+	AllUsers             bool
+	AllBuckets           bool
+	TelegramID           *int64
+	BucketStart          *string // YYYY-MM-DD, group by day/week/month
+	CategoryID           *uint   // group by category, null = tanpa kategori
+	CategoryName         *string
+	RecordsCount         int64
+	DaysCount            int64
+	TotalDuration        float64
+	TotalBreakDuration   float64
+	AverageDuration      float64
+	AverageBreakDuration float64
+	AverageDailyDuration float64
+}
+
+// overtimeSummaryBuckets maps group_by to the bucket columns. The date column is already the calendar day in TIMEZONE,
+// it is truncated as a timestamp without time zone so the session time zone of Postgres cannot shift it.
+var overtimeSummaryBuckets = map[string]struct {
+	groupColumns  string
+	selectColumns string
+}{
+	"day":      {"overtimes.date", "to_char(overtimes.date, 'YYYY-MM-DD') AS bucket_start"},
+	"week":     {"date_trunc('week', overtimes.date::timestamp)", "to_char(date_trunc('week', overtimes.date::timestamp), 'YYYY-MM-DD') AS bucket_start"},
+	"month":    {"date_trunc('month', overtimes.date::timestamp)", "to_char(date_trunc('month', overtimes.date::timestamp), 'YYYY-MM-DD') AS bucket_start"},
+	"category": {"overtimes.category_id, categories.name", "overtimes.category_id AS category_id, categories.name AS category_name"},
+}
+
+// firstColumn returns the first column of a comma separated column list, GROUPING() of it tells a subtotal row apart
+func firstColumn(columns string) string {
+	first, _, _ := strings.Cut(columns, ",")
+	return first
+}
+
+// Summarize menjumlahkan lembur per telegram user dan bucket (day, week, month atau category) antara dua tanggal,
+// sekaligus subtotal per telegram user dan total keseluruhan (GROUPING SETS). status kosong = semua status.
+func (o *OvertimeRepository) Summarize(telegramIDs []int64, startDate time.Time, endDate time.Time, groupBy string, status string, rows *[]OvertimeSummaryRow, c *fiber.Ctx, tx *gorm.DB) error {
+	bucket, ok := overtimeSummaryBuckets[groupBy]
+	if !ok {
+		return fmt.Errorf("unknown group_by %q", groupBy)
+	}
+
+	query := tx.WithContext(c.Context()).
+		Table("overtimes").
+		Select("GROUPING(telegram_users.telegram_id) = 1 AS all_users, "+
+			"GROUPING("+firstColumn(bucket.groupColumns)+") = 1 AS all_buckets, "+
+			"telegram_users.telegram_id AS telegram_id, "+bucket.selectColumns+", "+
+			"COUNT(*) AS records_count, "+
+			"COUNT(DISTINCT overtimes.date) AS days_count, "+
+			"COALESCE(SUM(overtimes.duration), 0) AS total_duration, "+
+			"COALESCE(SUM(overtimes.break_duration), 0) AS total_break_duration, "+
+			"COALESCE(AVG(overtimes.duration), 0) AS average_duration, "+
+			"COALESCE(AVG(overtimes.break_duration), 0) AS average_break_duration, "+
+			"COALESCE(SUM(overtimes.duration) / NULLIF(COUNT(DISTINCT overtimes.date), 0), 0) AS average_daily_duration").
+		Joins("JOIN telegram_users ON telegram_users.id = overtimes.telegram_user_id").
+		Joins("LEFT JOIN categories ON categories.id = overtimes.category_id").
+		Where("telegram_users.telegram_id IN ?", telegramIDs).
+		Where("overtimes.deleted_at IS NULL").
+		Where("overtimes.date BETWEEN ? AND ?", startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
+	if status != "" {
+		query = query.Where("overtimes.status = ?", status)
+	}
+	err := query.
+		Group("GROUPING SETS ((telegram_users.telegram_id, " + bucket.groupColumns + "), (telegram_users.telegram_id), ())").
+		Order("all_users DESC, all_buckets DESC, telegram_id, " + bucket.groupColumns).
+		Scan(rows).Error
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/entities"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type OvertimeStatusHistoryRepository struct{}
+
+// Create mencatat satu perubahan status lembur
+func (r *OvertimeStatusHistoryRepository) Create(history *entities.OvertimeStatusHistory, c *fiber.Ctx, tx *gorm.DB) error {
+	err := tx.WithContext(c.Context()).Create(&history).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindByOvertimeID mengambil riwayat status lembur dari yang paling lama
+func (r *OvertimeStatusHistoryRepository) FindByOvertimeID(overtimeID uint, histories *[]entities.OvertimeStatusHistory, c *fiber.Ctx, tx *gorm.DB) error {
+	err := tx.WithContext(c.Context()).
+		Where("overtime_id = ?", overtimeID).
+		Order("id ASC").
+		Find(&histories).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
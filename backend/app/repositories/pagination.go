@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/pagination"
+	"gorm.io/gorm"
+)
+
+// SortColumn is one whitelisted sort key of a list: the SQL column and how to read the cursor value from a row.
+// The column must not be nullable, rows with a NULL sort value would fall out of the keyset comparison.
+type SortColumn[T any] struct {
+	Column string
+	Value  func(T) string
+}
+
+// sortKeys returns the whitelisted sort keys, sorted
+func sortKeys[T any](sorts map[string]SortColumn[T]) []string {
+	keys := make([]string, 0, len(sorts))
+	for key := range sorts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// findPage loads one keyset page of query ordered by the sort column then idColumn, and counts all rows matching query.
+// preloads are only applied to the page query. It returns the cursor of the next page, empty on the last page.
+func findPage[T any](query *gorm.DB, sorts map[string]SortColumn[T], idColumn string, rowID func(T) uint, page pagination.Request, rows *[]T, total *int64, preloads ...string) (string, error) {
+	column, ok := sorts[page.Sort]
+	if !ok {
+		return "", fmt.Errorf("unknown sort %q", page.Sort)
+	}
+
+	if err := query.Session(&gorm.Session{}).Count(total).Error; err != nil {
+		return "", err
+	}
+
+	pageQuery := query.Session(&gorm.Session{})
+	for _, preload := range preloads {
+		pageQuery = pageQuery.Preload(preload)
+	}
+	if page.After != nil {
+		operator := ">"
+		if page.Order == pagination.OrderDesc {
+			operator = "<"
+		}
+		pageQuery = pageQuery.Where(fmt.Sprintf("(%s, %s) %s (?, ?)", column.Column, idColumn, operator), page.After.Value, page.After.ID)
+	}
+	// one extra row tells whether there is a next page
+	err := pageQuery.
+		Order(column.Column + " " + page.Order).
+		Order(idColumn + " " + page.Order).
+		Limit(page.Limit + 1).
+		Find(rows).Error
+	if err != nil {
+		return "", err
+	}
+
+	if len(*rows) <= page.Limit {
+		return "", nil
+	}
+	*rows = (*rows)[:page.Limit]
+	last := (*rows)[page.Limit-1]
+	return pagination.Cursor{Sort: page.Sort, Order: page.Order, Value: column.Value(last), ID: rowID(last)}.Encode(), nil
+}
+
+// containsPattern builds an ILIKE pattern matching search anywhere, % and _ in search are matched literally
+func containsPattern(search string) string {
+	escaper := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + escaper.Replace(search) + "%"
+}
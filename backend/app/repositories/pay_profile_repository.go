@@ -0,0 +1,35 @@
+package repositories
+
+import (
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/entities"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PayProfileRepository struct{}
+
+// FindByTelegramUserID mengambil profil gaji milik telegram user
+func (r *PayProfileRepository) FindByTelegramUserID(telegramUserID uint, profile *entities.PayProfile, c *fiber.Ctx, tx *gorm.DB) error {
+	err := tx.WithContext(c.Context()).
+		Where("telegram_user_id = ?", telegramUserID).
+		First(&profile).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Upsert membuat profil gaji baru atau menimpa profil yang sudah ada untuk telegram user yang sama
+func (r *PayProfileRepository) Upsert(profile *entities.PayProfile, c *fiber.Ctx, tx *gorm.DB) error {
+	err := tx.WithContext(c.Context()).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "telegram_user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"monthly_salary", "work_week_days", "hourly_divisor", "tiers", "updated_by_user_id", "updated_at"}),
+		}).
+		Create(&profile).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/entities"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TelegramLinkCodeRepository struct{}
+
+func (t *TelegramLinkCodeRepository) Create(linkCode *entities.TelegramLinkCode, c *fiber.Ctx, tx *gorm.DB) error {
+	err := tx.WithContext(c.Context()).Create(&linkCode).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindByCodeHashForUpdate mengunci baris kode sampai transaksi selesai supaya kode tidak bisa dipakai dua kali bersamaan
+func (t *TelegramLinkCodeRepository) FindByCodeHashForUpdate(codeHash string, linkCode *entities.TelegramLinkCode, c *fiber.Ctx, tx *gorm.DB) error {
+	err := tx.WithContext(c.Context()).Clauses(clause.Locking{Strength: "UPDATE"}).Where("code_hash = ?", codeHash).First(&linkCode).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// MarkUsed menandai kode terpakai, mengembalikan false jika kode sudah terpakai sebelumnya
+func (t *TelegramLinkCodeRepository) MarkUsed(ID uint, telegramID int64, usedAt time.Time, c *fiber.Ctx, tx *gorm.DB) (bool, error) {
+	result := tx.WithContext(c.Context()).Model(&entities.TelegramLinkCode{}).
+		Where("id = ? AND used_at IS NULL", ID).
+		Updates(map[string]interface{}{"used_at": usedAt, "used_by_telegram_id": telegramID})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
@@ -0,0 +1,26 @@
+package repositories
+
+import (
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/entities"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type TelegramPollingStateRepository struct{}
+
+func (t *TelegramPollingStateRepository) FindByBotID(botID int64, state *entities.TelegramPollingState, c *fiber.Ctx, tx *gorm.DB) error {
+	err := tx.WithContext(c.Context()).Where("bot_id = ?", botID).First(&state).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Save insert atau update offset untuk bot
+func (t *TelegramPollingStateRepository) Save(state *entities.TelegramPollingState, c *fiber.Ctx, tx *gorm.DB) error {
+	err := tx.WithContext(c.Context()).Save(&state).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
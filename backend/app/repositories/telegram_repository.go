@@ -1,7 +1,12 @@
 package repositories
 
 import (
+	"fmt"
+	"strconv"
+	"time"
+
 	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/entities"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/pagination"
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
 )
@@ -16,13 +21,28 @@ func (t *TelegramRepository) Create(payload *entities.TelegramUser, c *fiber.Ctx
 	return nil
 }
 
-func (t *TelegramRepository) FindByUserID(userID uint, telegramUser *[]entities.TelegramUser, c *fiber.Ctx, tx *gorm.DB) error {
-	// get all telegram user by user ID
-	err := tx.WithContext(c.Context()).Where("user_id = ?", userID).Find(&telegramUser).Error
-	if err != nil {
-		return err
+// telegramUserSorts whitelists the sort keys of the telegram user list
+var telegramUserSorts = map[string]SortColumn[entities.TelegramUser]{
+	"id":          {"telegram_users.id", func(t entities.TelegramUser) string { return strconv.FormatUint(uint64(t.ID), 10) }},
+	"telegram_id": {"telegram_users.telegram_id", func(t entities.TelegramUser) string { return strconv.FormatInt(t.TelegramID, 10) }},
+	"created_at":  {"telegram_users.created_at", func(t entities.TelegramUser) string { return t.CreatedAt.Format(time.RFC3339Nano) }},
+}
+
+// TelegramUserSortKeys returns the sort keys accepted by ListByUserID
+func (t *TelegramRepository) TelegramUserSortKeys() []string {
+	return sortKeys(telegramUserSorts)
+}
+
+// ListByUserID loads one page of telegram users of a user, search matches username, first or last name
+func (t *TelegramRepository) ListByUserID(userID uint, search string, page pagination.Request, telegramUsers *[]entities.TelegramUser, total *int64, c *fiber.Ctx, tx *gorm.DB) (string, error) {
+	query := tx.WithContext(c.Context()).
+		Model(&entities.TelegramUser{}).
+		Where("telegram_users.user_id = ?", userID)
+	if search != "" {
+		pattern := containsPattern(search)
+		query = query.Where("telegram_users.username ILIKE ? OR telegram_users.first_name ILIKE ? OR telegram_users.last_name ILIKE ?", pattern, pattern, pattern)
 	}
-	return nil
+	return findPage(query, telegramUserSorts, "telegram_users.id", func(t entities.TelegramUser) uint { return t.ID }, page, telegramUsers, total)
 }
 
 func (t *TelegramRepository) FindByID(ID uint, telegramUser *entities.TelegramUser, c *fiber.Ctx, tx *gorm.DB) error {
@@ -72,3 +92,53 @@ func (t *TelegramRepository) DeleteByTelegramID(telegramID int64, c *fiber.Ctx,
 	}
 	return nil
 }
+
+// FindReminderCandidates mencari user yang mengaktifkan pengingat, belum diingatkan sejak dayStart dan belum punya lembur pada date
+func (t *TelegramRepository) FindReminderCandidates(date time.Time, dayStart time.Time, telegramUsers *[]entities.TelegramUser, c *fiber.Ctx, tx *gorm.DB) error {
+	err := tx.WithContext(c.Context()).
+		Where("reminder_enabled = ?", true).
+		Where("last_reminder_at IS NULL OR last_reminder_at < ?", dayStart).
+		Where("NOT EXISTS (SELECT 1 FROM overtimes WHERE overtimes.telegram_user_id = telegram_users.id AND overtimes.date = ? AND overtimes.deleted_at IS NULL)", date.Format("2006-01-02")).
+		Find(&telegramUsers).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// UpdateFields mengubah kolom tertentu, dipakai untuk nilai false/kosong yang diabaikan oleh Updates(struct)
+func (t *TelegramRepository) UpdateFields(ID uint, fields map[string]interface{}, c *fiber.Ctx, tx *gorm.DB) error {
+	err := tx.WithContext(c.Context()).Model(&entities.TelegramUser{}).Where("id = ?", ID).Updates(fields).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindRecapCandidates mencari user yang mengaktifkan rekap dan belum menerima rekap sejak dayStart.
+// lastSentColumn adalah last_weekly_recap_at atau last_monthly_recap_at.
+func (t *TelegramRepository) FindRecapCandidates(lastSentColumn string, dayStart time.Time, telegramUsers *[]entities.TelegramUser, c *fiber.Ctx, tx *gorm.DB) error {
+	if lastSentColumn != "last_weekly_recap_at" && lastSentColumn != "last_monthly_recap_at" {
+		return fmt.Errorf("invalid recap column %q", lastSentColumn)
+	}
+	err := tx.WithContext(c.Context()).
+		Where("recap_enabled = ?", true).
+		Where(lastSentColumn+" IS NULL OR "+lastSentColumn+" < ?", dayStart).
+		Find(&telegramUsers).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindExistingTelegramIDs mengambil telegram_id dari daftar yang terdaftar di telegram_users
+func (t *TelegramRepository) FindExistingTelegramIDs(telegramIDs []int64, found *[]int64, c *fiber.Ctx, tx *gorm.DB) error {
+	err := tx.WithContext(c.Context()).
+		Model(&entities.TelegramUser{}).
+		Where("telegram_id IN ?", telegramIDs).
+		Pluck("telegram_id", found).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
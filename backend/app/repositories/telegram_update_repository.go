@@ -0,0 +1,31 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/entities"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type TelegramUpdateRepository struct{}
+
+// Create menyimpan update_id, mengembalikan duplicate key error jika update sudah pernah diproses
+func (t *TelegramUpdateRepository) Create(update *entities.TelegramUpdate, c *fiber.Ctx, tx *gorm.DB) error {
+	err := tx.WithContext(c.Context()).Create(&update).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeleteCreatedBefore menghapus update_id yang diproses sebelum cutoff, Telegram tidak mengirim ulang update lama
+func (t *TelegramUpdateRepository) DeleteCreatedBefore(cutoff time.Time, c *fiber.Ctx, tx *gorm.DB) (int64, error) {
+	result := tx.WithContext(c.Context()).
+		Where("created_at < ?", cutoff).
+		Delete(&entities.TelegramUpdate{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
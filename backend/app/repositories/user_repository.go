@@ -1,7 +1,11 @@
 package repositories
 
 import (
+	"strconv"
+	"time"
+
 	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/entities"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/pagination"
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
 )
@@ -41,11 +45,35 @@ func (r *UserRepository) CreateApiKey(apiKey *entities.APIKey, tx *gorm.DB, c *f
 	return nil
 }
 
-func (r *UserRepository) FindAll(users *[]entities.User, tx *gorm.DB) error {
-	if err := tx.Find(&users).Error; err != nil {
-		return err
+// UserListFilter narrows List, zero values are ignored
+type UserListFilter struct {
+	Role   string
+	Search string // username or email contains, case-insensitive
+}
+
+// userSorts whitelists the sort keys of the user list
+var userSorts = map[string]SortColumn[entities.User]{
+	"id":         {"users.id", func(u entities.User) string { return strconv.FormatUint(uint64(u.ID), 10) }},
+	"username":   {"users.username", func(u entities.User) string { return u.Username }},
+	"created_at": {"users.created_at", func(u entities.User) string { return u.CreatedAt.Format(time.RFC3339Nano) }},
+}
+
+// UserSortKeys returns the sort keys accepted by List
+func (r *UserRepository) UserSortKeys() []string {
+	return sortKeys(userSorts)
+}
+
+// List loads one page of users and counts all matching users
+func (r *UserRepository) List(filter UserListFilter, page pagination.Request, users *[]entities.User, total *int64, tx *gorm.DB, c *fiber.Ctx) (string, error) {
+	query := tx.WithContext(c.Context()).Model(&entities.User{})
+	if filter.Role != "" {
+		query = query.Where("users.role = ?", filter.Role)
 	}
-	return nil
+	if filter.Search != "" {
+		pattern := containsPattern(filter.Search)
+		query = query.Where("users.username ILIKE ? OR users.email ILIKE ?", pattern, pattern)
+	}
+	return findPage(query, userSorts, "users.id", func(u entities.User) uint { return u.ID }, page, users, total)
 }
 
 func (r *UserRepository) DeleteUserById(id uint, tx *gorm.DB) error {
@@ -54,3 +82,10 @@ func (r *UserRepository) DeleteUserById(id uint, tx *gorm.DB) error {
 	}
 	return nil
 }
+
+func (r *UserRepository) UpdateRole(id uint, role string, tx *gorm.DB, c *fiber.Ctx) error {
+	if err := tx.WithContext(c.Context()).Model(&entities.User{}).Where("id = ?", id).Update("role", role).Error; err != nil {
+		return err
+	}
+	return nil
+}
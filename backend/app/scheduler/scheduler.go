@@ -0,0 +1,195 @@
+// Package scheduler runs periodic background jobs inside the backend process.
+// Every run takes a Postgres advisory lock, so with several replicas only one of them executes a job at a time.
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"time"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/helpers"
+	"gorm.io/gorm"
+)
+
+// Job is a named periodic task. Next returns the next run time after now (in helpers.GetTimezone).
+type Job struct {
+	Name string
+	Next func(now time.Time) time.Time
+	Run  func(ctx context.Context) error
+}
+
+type Scheduler struct {
+	DB   *gorm.DB
+	jobs []Job
+}
+
+func New(db *gorm.DB) *Scheduler {
+	return &Scheduler{DB: db}
+}
+
+func (s *Scheduler) Add(jobs ...Job) {
+	s.jobs = append(s.jobs, jobs...)
+}
+
+// Start runs every job in its own goroutine until ctx is cancelled
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, job := range s.jobs {
+		go s.loop(ctx, job)
+	}
+}
+
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	for {
+		next := job.Next(helpers.NowWithTimezone())
+		helpers.Logger.Info().Str("type", "scheduler").Str("job", job.Name).Time("next_run", next).Msg("Scheduler job scheduled")
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		s.RunOnce(ctx, job)
+	}
+}
+
+// LockKey derives the advisory lock key from the job name
+func LockKey(name string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte("scheduler:" + name))
+	return int64(h.Sum64())
+}
+
+// RunOnce runs job if no other replica holds its advisory lock. It reports whether the job ran.
+func (s *Scheduler) RunOnce(ctx context.Context, job Job) bool {
+	started := time.Now()
+	logger := helpers.Logger.With().Str("type", "scheduler").Str("job", job.Name).Logger()
+
+	sqlDB, err := s.DB.DB()
+	if err != nil {
+		logger.Error().Err(err).Msg("Scheduler failed to get database")
+		return false
+	}
+	// advisory locks belong to a session, so lock and unlock have to use the same connection
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		logger.Error().Err(err).Msg("Scheduler failed to get connection")
+		return false
+	}
+	defer conn.Close()
+
+	key := LockKey(job.Name)
+	var locked bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&locked); err != nil {
+		logger.Error().Err(err).Msg("Scheduler failed to take advisory lock")
+		return false
+	}
+	if !locked {
+		logger.Info().Msg("Scheduler job skipped, running on another replica")
+		return false
+	}
+	defer func() {
+		if _, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", key); err != nil {
+			logger.Error().Err(err).Msg("Scheduler failed to release advisory lock")
+		}
+	}()
+
+	if err := runJob(ctx, job); err != nil {
+		logger.Error().Err(err).Dur("duration", time.Since(started)).Msg("Scheduler job failed")
+		return true
+	}
+	logger.Info().Dur("duration", time.Since(started)).Msg("Scheduler job finished")
+	return true
+}
+
+func runJob(ctx context.Context, job Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panic: %v", r)
+		}
+	}()
+	return job.Run(ctx)
+}
+
+// ParseClock parses "HH:MM" into hour and minute
+func ParseClock(value string) (int, int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+	return t.Hour(), t.Minute(), nil
+}
+
+// DailyAt returns a Next function that fires every day at hour:minute in the location of now
+func DailyAt(hour int, minute int) func(now time.Time) time.Time {
+	return func(now time.Time) time.Time {
+		next := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, now.Location())
+		if !next.After(now) {
+			next = next.AddDate(0, 0, 1)
+		}
+		return next
+	}
+}
+
+// DailyWindow fires at hour:minute and then every interval until the end of that day,
+// for jobs that have to pick up work deferred by an earlier run (e.g. quiet hours)
+func DailyWindow(hour int, minute int, interval time.Duration) func(now time.Time) time.Time {
+	return func(now time.Time) time.Time {
+		start := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, now.Location())
+		if now.Before(start) {
+			return start
+		}
+		next := start.Add((now.Sub(start)/interval + 1) * interval)
+		if next.Day() == start.Day() {
+			return next
+		}
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// WeeklyAt returns a Next function that fires every week on weekday at hour:minute
+func WeeklyAt(weekday time.Weekday, hour int, minute int) func(now time.Time) time.Time {
+	return func(now time.Time) time.Time {
+		days := (int(weekday) - int(now.Weekday()) + 7) % 7
+		next := time.Date(now.Year(), now.Month(), now.Day()+days, hour, minute, 0, 0, now.Location())
+		if !next.After(now) {
+			next = next.AddDate(0, 0, 7)
+		}
+		return next
+	}
+}
+
+// MonthlyAt returns a Next function that fires every month on day (1-28) at hour:minute
+func MonthlyAt(day int, hour int, minute int) func(now time.Time) time.Time {
+	return func(now time.Time) time.Time {
+		next := time.Date(now.Year(), now.Month(), day, hour, minute, 0, 0, now.Location())
+		if !next.After(now) {
+			next = time.Date(now.Year(), now.Month()+1, day, hour, minute, 0, 0, now.Location())
+		}
+		return next
+	}
+}
+
+// ParseWeekday parses an English or Indonesian day name
+func ParseWeekday(value string) (time.Weekday, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "sunday", "minggu":
+		return time.Sunday, nil
+	case "monday", "senin":
+		return time.Monday, nil
+	case "tuesday", "selasa":
+		return time.Tuesday, nil
+	case "wednesday", "rabu":
+		return time.Wednesday, nil
+	case "thursday", "kamis":
+		return time.Thursday, nil
+	case "friday", "jumat":
+		return time.Friday, nil
+	case "saturday", "sabtu":
+		return time.Saturday, nil
+	}
+	return 0, fmt.Errorf("invalid weekday %q", value)
+}
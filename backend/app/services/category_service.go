@@ -0,0 +1,253 @@
+package services
+
+import (
+	"strings"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/entities"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/payloads"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/database"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/helpers"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/repositories"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type CategoryService struct {
+	CategoryRepository repositories.CategoryRepository
+}
+
+// resolveCategory finds the category of an overtime by name (case-insensitive) or by ID, the name wins when both are sent.
+// Nothing sent returns a nil category. An unknown or inactive category writes a 422 listing the active ones and returns false.
+func resolveCategory(repo *repositories.CategoryRepository, method string, name string, id *uint, c *fiber.Ctx, tx *gorm.DB) (*entities.Category, bool, error) {
+	name = strings.TrimSpace(name)
+	if name == "" && (id == nil || *id == 0) {
+		return nil, true, nil
+	}
+
+	var category entities.Category
+	var err error
+	if name != "" {
+		err = repo.FindByName(name, &category, c, tx)
+	} else {
+		err = repo.FindByID(*id, &category, c, tx)
+	}
+	if err != nil && !helpers.IsNotFoundError(err) {
+		return nil, false, err
+	}
+	if err == nil && category.Active {
+		return &category, true, nil
+	}
+
+	var active []entities.Category
+	if err := repo.FindAll(true, &active, c, tx); err != nil {
+		return nil, false, err
+	}
+	available := make([]string, 0, len(active))
+	for _, category := range active {
+		available = append(available, category.Name)
+	}
+	helpers.MyLogger("info", "OvertimeManagement", method, "service", "unknown or inactive category", map[string]interface{}{
+		"category":    name,
+		"category_id": id,
+	}, c)
+	message := "Unknown category"
+	if err == nil {
+		message = "Category " + category.Name + " is inactive"
+	}
+	if len(available) > 0 {
+		message += ", available: " + strings.Join(available, ", ")
+	}
+	return nil, false, helpers.Response(c, fiber.StatusUnprocessableEntity, message, map[string]interface{}{
+		"available_categories": available,
+	})
+}
+
+// GetCategories lists categories by name, inactive ones only when includeInactive is set
+func (s *CategoryService) GetCategories(includeInactive bool, c *fiber.Ctx, tx *gorm.DB) error {
+	var categories []entities.Category
+	if err := s.CategoryRepository.FindAll(!includeInactive, &categories, c, tx); err != nil {
+		helpers.MyLogger("error", "CategoryManagement", "GetCategories", "service", "error getting categories", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return err
+	}
+	return helpers.Response(c, fiber.StatusOK, "Categories retrieved successfully", categories)
+}
+
+// GetCategoryByID retrieves a category by ID
+func (s *CategoryService) GetCategoryByID(id uint, c *fiber.Ctx, tx *gorm.DB) error {
+	var category entities.Category
+	if err := s.CategoryRepository.FindByID(id, &category, c, tx); err != nil {
+		if helpers.IsNotFoundError(err) {
+			return helpers.Response(c, fiber.StatusNotFound, "Category not found", nil)
+		}
+		return err
+	}
+	return helpers.Response(c, fiber.StatusOK, "Category retrieved successfully", category)
+}
+
+// CreateCategory adds a category, names are unique regardless of case
+func (s *CategoryService) CreateCategory(payload *payloads.CreateCategoryPayload, c *fiber.Ctx, tx *gorm.DB) error {
+	helpers.MyLogger("debug", "CategoryManagement", "CreateCategory", "service", "start create category", map[string]interface{}{
+		"name":    payload.Name,
+		"user_id": helpers.GetCurrentUserID(c),
+	}, c)
+
+	category := entities.Category{
+		Name:             strings.TrimSpace(payload.Name),
+		Color:            strings.ToUpper(payload.Color),
+		Active:           true,
+		PayMultiplier:    payload.PayMultiplier,
+		RequiresApproval: true,
+	}
+	if category.Name == "" {
+		tx.Rollback()
+		return helpers.ResponseErrorBadRequest(c, "Name is required", nil)
+	}
+	if payload.Active != nil {
+		category.Active = *payload.Active
+	}
+	if payload.RequiresApproval != nil {
+		category.RequiresApproval = *payload.RequiresApproval
+	}
+
+	// Active and RequiresApproval are written explicitly, gorm would replace a false zero value with the column default
+	if err := s.CategoryRepository.Create(&category, c, tx.Select("*").Omit("id")); err != nil {
+		tx.Rollback()
+		if helpers.IsDuplicateKeyError(err) {
+			helpers.MyLogger("info", "CategoryManagement", "CreateCategory", "service", "category name already exists", map[string]interface{}{
+				"name": payload.Name,
+			}, c)
+			return helpers.Response(c, fiber.StatusConflict, "Category with this name already exists", nil)
+		}
+		helpers.MyLogger("error", "CategoryManagement", "CreateCategory", "service", "error creating category", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	helpers.MyLogger("info", "CategoryManagement", "CreateCategory", "service", "category created successfully", map[string]interface{}{
+		"category_id": category.ID,
+	}, c)
+	return helpers.Response(c, fiber.StatusCreated, "Category created successfully", category)
+}
+
+// UpdateCategory changes the fields that are sent, renaming keeps every overtime linked to the category
+func (s *CategoryService) UpdateCategory(id uint, payload *payloads.UpdateCategoryPayload, c *fiber.Ctx, tx *gorm.DB) error {
+	helpers.MyLogger("debug", "CategoryManagement", "UpdateCategory", "service", "start update category", map[string]interface{}{
+		"category_id": id,
+	}, c)
+
+	var category entities.Category
+	if err := s.CategoryRepository.FindByID(id, &category, c, tx); err != nil {
+		tx.Rollback()
+		if helpers.IsNotFoundError(err) {
+			return helpers.Response(c, fiber.StatusNotFound, "Category not found", nil)
+		}
+		return err
+	}
+
+	updates := map[string]interface{}{}
+	if name := strings.TrimSpace(payload.Name); name != "" {
+		updates["name"] = name
+	}
+	if payload.Color != nil {
+		updates["color"] = strings.ToUpper(*payload.Color)
+	}
+	if payload.Active != nil {
+		updates["active"] = *payload.Active
+	}
+	if payload.PayMultiplier != nil {
+		updates["pay_multiplier"] = *payload.PayMultiplier
+	} else if payload.ClearPayMultiplier {
+		updates["pay_multiplier"] = nil
+	}
+	if payload.RequiresApproval != nil {
+		updates["requires_approval"] = *payload.RequiresApproval
+	}
+	if len(updates) == 0 {
+		tx.Rollback()
+		return helpers.ResponseErrorBadRequest(c, "No fields to update", nil)
+	}
+
+	if err := s.CategoryRepository.Update(id, updates, c, tx); err != nil {
+		tx.Rollback()
+		if helpers.IsDuplicateKeyError(err) {
+			return helpers.Response(c, fiber.StatusConflict, "Category with this name already exists", nil)
+		}
+		helpers.MyLogger("error", "CategoryManagement", "UpdateCategory", "service", "error updating category", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := s.CategoryRepository.FindByID(id, &category, c, database.ClientPostgres); err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, "Update successful but failed to retrieve updated data", nil)
+	}
+
+	helpers.MyLogger("info", "CategoryManagement", "UpdateCategory", "service", "category updated successfully", map[string]interface{}{
+		"category_id":    id,
+		"updated_fields": len(updates),
+	}, c)
+	return helpers.Response(c, fiber.StatusOK, "Category updated successfully", category)
+}
+
+// DeleteCategory removes a category that no overtime uses, categories in use can only be deactivated
+func (s *CategoryService) DeleteCategory(id uint, c *fiber.Ctx, tx *gorm.DB) error {
+	helpers.MyLogger("debug", "CategoryManagement", "DeleteCategory", "service", "start delete category", map[string]interface{}{
+		"category_id": id,
+	}, c)
+
+	var category entities.Category
+	if err := s.CategoryRepository.FindByID(id, &category, c, tx); err != nil {
+		tx.Rollback()
+		if helpers.IsNotFoundError(err) {
+			return helpers.Response(c, fiber.StatusNotFound, "Category not found", nil)
+		}
+		return err
+	}
+
+	used, err := s.CategoryRepository.CountOvertimes(id, c, tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if used > 0 {
+		tx.Rollback()
+		helpers.MyLogger("info", "CategoryManagement", "DeleteCategory", "service", "category is still used", map[string]interface{}{
+			"category_id":     id,
+			"overtimes_count": used,
+		}, c)
+		return helpers.Response(c, fiber.StatusConflict, "Category is used by overtime records, deactivate it instead (PUT with active false)", map[string]interface{}{
+			"overtimes_count": used,
+		})
+	}
+
+	if err := s.CategoryRepository.Delete(id, c, tx); err != nil {
+		tx.Rollback()
+		helpers.MyLogger("error", "CategoryManagement", "DeleteCategory", "service", "error deleting category", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	helpers.MyLogger("info", "CategoryManagement", "DeleteCategory", "service", "category deleted successfully", map[string]interface{}{
+		"category_id": id,
+	}, c)
+	return helpers.Response(c, fiber.StatusOK, "Category deleted successfully", nil)
+}
@@ -0,0 +1,337 @@
+package services
+
+import (
+	"io"
+	"time"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/entities"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/payloads"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/calendar"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/database"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/helpers"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/repositories"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type HolidayService struct {
+	HolidayRepository repositories.HolidayRepository
+}
+
+// localDate returns the calendar day of a date column value at midnight in TIMEZONE
+func localDate(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, helpers.GetTimezone())
+}
+
+// holidaySet maps YYYY-MM-DD to the holiday on that date
+type holidaySet map[string]entities.Holiday
+
+func (s holidaySet) has(date time.Time) bool {
+	_, ok := s[localDate(date).Format("2006-01-02")]
+	return ok
+}
+
+func loadHolidaySet(repo *repositories.HolidayRepository, startDate time.Time, endDate time.Time, c *fiber.Ctx, tx *gorm.DB) (holidaySet, error) {
+	var holidays []entities.Holiday
+	if err := repo.FindBetween(startDate, endDate, &holidays, c, tx); err != nil {
+		return nil, err
+	}
+	set := holidaySet{}
+	for _, holiday := range holidays {
+		set[localDate(holiday.Date).Format("2006-01-02")] = holiday
+	}
+	return set, nil
+}
+
+// TagCalendarFlags fills IsHoliday and IsWeekend of every overtime, dates are read in TIMEZONE
+func TagCalendarFlags(repo *repositories.HolidayRepository, overtimes []entities.Overtime, c *fiber.Ctx, tx *gorm.DB) error {
+	if len(overtimes) == 0 {
+		return nil
+	}
+	first, last := overtimes[0].Date, overtimes[0].Date
+	for _, overtime := range overtimes {
+		if overtime.Date.Before(first) {
+			first = overtime.Date
+		}
+		if overtime.Date.After(last) {
+			last = overtime.Date
+		}
+	}
+	holidays, err := loadHolidaySet(repo, first, last, c, tx)
+	if err != nil {
+		return err
+	}
+	for i := range overtimes {
+		overtimes[i].IsHoliday = holidays.has(overtimes[i].Date)
+		overtimes[i].IsWeekend = calendar.IsWeekend(localDate(overtimes[i].Date))
+	}
+	return nil
+}
+
+// GetHolidays lists holidays between two dates, oldest first
+func (h *HolidayService) GetHolidays(startDate time.Time, endDate time.Time, c *fiber.Ctx, tx *gorm.DB) error {
+	helpers.MyLogger("debug", "HolidayManagement", "GetHolidays", "service", "start get holidays", map[string]interface{}{
+		"start_date": startDate,
+		"end_date":   endDate,
+	}, c)
+
+	var holidays []entities.Holiday
+	if err := h.HolidayRepository.FindBetween(startDate, endDate, &holidays, c, tx); err != nil {
+		helpers.MyLogger("error", "HolidayManagement", "GetHolidays", "service", "error getting holidays", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return err
+	}
+
+	return helpers.Response(c, fiber.StatusOK, "Holidays retrieved successfully", map[string]interface{}{
+		"holidays":       holidays,
+		"holidays_count": len(holidays),
+		"period": map[string]interface{}{
+			"start_date": startDate.Format("2006-01-02"),
+			"end_date":   endDate.Format("2006-01-02"),
+		},
+	})
+}
+
+// GetHolidayByID retrieves a holiday by ID
+func (h *HolidayService) GetHolidayByID(id uint, c *fiber.Ctx, tx *gorm.DB) error {
+	var holiday entities.Holiday
+	if err := h.HolidayRepository.FindByID(id, &holiday, c, tx); err != nil {
+		if helpers.IsNotFoundError(err) {
+			return helpers.Response(c, fiber.StatusNotFound, "Holiday not found", nil)
+		}
+		return err
+	}
+	return helpers.Response(c, fiber.StatusOK, "Holiday retrieved successfully", holiday)
+}
+
+// CreateHoliday adds a holiday, a date can only have one holiday
+func (h *HolidayService) CreateHoliday(payload *payloads.CreateHolidayPayload, c *fiber.Ctx, tx *gorm.DB) error {
+	userID := helpers.GetCurrentUserID(c)
+	helpers.MyLogger("debug", "HolidayManagement", "CreateHoliday", "service", "start create holiday", map[string]interface{}{
+		"date":    payload.Date,
+		"user_id": userID,
+	}, c)
+
+	date, err := helpers.ParseDateWithTimezone(payload.Date)
+	if err != nil {
+		tx.Rollback()
+		return helpers.ResponseErrorBadRequest(c, "Invalid date format. Use YYYY-MM-DD", nil)
+	}
+
+	holiday := entities.Holiday{
+		Date:            date,
+		Name:            payload.Name,
+		Kind:            payload.Kind,
+		Source:          entities.HolidaySourceManual,
+		CreatedByUserID: userID,
+	}
+	if holiday.Kind == "" {
+		holiday.Kind = entities.HolidayKindNational
+		if calendar.IsCollectiveLeave(payload.Name) {
+			holiday.Kind = entities.HolidayKindCollectiveLeave
+		}
+	}
+
+	if err := h.HolidayRepository.Create(&holiday, c, tx); err != nil {
+		tx.Rollback()
+		if helpers.IsDuplicateKeyError(err) {
+			helpers.MyLogger("info", "HolidayManagement", "CreateHoliday", "service", "holiday date already exists", map[string]interface{}{
+				"date": payload.Date,
+			}, c)
+			return helpers.Response(c, fiber.StatusConflict, "Holiday on this date already exists", nil)
+		}
+		helpers.MyLogger("error", "HolidayManagement", "CreateHoliday", "service", "error creating holiday", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	helpers.MyLogger("info", "HolidayManagement", "CreateHoliday", "service", "holiday created successfully", map[string]interface{}{
+		"holiday_id": holiday.ID,
+		"date":       payload.Date,
+	}, c)
+	return helpers.Response(c, fiber.StatusCreated, "Holiday created successfully", holiday)
+}
+
+// UpdateHoliday changes the date, name or kind of a holiday
+func (h *HolidayService) UpdateHoliday(id uint, payload *payloads.UpdateHolidayPayload, c *fiber.Ctx, tx *gorm.DB) error {
+	helpers.MyLogger("debug", "HolidayManagement", "UpdateHoliday", "service", "start update holiday", map[string]interface{}{
+		"holiday_id": id,
+	}, c)
+
+	var holiday entities.Holiday
+	if err := h.HolidayRepository.FindByID(id, &holiday, c, tx); err != nil {
+		tx.Rollback()
+		if helpers.IsNotFoundError(err) {
+			return helpers.Response(c, fiber.StatusNotFound, "Holiday not found", nil)
+		}
+		return err
+	}
+
+	updates := map[string]interface{}{}
+	if payload.Date != "" {
+		date, err := helpers.ParseDateWithTimezone(payload.Date)
+		if err != nil {
+			tx.Rollback()
+			return helpers.ResponseErrorBadRequest(c, "Invalid date format. Use YYYY-MM-DD", nil)
+		}
+		updates["date"] = date
+	}
+	if payload.Name != "" {
+		updates["name"] = payload.Name
+	}
+	if payload.Kind != "" {
+		updates["kind"] = payload.Kind
+	}
+	if len(updates) == 0 {
+		tx.Rollback()
+		return helpers.ResponseErrorBadRequest(c, "No fields to update", nil)
+	}
+
+	if err := h.HolidayRepository.Update(id, updates, c, tx); err != nil {
+		tx.Rollback()
+		if helpers.IsDuplicateKeyError(err) {
+			return helpers.Response(c, fiber.StatusConflict, "Holiday on this date already exists", nil)
+		}
+		helpers.MyLogger("error", "HolidayManagement", "UpdateHoliday", "service", "error updating holiday", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := h.HolidayRepository.FindByID(id, &holiday, c, database.ClientPostgres); err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, "Update successful but failed to retrieve updated data", nil)
+	}
+
+	helpers.MyLogger("info", "HolidayManagement", "UpdateHoliday", "service", "holiday updated successfully", map[string]interface{}{
+		"holiday_id":     id,
+		"updated_fields": len(updates),
+	}, c)
+	return helpers.Response(c, fiber.StatusOK, "Holiday updated successfully", holiday)
+}
+
+// DeleteHoliday removes a holiday
+func (h *HolidayService) DeleteHoliday(id uint, c *fiber.Ctx, tx *gorm.DB) error {
+	helpers.MyLogger("debug", "HolidayManagement", "DeleteHoliday", "service", "start delete holiday", map[string]interface{}{
+		"holiday_id": id,
+	}, c)
+
+	var holiday entities.Holiday
+	if err := h.HolidayRepository.FindByID(id, &holiday, c, tx); err != nil {
+		tx.Rollback()
+		if helpers.IsNotFoundError(err) {
+			return helpers.Response(c, fiber.StatusNotFound, "Holiday not found", nil)
+		}
+		return err
+	}
+
+	if err := h.HolidayRepository.Delete(id, c, tx); err != nil {
+		tx.Rollback()
+		helpers.MyLogger("error", "HolidayManagement", "DeleteHoliday", "service", "error deleting holiday", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	helpers.MyLogger("info", "HolidayManagement", "DeleteHoliday", "service", "holiday deleted successfully", map[string]interface{}{
+		"holiday_id": id,
+	}, c)
+	return helpers.Response(c, fiber.StatusOK, "Holiday deleted successfully", nil)
+}
+
+// ImportHolidays reads an iCalendar or CSV file and upserts every date, existing dates get the imported name and kind
+func (h *HolidayService) ImportHolidays(format string, file io.Reader, c *fiber.Ctx, tx *gorm.DB) error {
+	userID := helpers.GetCurrentUserID(c)
+	helpers.MyLogger("debug", "HolidayManagement", "ImportHolidays", "service", "start import holidays", map[string]interface{}{
+		"format":  format,
+		"user_id": userID,
+	}, c)
+
+	var entries []calendar.Entry
+	var skipped []calendar.LineError
+	var err error
+	source := entities.HolidaySourceICS
+	if format == "csv" {
+		source = entities.HolidaySourceCSV
+		entries, skipped, err = calendar.ParseCSV(file, helpers.GetTimezone())
+	} else {
+		entries, skipped, err = calendar.ParseICS(file, helpers.GetTimezone())
+	}
+	if err != nil {
+		tx.Rollback()
+		helpers.MyLogger("info", "HolidayManagement", "ImportHolidays", "service", "invalid holiday file", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return helpers.Response(c, fiber.StatusUnprocessableEntity, "Invalid "+format+" file: "+err.Error(), nil)
+	}
+
+	// a date can only hold one holiday, the first entry in the file wins
+	seen := map[string]bool{}
+	holidays := make([]entities.Holiday, 0, len(entries))
+	duplicates := []string{}
+	for _, entry := range entries {
+		key := entry.Date.Format("2006-01-02")
+		if seen[key] {
+			duplicates = append(duplicates, key)
+			continue
+		}
+		seen[key] = true
+		kind := entities.HolidayKindNational
+		if entry.CollectiveLeave {
+			kind = entities.HolidayKindCollectiveLeave
+		}
+		holidays = append(holidays, entities.Holiday{
+			Date:            entry.Date,
+			Name:            entry.Name,
+			Kind:            kind,
+			Source:          source,
+			CreatedByUserID: userID,
+		})
+	}
+
+	if len(holidays) > 0 {
+		if err := h.HolidayRepository.UpsertMany(holidays, c, tx); err != nil {
+			tx.Rollback()
+			helpers.MyLogger("error", "HolidayManagement", "ImportHolidays", "service", "error saving holidays", map[string]interface{}{
+				"error": err.Error(),
+			}, c)
+			return err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if skipped == nil {
+		skipped = []calendar.LineError{}
+	}
+	helpers.MyLogger("info", "HolidayManagement", "ImportHolidays", "service", "holidays imported successfully", map[string]interface{}{
+		"format":   format,
+		"imported": len(holidays),
+		"skipped":  len(skipped),
+	}, c)
+	return helpers.Response(c, fiber.StatusOK, "Holidays imported successfully", payloads.ImportHolidaysResponse{
+		Format:         format,
+		Imported:       len(holidays),
+		DuplicateDates: duplicates,
+		Skipped:        skipped,
+	})
+}
@@ -0,0 +1,241 @@
+package services
+
+import (
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/entities"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/payloads"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/database"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/helpers"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// overtimeTransition is one step of the approval workflow.
+// OwnerOnly limits it to the owner of the record or an admin, NotOwner forbids it on the approver's own records.
+type overtimeTransition struct {
+	Method    string
+	From      []string
+	To        string
+	OwnerOnly bool
+	NotOwner  bool
+}
+
+var (
+	transitionSubmit  = overtimeTransition{Method: "SubmitRecordOvertime", From: []string{entities.OvertimeStatusDraft, entities.OvertimeStatusRejected}, To: entities.OvertimeStatusSubmitted, OwnerOnly: true}
+	transitionApprove = overtimeTransition{Method: "ApproveRecordOvertime", From: []string{entities.OvertimeStatusSubmitted}, To: entities.OvertimeStatusApproved, NotOwner: true}
+	transitionReject  = overtimeTransition{Method: "RejectRecordOvertime", From: []string{entities.OvertimeStatusSubmitted}, To: entities.OvertimeStatusRejected, NotOwner: true}
+	transitionReopen  = overtimeTransition{Method: "ReopenRecordOvertime", From: []string{entities.OvertimeStatusApproved, entities.OvertimeStatusRejected}, To: entities.OvertimeStatusDraft}
+)
+
+func (t overtimeTransition) allowedFrom(status string) bool {
+	for _, from := range t.From {
+		if from == status {
+			return true
+		}
+	}
+	return false
+}
+
+func lockedResponse(c *fiber.Ctx) error {
+	return helpers.Response(c, fiber.StatusConflict, "Approved overtime record cannot be modified, reopen it first", nil)
+}
+
+// SubmitRecordOvertime submits a draft or rejected overtime record for approval
+func (o *OvertimeService) SubmitRecordOvertime(id uint, payload *payloads.ChangeOvertimeStatusPayload, c *fiber.Ctx, tx *gorm.DB) error {
+	return o.changeStatus(id, transitionSubmit, payload.Reason, c, tx)
+}
+
+// ApproveRecordOvertime approves a submitted overtime record
+func (o *OvertimeService) ApproveRecordOvertime(id uint, payload *payloads.ChangeOvertimeStatusPayload, c *fiber.Ctx, tx *gorm.DB) error {
+	return o.changeStatus(id, transitionApprove, payload.Reason, c, tx)
+}
+
+// RejectRecordOvertime rejects a submitted overtime record, the owner can fix and submit it again
+func (o *OvertimeService) RejectRecordOvertime(id uint, payload *payloads.ChangeOvertimeStatusPayload, c *fiber.Ctx, tx *gorm.DB) error {
+	return o.changeStatus(id, transitionReject, payload.Reason, c, tx)
+}
+
+// ReopenRecordOvertime moves an approved or rejected overtime record back to draft so it can be edited
+func (o *OvertimeService) ReopenRecordOvertime(id uint, payload *payloads.ChangeOvertimeStatusPayload, c *fiber.Ctx, tx *gorm.DB) error {
+	return o.changeStatus(id, transitionReopen, payload.Reason, c, tx)
+}
+
+func (o *OvertimeService) changeStatus(id uint, transition overtimeTransition, reason string, c *fiber.Ctx, tx *gorm.DB) error {
+	userID := helpers.GetCurrentUserID(c)
+	helpers.MyLogger("debug", "OvertimeManagement", transition.Method, "service", "start change overtime status", map[string]interface{}{
+		"overtime_id": id,
+		"user_id":     userID,
+		"to_status":   transition.To,
+	}, c)
+
+	var overtime entities.Overtime
+	err := o.OvertimeRepository.GetRecordByIDForUpdate(id, &overtime, c, tx)
+	if err != nil {
+		if helpers.IsNotFoundError(err) {
+			helpers.MyLogger("info", "OvertimeManagement", transition.Method, "service", "overtime record not found", map[string]interface{}{
+				"overtime_id": id,
+			}, c)
+			return helpers.Response(c, fiber.StatusNotFound, "Overtime record not found", nil)
+		}
+		helpers.MyLogger("error", "OvertimeManagement", transition.Method, "service", "error finding overtime record", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return err
+	}
+
+	if transition.OwnerOnly || transition.NotOwner {
+		var owner entities.TelegramUser
+		if err := o.TelegramRepository.FindByID(overtime.TelegramUserID, &owner, c, tx); err != nil && !helpers.IsNotFoundError(err) {
+			helpers.MyLogger("error", "OvertimeManagement", transition.Method, "service", "error finding record owner", map[string]interface{}{
+				"error": err.Error(),
+			}, c)
+			return err
+		}
+		user, _ := c.Locals("user").(entities.User)
+		isOwner := owner.UserID != 0 && owner.UserID == userID
+		var message string
+		switch {
+		case transition.OwnerOnly && !isOwner && user.Role != entities.RoleAdmin:
+			message = "Only the owner of the overtime record or an admin can submit it"
+		case transition.NotOwner && isOwner:
+			message = "You cannot approve or reject your own overtime record"
+		}
+		if message != "" {
+			helpers.MyLogger("info", "OvertimeManagement", transition.Method, "service", "status change forbidden for this user", map[string]interface{}{
+				"overtime_id": id,
+				"user_id":     userID,
+				"owner_id":    owner.UserID,
+			}, c)
+			tx.Rollback()
+			return helpers.Response(c, fiber.StatusForbidden, message, nil)
+		}
+	}
+
+	if !transition.allowedFrom(overtime.Status) {
+		helpers.MyLogger("info", "OvertimeManagement", transition.Method, "service", "status transition not allowed", map[string]interface{}{
+			"overtime_id": id,
+			"from_status": overtime.Status,
+			"to_status":   transition.To,
+		}, c)
+		tx.Rollback()
+		return helpers.Response(c, fiber.StatusConflict, "Overtime record with status "+overtime.Status+" cannot be moved to "+transition.To, map[string]interface{}{
+			"status":       overtime.Status,
+			"allowed_from": transition.From,
+		})
+	}
+
+	err = o.OvertimeRepository.UpdateRecordOvertimePartial(id, map[string]interface{}{"status": transition.To}, c, tx)
+	if err != nil {
+		helpers.MyLogger("error", "OvertimeManagement", transition.Method, "service", "error updating overtime status", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		tx.Rollback()
+		return err
+	}
+
+	history := entities.OvertimeStatusHistory{
+		OvertimeID:      id,
+		FromStatus:      overtime.Status,
+		ToStatus:        transition.To,
+		Reason:          reason,
+		ChangedByUserID: userID,
+	}
+	if err := o.OvertimeStatusHistoryRepository.Create(&history, c, tx); err != nil {
+		helpers.MyLogger("error", "OvertimeManagement", transition.Method, "service", "error creating status history", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		helpers.MyLogger("error", "OvertimeManagement", transition.Method, "service", "error committing transaction", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		tx.Rollback()
+		return err
+	}
+
+	var updatedRecord entities.Overtime
+	if err := o.OvertimeRepository.GetRecordByID(id, &updatedRecord, c, database.ClientPostgres); err != nil {
+		helpers.MyLogger("error", "OvertimeManagement", transition.Method, "service", "error getting updated record", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return helpers.Response(c, fiber.StatusInternalServerError, "Status changed but failed to retrieve updated data", nil)
+	}
+
+	if err := o.tagCalendarFlagsOne(transition.Method, &updatedRecord, c, database.ClientPostgres); err != nil {
+		return err
+	}
+
+	helpers.MyLogger("info", "OvertimeManagement", transition.Method, "service", "overtime status changed successfully", map[string]interface{}{
+		"overtime_id": id,
+		"from_status": history.FromStatus,
+		"to_status":   history.ToStatus,
+		"changed_by":  userID,
+	}, c)
+	return helpers.Response(c, fiber.StatusOK, "Overtime status changed to "+transition.To, payloads.OvertimeStatusChangeResponse{
+		Overtime: updatedRecord,
+		History:  history,
+	})
+}
+
+// GetPendingApprovals retrieves submitted overtime records waiting for an approver, oldest first
+func (o *OvertimeService) GetPendingApprovals(c *fiber.Ctx, tx *gorm.DB) error {
+	helpers.MyLogger("debug", "OvertimeManagement", "GetPendingApprovals", "service", "start get pending approvals", nil, c)
+
+	var overtimes []entities.Overtime
+	if err := o.OvertimeRepository.GetRecordsByStatus(entities.OvertimeStatusSubmitted, &overtimes, c, tx); err != nil {
+		helpers.MyLogger("error", "OvertimeManagement", "GetPendingApprovals", "service", "error getting pending approvals", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return err
+	}
+
+	if err := o.tagCalendarFlags("GetPendingApprovals", overtimes, c, tx); err != nil {
+		return err
+	}
+
+	records := make([]payloads.PendingOvertimeResponse, 0, len(overtimes))
+	for _, overtime := range overtimes {
+		records = append(records, payloads.PendingOvertimeResponse{
+			Overtime:     overtime,
+			TelegramUser: overtime.TelegramUser,
+		})
+	}
+
+	helpers.MyLogger("info", "OvertimeManagement", "GetPendingApprovals", "service", "pending approvals retrieved successfully", map[string]interface{}{
+		"records_count": len(records),
+	}, c)
+	return helpers.Response(c, fiber.StatusOK, "Pending approvals retrieved successfully", map[string]interface{}{
+		"records":       records,
+		"records_count": len(records),
+	})
+}
+
+// GetStatusHistory retrieves the status changes of an overtime record
+func (o *OvertimeService) GetStatusHistory(id uint, c *fiber.Ctx, tx *gorm.DB) error {
+	helpers.MyLogger("debug", "OvertimeManagement", "GetStatusHistory", "service", "start get overtime status history", map[string]interface{}{
+		"overtime_id": id,
+	}, c)
+
+	var overtime entities.Overtime
+	if err := o.OvertimeRepository.GetRecordByID(id, &overtime, c, tx); err != nil {
+		if helpers.IsNotFoundError(err) {
+			return helpers.Response(c, fiber.StatusNotFound, "Overtime record not found", nil)
+		}
+		return err
+	}
+
+	var histories []entities.OvertimeStatusHistory
+	if err := o.OvertimeStatusHistoryRepository.FindByOvertimeID(id, &histories, c, tx); err != nil {
+		helpers.MyLogger("error", "OvertimeManagement", "GetStatusHistory", "service", "error getting status history", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return err
+	}
+
+	return helpers.Response(c, fiber.StatusOK, "Overtime status history retrieved successfully", map[string]interface{}{
+		"status":  overtime.Status,
+		"history": histories,
+	})
+}
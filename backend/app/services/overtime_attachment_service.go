@@ -0,0 +1,382 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/entities"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/payloads"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/blobstore"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/helpers"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/repositories"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// MaxAttachmentsPerUpload is how many files one multipart request may carry
+const MaxAttachmentsPerUpload = 5
+
+type OvertimeAttachmentService struct {
+	OvertimeAttachmentRepository repositories.OvertimeAttachmentRepository
+	OvertimeRepository           repositories.OvertimeRepository
+}
+
+// AttachmentMaxSize reads ATTACHMENT_MAX_SIZE_MB, the largest file in bytes (default 10 MB)
+func AttachmentMaxSize() int64 {
+	megabytes, err := strconv.ParseInt(helpers.GetEnv("ATTACHMENT_MAX_SIZE_MB", "10"), 10, 64)
+	if err != nil || megabytes <= 0 {
+		megabytes = 10
+	}
+	return megabytes << 20
+}
+
+// AttachmentBodyLimit is the request body limit needed for MaxAttachmentsPerUpload files of AttachmentMaxSize,
+// never lower than the Fiber default
+func AttachmentBodyLimit() int {
+	limit := int(AttachmentMaxSize())*MaxAttachmentsPerUpload + 1<<20
+	if limit < fiber.DefaultBodyLimit {
+		return fiber.DefaultBodyLimit
+	}
+	return limit
+}
+
+// attachmentAllowedTypes reads ATTACHMENT_ALLOWED_TYPES, comma separated MIME types
+func attachmentAllowedTypes() []string {
+	var types []string
+	for _, contentType := range strings.Split(helpers.GetEnv("ATTACHMENT_ALLOWED_TYPES", "image/jpeg,image/png,image/webp,application/pdf"), ",") {
+		if contentType = strings.ToLower(strings.TrimSpace(contentType)); contentType != "" {
+			types = append(types, contentType)
+		}
+	}
+	return types
+}
+
+// attachmentMaxPerRecord reads ATTACHMENT_MAX_PER_RECORD (default 10)
+func attachmentMaxPerRecord() int64 {
+	count, err := strconv.ParseInt(helpers.GetEnv("ATTACHMENT_MAX_PER_RECORD", "10"), 10, 64)
+	if err != nil || count <= 0 {
+		count = 10
+	}
+	return count
+}
+
+// sniffContentType detects the MIME type from the first bytes of the file, without parameters
+func sniffContentType(data []byte) string {
+	contentType, _, err := mime.ParseMediaType(http.DetectContentType(data))
+	if err != nil {
+		return "application/octet-stream"
+	}
+	return contentType
+}
+
+// attachmentExtension is the extension of the stored object, taken from the sniffed type and not from the client file name
+func attachmentExtension(contentType string) string {
+	switch contentType {
+	case "image/jpeg":
+		return ".jpg"
+	case "application/pdf":
+		return ".pdf"
+	}
+	if extensions, err := mime.ExtensionsByType(contentType); err == nil && len(extensions) > 0 {
+		return extensions[0]
+	}
+	return ""
+}
+
+// cleanAttachmentName keeps the base name of the client file without control characters, used in Content-Disposition
+func cleanAttachmentName(name string, index int, contentType string) string {
+	name = filepath.Base(strings.ReplaceAll(name, `\`, "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == '"' {
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(name))
+	if name == "" || name == "." || name == "/" {
+		name = fmt.Sprintf("lampiran-%d%s", index+1, attachmentExtension(contentType))
+	}
+	if len(name) > 255 {
+		extension := filepath.Ext(name)
+		if len(extension) > 16 {
+			extension = ""
+		}
+		name = strings.ToValidUTF8(name[:255-len(extension)], "") + extension
+	}
+	return name
+}
+
+func newAttachmentKey(overtimeID uint, contentType string) (string, error) {
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("overtime/%d/%s%s", overtimeID, hex.EncodeToString(raw), attachmentExtension(contentType)), nil
+}
+
+// findAttachmentOvertime loads the record and checks access: the owner and admins can change attachments,
+// approvers can only read them. Other users get the same 404 as a missing record, found false means the response is already written.
+func (s *OvertimeAttachmentService) findAttachmentOvertime(method string, overtimeID uint, write bool, c *fiber.Ctx, tx *gorm.DB) (entities.Overtime, bool, error) {
+	var overtime entities.Overtime
+	if err := s.OvertimeRepository.GetRecordByID(overtimeID, &overtime, c, tx); err != nil {
+		if !helpers.IsNotFoundError(err) {
+			return overtime, false, err
+		}
+		helpers.MyLogger("info", "OvertimeAttachment", method, "service", "overtime record not found", map[string]interface{}{
+			"overtime_id": overtimeID,
+		}, c)
+		return overtime, false, helpers.Response(c, fiber.StatusNotFound, "Overtime record not found", nil)
+	}
+
+	user, _ := c.Locals("user").(entities.User)
+	isOwner := overtime.TelegramUser.UserID == helpers.GetCurrentUserID(c)
+	switch {
+	case isOwner || user.Role == entities.RoleAdmin:
+		return overtime, true, nil
+	case user.Role == entities.RoleApprover && !write:
+		return overtime, true, nil
+	case user.Role == entities.RoleApprover:
+		return overtime, false, helpers.Response(c, fiber.StatusForbidden, "Only the owner of the overtime record can change its attachments", nil)
+	}
+	helpers.MyLogger("info", "OvertimeAttachment", method, "service", "overtime record of another user", map[string]interface{}{
+		"overtime_id": overtimeID,
+		"user_id":     helpers.GetCurrentUserID(c),
+	}, c)
+	return overtime, false, helpers.Response(c, fiber.StatusNotFound, "Overtime record not found", nil)
+}
+
+// UploadAttachments checks every file against the size and MIME type limits, stores them in blobstore
+// and saves their metadata. Nothing is saved when one file is rejected.
+func (s *OvertimeAttachmentService) UploadAttachments(overtimeID uint, files []payloads.AttachmentUpload, source string, c *fiber.Ctx, tx *gorm.DB) error {
+	userID := helpers.GetCurrentUserID(c)
+	helpers.MyLogger("debug", "OvertimeAttachment", "UploadAttachments", "service", "start upload attachments", map[string]interface{}{
+		"overtime_id": overtimeID,
+		"files_count": len(files),
+		"source":      source,
+		"user_id":     userID,
+	}, c)
+
+	if _, ok, err := s.findAttachmentOvertime("UploadAttachments", overtimeID, true, c, tx); !ok || err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	count, err := s.OvertimeAttachmentRepository.CountByOvertimeID(overtimeID, c, tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if maxPerRecord := attachmentMaxPerRecord(); count+int64(len(files)) > maxPerRecord {
+		tx.Rollback()
+		return helpers.Response(c, fiber.StatusUnprocessableEntity, fmt.Sprintf("An overtime record can have at most %d attachments, it already has %d", maxPerRecord, count), nil)
+	}
+
+	maxSize := AttachmentMaxSize()
+	allowedTypes := attachmentAllowedTypes()
+	contentTypes := make([]string, len(files))
+	for i, file := range files {
+		if len(file.Data) == 0 {
+			tx.Rollback()
+			return helpers.Response(c, fiber.StatusBadRequest, "File "+file.FileName+" is empty", nil)
+		}
+		if int64(len(file.Data)) > maxSize {
+			tx.Rollback()
+			return helpers.Response(c, fiber.StatusRequestEntityTooLarge, fmt.Sprintf("File %s is larger than %d MB", file.FileName, maxSize>>20), nil)
+		}
+		contentTypes[i] = sniffContentType(file.Data)
+		if !slices.Contains(allowedTypes, contentTypes[i]) {
+			helpers.MyLogger("info", "OvertimeAttachment", "UploadAttachments", "service", "file type not allowed", map[string]interface{}{
+				"file_name":    file.FileName,
+				"content_type": contentTypes[i],
+			}, c)
+			tx.Rollback()
+			return helpers.Response(c, fiber.StatusUnsupportedMediaType, fmt.Sprintf("File %s is %s, allowed: %s", file.FileName, contentTypes[i], strings.Join(allowedTypes, ", ")), nil)
+		}
+	}
+
+	// file yang sudah tersimpan dihapus lagi kalau transaksi gagal
+	var storedKeys []string
+	cleanup := func() {
+		for _, key := range storedKeys {
+			if err := blobstore.Client.Delete(context.Background(), key); err != nil {
+				helpers.MyLogger("error", "OvertimeAttachment", "UploadAttachments", "service", "error removing stored file", map[string]interface{}{
+					"storage_key": key,
+					"error":       err.Error(),
+				}, c)
+			}
+		}
+	}
+
+	attachments := make([]entities.OvertimeAttachment, 0, len(files))
+	for i, file := range files {
+		key, err := newAttachmentKey(overtimeID, contentTypes[i])
+		if err != nil {
+			tx.Rollback()
+			cleanup()
+			return err
+		}
+		if err := blobstore.Client.Put(c.Context(), key, bytes.NewReader(file.Data), int64(len(file.Data)), contentTypes[i]); err != nil {
+			helpers.MyLogger("error", "OvertimeAttachment", "UploadAttachments", "service", "error storing file", map[string]interface{}{
+				"error": err.Error(),
+			}, c)
+			tx.Rollback()
+			cleanup()
+			return err
+		}
+		storedKeys = append(storedKeys, key)
+
+		sum := sha256.Sum256(file.Data)
+		attachment := entities.OvertimeAttachment{
+			OvertimeID:       overtimeID,
+			FileName:         cleanAttachmentName(file.FileName, int(count)+i, contentTypes[i]),
+			ContentType:      contentTypes[i],
+			Size:             int64(len(file.Data)),
+			SHA256:           hex.EncodeToString(sum[:]),
+			StorageKey:       key,
+			Source:           source,
+			UploadedByUserID: userID,
+		}
+		if err := s.OvertimeAttachmentRepository.Create(&attachment, c, tx); err != nil {
+			helpers.MyLogger("error", "OvertimeAttachment", "UploadAttachments", "service", "error creating attachment", map[string]interface{}{
+				"error": err.Error(),
+			}, c)
+			tx.Rollback()
+			cleanup()
+			return err
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		helpers.MyLogger("error", "OvertimeAttachment", "UploadAttachments", "service", "error committing transaction", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		cleanup()
+		return err
+	}
+
+	helpers.MyLogger("info", "OvertimeAttachment", "UploadAttachments", "service", "attachments uploaded successfully", map[string]interface{}{
+		"overtime_id": overtimeID,
+		"files_count": len(attachments),
+		"source":      source,
+	}, c)
+	return helpers.Response(c, fiber.StatusCreated, "Attachments uploaded successfully", attachments)
+}
+
+// ListAttachments retrieves the attachments of an overtime record
+func (s *OvertimeAttachmentService) ListAttachments(overtimeID uint, c *fiber.Ctx, tx *gorm.DB) error {
+	helpers.MyLogger("debug", "OvertimeAttachment", "ListAttachments", "service", "start list attachments", map[string]interface{}{
+		"overtime_id": overtimeID,
+	}, c)
+
+	if _, ok, err := s.findAttachmentOvertime("ListAttachments", overtimeID, false, c, tx); !ok || err != nil {
+		return err
+	}
+
+	attachments := []entities.OvertimeAttachment{}
+	if err := s.OvertimeAttachmentRepository.ListByOvertimeID(overtimeID, &attachments, c, tx); err != nil {
+		helpers.MyLogger("error", "OvertimeAttachment", "ListAttachments", "service", "error list attachments", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return err
+	}
+	return helpers.Response(c, fiber.StatusOK, "Attachments retrieved successfully", attachments)
+}
+
+// DownloadAttachment streams the stored file as a download
+func (s *OvertimeAttachmentService) DownloadAttachment(overtimeID uint, attachmentID uint, c *fiber.Ctx, tx *gorm.DB) error {
+	helpers.MyLogger("debug", "OvertimeAttachment", "DownloadAttachment", "service", "start download attachment", map[string]interface{}{
+		"overtime_id":   overtimeID,
+		"attachment_id": attachmentID,
+	}, c)
+
+	if _, ok, err := s.findAttachmentOvertime("DownloadAttachment", overtimeID, false, c, tx); !ok || err != nil {
+		return err
+	}
+
+	var attachment entities.OvertimeAttachment
+	if err := s.OvertimeAttachmentRepository.FindByID(overtimeID, attachmentID, &attachment, c, tx); err != nil {
+		if helpers.IsNotFoundError(err) {
+			return helpers.Response(c, fiber.StatusNotFound, "Attachment not found", nil)
+		}
+		return err
+	}
+
+	file, err := blobstore.Client.Open(c.Context(), attachment.StorageKey)
+	if err != nil {
+		if err == blobstore.ErrNotFound {
+			helpers.MyLogger("error", "OvertimeAttachment", "DownloadAttachment", "service", "stored file is missing", map[string]interface{}{
+				"attachment_id": attachmentID,
+				"storage_key":   attachment.StorageKey,
+			}, c)
+			return helpers.Response(c, fiber.StatusNotFound, "Attachment file not found", nil)
+		}
+		return err
+	}
+
+	// nosniff: browser tidak boleh menebak tipe lain dari isi file unggahan user
+	c.Attachment(attachment.FileName)
+	c.Set(fiber.HeaderContentType, attachment.ContentType)
+	c.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+	return c.Status(fiber.StatusOK).SendStream(file, int(attachment.Size))
+}
+
+// DeleteAttachment removes an attachment, the stored file is deleted after the commit
+func (s *OvertimeAttachmentService) DeleteAttachment(overtimeID uint, attachmentID uint, c *fiber.Ctx, tx *gorm.DB) error {
+	helpers.MyLogger("debug", "OvertimeAttachment", "DeleteAttachment", "service", "start delete attachment", map[string]interface{}{
+		"overtime_id":   overtimeID,
+		"attachment_id": attachmentID,
+	}, c)
+
+	if _, ok, err := s.findAttachmentOvertime("DeleteAttachment", overtimeID, true, c, tx); !ok || err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var attachment entities.OvertimeAttachment
+	if err := s.OvertimeAttachmentRepository.FindByID(overtimeID, attachmentID, &attachment, c, tx); err != nil {
+		tx.Rollback()
+		if helpers.IsNotFoundError(err) {
+			return helpers.Response(c, fiber.StatusNotFound, "Attachment not found", nil)
+		}
+		return err
+	}
+	if err := s.OvertimeAttachmentRepository.Delete(attachment.ID, c, tx); err != nil {
+		helpers.MyLogger("error", "OvertimeAttachment", "DeleteAttachment", "service", "error delete attachment", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit().Error; err != nil {
+		helpers.MyLogger("error", "OvertimeAttachment", "DeleteAttachment", "service", "error committing transaction", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return err
+	}
+
+	// Gagal menghapus file hanya menyisakan file yatim di storage, metadata sudah terhapus
+	if err := blobstore.Client.Delete(c.Context(), attachment.StorageKey); err != nil {
+		helpers.MyLogger("error", "OvertimeAttachment", "DeleteAttachment", "service", "error removing stored file", map[string]interface{}{
+			"storage_key": attachment.StorageKey,
+			"error":       err.Error(),
+		}, c)
+	}
+
+	helpers.MyLogger("info", "OvertimeAttachment", "DeleteAttachment", "service", "attachment deleted successfully", map[string]interface{}{
+		"overtime_id":   overtimeID,
+		"attachment_id": attachmentID,
+	}, c)
+	return helpers.Response(c, fiber.StatusOK, "Attachment deleted successfully", nil)
+}
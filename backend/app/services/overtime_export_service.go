@@ -0,0 +1,118 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/entities"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/payloads"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/helpers"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/timesheet"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// timesheetEmployee names the employee on the timesheet: full name, else @username, else the telegram ID
+func timesheetEmployee(telegramUser *entities.TelegramUser) string {
+	if name := strings.TrimSpace(telegramUser.FirstName + " " + telegramUser.LastName); name != "" {
+		return name
+	}
+	if telegramUser.Username != "" {
+		return "@" + telegramUser.Username
+	}
+	return strconv.FormatInt(telegramUser.TelegramID, 10)
+}
+
+// BuildTimesheet renders the records of GetRecordBetweenDateByTelegramId as a timesheet in format (csv, xlsx or pdf),
+// ordered by date and start time. Returns false when the telegram user is unknown, the 404 is already written.
+func (o *OvertimeService) BuildTimesheet(telegramID int64, startDate time.Time, endDate time.Time, format string, c *fiber.Ctx, tx *gorm.DB) (payloads.ExportFile, bool, error) {
+	helpers.MyLogger("debug", "OvertimeManagement", "BuildTimesheet", "service", "start build timesheet", map[string]interface{}{
+		"telegram_id": telegramID,
+		"start_date":  startDate,
+		"end_date":    endDate,
+		"format":      format,
+	}, c)
+
+	var telegramUser entities.TelegramUser
+	if err := o.TelegramRepository.FindByTelegramID(telegramID, &telegramUser, c, tx); err != nil {
+		if helpers.IsNotFoundError(err) {
+			helpers.MyLogger("info", "OvertimeManagement", "BuildTimesheet", "service", "telegram user not found", map[string]interface{}{
+				"telegram_id": telegramID,
+			}, c)
+			return payloads.ExportFile{}, false, helpers.Response(c, fiber.StatusNotFound, "Telegram user not found", nil)
+		}
+		return payloads.ExportFile{}, false, err
+	}
+
+	var overtimes []entities.Overtime
+	if err := o.OvertimeRepository.GetRecordBetweenDateByTelegramId(telegramID, startDate, endDate, &overtimes, c, tx); err != nil {
+		helpers.MyLogger("error", "OvertimeManagement", "BuildTimesheet", "service", "error getting overtime records between dates", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return payloads.ExportFile{}, false, err
+	}
+	sort.SliceStable(overtimes, func(i, j int) bool {
+		if !overtimes[i].Date.Equal(overtimes[j].Date) {
+			return overtimes[i].Date.Before(overtimes[j].Date)
+		}
+		return overtimes[i].TimeStart.Format("15:04:05") < overtimes[j].TimeStart.Format("15:04:05")
+	})
+
+	sheet := timesheet.Sheet{
+		Employee:    timesheetEmployee(&telegramUser),
+		TelegramID:  telegramID,
+		Start:       startDate,
+		End:         endDate,
+		GeneratedAt: helpers.NowWithTimezone(),
+		Rows:        make([]timesheet.Row, 0, len(overtimes)),
+	}
+	for _, overtime := range overtimes {
+		sheet.Rows = append(sheet.Rows, timesheet.Row{
+			ID:            overtime.ID,
+			Date:          overtime.Date,
+			TimeStart:     overtime.TimeStart.Format("15:04"),
+			TimeStop:      overtime.TimeStop.Format("15:04"),
+			BreakDuration: overtime.BreakDuration,
+			Duration:      overtime.Duration,
+			Category:      overtime.CategoryName(),
+			Status:        overtime.Status,
+			Description:   overtime.Description,
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := timesheet.Render(&buf, format, &sheet); err != nil {
+		helpers.MyLogger("error", "OvertimeManagement", "BuildTimesheet", "service", "error rendering timesheet", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return payloads.ExportFile{}, false, err
+	}
+
+	helpers.MyLogger("info", "OvertimeManagement", "BuildTimesheet", "service", "timesheet built successfully", map[string]interface{}{
+		"telegram_id":   telegramID,
+		"format":        format,
+		"records_count": len(sheet.Rows),
+		"size":          buf.Len(),
+	}, c)
+	return payloads.ExportFile{
+		FileName:    fmt.Sprintf("timesheet-%d-%s-%s.%s", telegramID, startDate.Format("20060102"), endDate.Format("20060102"), format),
+		ContentType: timesheet.ContentType(format),
+		Data:        buf.Bytes(),
+		Records:     len(sheet.Rows),
+	}, true, nil
+}
+
+// ExportTimesheet sends the timesheet as a file download
+func (o *OvertimeService) ExportTimesheet(telegramID int64, startDate time.Time, endDate time.Time, format string, c *fiber.Ctx, tx *gorm.DB) error {
+	file, ok, err := o.BuildTimesheet(telegramID, startDate, endDate, format, c, tx)
+	if !ok || err != nil {
+		return err
+	}
+	c.Attachment(file.FileName)
+	c.Set(fiber.HeaderContentType, file.ContentType)
+	return c.Status(fiber.StatusOK).Send(file.Data)
+}
@@ -0,0 +1,278 @@
+package services
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/entities"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/payloads"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/calendar"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/helpers"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/repositories"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// feedHistoryMonths is how far back the calendar feed goes, older records are left out to keep the file small
+const feedHistoryMonths = 12
+
+type OvertimeFeedService struct {
+	OvertimeFeedTokenRepository repositories.OvertimeFeedTokenRepository
+	TelegramRepository          repositories.TelegramRepository
+	OvertimeRepository          repositories.OvertimeRepository
+}
+
+// hashFeedToken is the value stored in the database, the plain token is only shown once after a rotate
+func hashFeedToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// FeedURL is the subscribe address of a token, based on APP_PUBLIC_URL or the host of the current request
+func FeedURL(token string, c *fiber.Ctx) string {
+	base := strings.TrimRight(helpers.GetEnv("APP_PUBLIC_URL", ""), "/")
+	if base == "" {
+		base = c.BaseURL()
+	}
+	return base + "/v1/overtime/feed/" + token + ".ics"
+}
+
+// feedEventStatus maps the overtime status to the event status shown by calendar apps
+func feedEventStatus(status string) string {
+	switch status {
+	case entities.OvertimeStatusApproved:
+		return calendar.EventConfirmed
+	case entities.OvertimeStatusRejected:
+		return calendar.EventCancelled
+	}
+	return calendar.EventTentative
+}
+
+// feedEvent turns one record into a VEVENT, date and clock times are read in TIMEZONE
+func feedEvent(overtime entities.Overtime) calendar.Event {
+	loc := helpers.GetTimezone()
+	start, stop := entities.OvertimeRange(overtime.Date, overtime.TimeStart, overtime.TimeStop)
+	start = time.Date(start.Year(), start.Month(), start.Day(), start.Hour(), start.Minute(), start.Second(), 0, loc)
+	stop = time.Date(stop.Year(), stop.Month(), stop.Day(), stop.Hour(), stop.Minute(), stop.Second(), 0, loc)
+
+	summary := fmt.Sprintf("Lembur %.2f jam", overtime.Duration)
+	var categories []string
+	if name := overtime.CategoryName(); name != "" {
+		summary += " - " + name
+		categories = []string{name}
+	}
+
+	var description []string
+	if overtime.Description != "" {
+		description = append(description, overtime.Description)
+	}
+	if overtime.BreakDuration > 0 {
+		description = append(description, fmt.Sprintf("Istirahat: %.2f jam", overtime.BreakDuration))
+	}
+	description = append(description, "Status: "+overtime.Status)
+
+	return calendar.Event{
+		UID:         fmt.Sprintf("overtime-%d@mini-app-bot-telegram", overtime.ID),
+		Start:       start,
+		End:         stop,
+		Summary:     summary,
+		Description: strings.Join(description, "\n"),
+		Categories:  categories,
+		Status:      feedEventStatus(overtime.Status),
+		Updated:     overtime.UpdatedAt,
+	}
+}
+
+// findOwnedTelegramUser resolves telegram_id for the current user, only the linked user or an admin may manage its feed.
+// Other users get the same 404 as an unknown telegram ID, found false means the response is already written.
+func (s *OvertimeFeedService) findOwnedTelegramUser(method string, telegramID int64, c *fiber.Ctx, tx *gorm.DB) (entities.TelegramUser, bool, error) {
+	var telegramUser entities.TelegramUser
+	if err := s.TelegramRepository.FindByTelegramID(telegramID, &telegramUser, c, tx); err != nil {
+		if !helpers.IsNotFoundError(err) {
+			return telegramUser, false, err
+		}
+	} else {
+		user, _ := c.Locals("user").(entities.User)
+		if telegramUser.UserID == helpers.GetCurrentUserID(c) || user.Role == entities.RoleAdmin {
+			return telegramUser, true, nil
+		}
+	}
+
+	helpers.MyLogger("info", "OvertimeFeed", method, "service", "telegram user not found", map[string]interface{}{
+		"telegram_id": telegramID,
+		"user_id":     helpers.GetCurrentUserID(c),
+	}, c)
+	return telegramUser, false, helpers.Response(c, fiber.StatusNotFound, "Telegram user not found", nil)
+}
+
+// GetFeedToken tells whether the calendar feed of a telegram user is active
+func (s *OvertimeFeedService) GetFeedToken(telegramID int64, c *fiber.Ctx, tx *gorm.DB) error {
+	helpers.MyLogger("debug", "OvertimeFeed", "GetFeedToken", "service", "start get feed token", map[string]interface{}{
+		"telegram_id": telegramID,
+	}, c)
+
+	telegramUser, ok, err := s.findOwnedTelegramUser("GetFeedToken", telegramID, c, tx)
+	if !ok || err != nil {
+		return err
+	}
+
+	response := payloads.OvertimeFeedStatusResponse{TelegramID: telegramID}
+	var token entities.OvertimeFeedToken
+	if err := s.OvertimeFeedTokenRepository.FindByTelegramUserID(telegramUser.ID, &token, c, tx); err != nil {
+		if !helpers.IsNotFoundError(err) {
+			helpers.MyLogger("error", "OvertimeFeed", "GetFeedToken", "service", "error get feed token", map[string]interface{}{
+				"error": err.Error(),
+			}, c)
+			return err
+		}
+	} else {
+		response.Active = true
+		response.CreatedAt = &token.CreatedAt
+		response.RotatedAt = &token.UpdatedAt
+		response.LastUsedAt = token.LastUsedAt
+	}
+
+	return helpers.Response(c, fiber.StatusOK, "Calendar feed retrieved successfully", response)
+}
+
+// RotateFeedToken creates a new feed token for a telegram user, the previous URL stops working immediately
+func (s *OvertimeFeedService) RotateFeedToken(telegramID int64, c *fiber.Ctx, tx *gorm.DB) error {
+	userID := helpers.GetCurrentUserID(c)
+	helpers.MyLogger("debug", "OvertimeFeed", "RotateFeedToken", "service", "start rotate feed token", map[string]interface{}{
+		"telegram_id": telegramID,
+		"user_id":     userID,
+	}, c)
+
+	telegramUser, ok, err := s.findOwnedTelegramUser("RotateFeedToken", telegramID, c, tx)
+	if !ok || err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// 32 random bytes = 43 base64url characters, safe in a URL path
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		tx.Rollback()
+		return err
+	}
+	plain := base64.RawURLEncoding.EncodeToString(raw)
+
+	token := entities.OvertimeFeedToken{
+		TelegramUserID:  telegramUser.ID,
+		TokenHash:       hashFeedToken(plain),
+		CreatedByUserID: userID,
+	}
+	if err := s.OvertimeFeedTokenRepository.Upsert(&token, c, tx); err != nil {
+		helpers.MyLogger("error", "OvertimeFeed", "RotateFeedToken", "service", "error upsert feed token", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit().Error; err != nil {
+		helpers.MyLogger("error", "OvertimeFeed", "RotateFeedToken", "service", "error committing transaction", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return err
+	}
+
+	helpers.MyLogger("info", "OvertimeFeed", "RotateFeedToken", "service", "feed token rotated successfully", map[string]interface{}{
+		"telegram_id": telegramID,
+		"user_id":     userID,
+	}, c)
+	return helpers.Response(c, fiber.StatusCreated, "Calendar feed token created successfully", payloads.OvertimeFeedTokenResponse{
+		TelegramID: telegramID,
+		Token:      plain,
+		URL:        FeedURL(plain, c),
+		RotatedAt:  token.UpdatedAt,
+	})
+}
+
+// RevokeFeedToken deletes the feed token of a telegram user, the feed URL answers 404 afterwards
+func (s *OvertimeFeedService) RevokeFeedToken(telegramID int64, c *fiber.Ctx, tx *gorm.DB) error {
+	helpers.MyLogger("debug", "OvertimeFeed", "RevokeFeedToken", "service", "start revoke feed token", map[string]interface{}{
+		"telegram_id": telegramID,
+	}, c)
+
+	telegramUser, ok, err := s.findOwnedTelegramUser("RevokeFeedToken", telegramID, c, tx)
+	if !ok || err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	deleted, err := s.OvertimeFeedTokenRepository.DeleteByTelegramUserID(telegramUser.ID, c, tx)
+	if err != nil {
+		helpers.MyLogger("error", "OvertimeFeed", "RevokeFeedToken", "service", "error delete feed token", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		tx.Rollback()
+		return err
+	}
+	if deleted == 0 {
+		tx.Rollback()
+		return helpers.Response(c, fiber.StatusNotFound, "Calendar feed is not active", nil)
+	}
+	if err := tx.Commit().Error; err != nil {
+		helpers.MyLogger("error", "OvertimeFeed", "RevokeFeedToken", "service", "error committing transaction", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return err
+	}
+
+	helpers.MyLogger("info", "OvertimeFeed", "RevokeFeedToken", "service", "feed token revoked successfully", map[string]interface{}{
+		"telegram_id": telegramID,
+	}, c)
+	return helpers.Response(c, fiber.StatusOK, "Calendar feed token revoked successfully", nil)
+}
+
+// RenderFeed writes the iCalendar feed of the token owner, records of the last feedHistoryMonths months and later
+func (s *OvertimeFeedService) RenderFeed(plain string, c *fiber.Ctx, tx *gorm.DB) error {
+	var token entities.OvertimeFeedToken
+	if err := s.OvertimeFeedTokenRepository.FindByTokenHash(hashFeedToken(plain), &token, c, tx); err != nil {
+		if helpers.IsNotFoundError(err) {
+			helpers.MyLogger("info", "OvertimeFeed", "RenderFeed", "service", "feed token not found", nil, c)
+			return helpers.Response(c, fiber.StatusNotFound, "Calendar feed not found", nil)
+		}
+		return err
+	}
+
+	now := helpers.NowWithTimezone()
+	var overtimes []entities.Overtime
+	if err := s.OvertimeRepository.ListForFeed(token.TelegramUserID, now.AddDate(0, -feedHistoryMonths, 0), &overtimes, c, tx); err != nil {
+		helpers.MyLogger("error", "OvertimeFeed", "RenderFeed", "service", "error list overtime records for feed", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return err
+	}
+
+	events := make([]calendar.Event, 0, len(overtimes))
+	for _, overtime := range overtimes {
+		events = append(events, feedEvent(overtime))
+	}
+	var buf bytes.Buffer
+	if err := calendar.WriteICS(&buf, "Lembur "+timesheetEmployee(&token.TelegramUser), events); err != nil {
+		return err
+	}
+
+	// Gagal mencatat last_used_at tidak boleh menggagalkan feed
+	if err := s.OvertimeFeedTokenRepository.TouchLastUsed(token.ID, now, c, tx); err != nil {
+		helpers.MyLogger("warn", "OvertimeFeed", "RenderFeed", "service", "error touch feed token", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+	}
+
+	helpers.MyLogger("debug", "OvertimeFeed", "RenderFeed", "service", "feed rendered", map[string]interface{}{
+		"telegram_user_id": token.TelegramUserID,
+		"events_count":     len(events),
+	}, c)
+	c.Set(fiber.HeaderContentType, "text/calendar; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, `inline; filename="lembur.ics"`)
+	c.Set(fiber.HeaderCacheControl, "private, max-age=300")
+	return c.Status(fiber.StatusOK).Send(buf.Bytes())
+}
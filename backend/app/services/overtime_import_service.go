@@ -0,0 +1,339 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/entities"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/payloads"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/helpers"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/limits"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/overtimecsv"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// importSavePoint is set before every row so a row whose insert fails does not abort the whole transaction
+const importSavePoint = "import_row"
+
+// importLookups caches telegram users and categories across the rows of one import, nil/0 = not found
+type importLookups struct {
+	telegramUsers map[int64]uint
+	categories    map[string]*entities.Category
+}
+
+// importPayload turns a CSV row into the payload of POST /v1/overtime, numbers that cannot be read are reported per column
+func importPayload(row overtimecsv.Row) (payloads.CreateNewRecordOvertime, []map[string]string) {
+	payload := payloads.CreateNewRecordOvertime{
+		Date:        row.Date,
+		TimeStart:   row.TimeStart,
+		TimeStop:    row.TimeStop,
+		Category:    row.Category,
+		Description: row.Description,
+	}
+	var rowErrors []map[string]string
+	if row.TelegramID != "" {
+		telegramID, err := strconv.ParseInt(row.TelegramID, 10, 64)
+		if err != nil {
+			rowErrors = append(rowErrors, map[string]string{"telegram_id": "Telegram ID must be a number"})
+		}
+		payload.TelegramID = telegramID
+	}
+	if row.Break != "" {
+		breakDuration, err := parseImportHours(row.Break)
+		if err != nil {
+			rowErrors = append(rowErrors, map[string]string{"break_duration": "Break duration must be a number of hours"})
+		}
+		payload.BreakDuration = breakDuration
+	}
+	if row.Duration != "" {
+		duration, err := parseImportHours(row.Duration)
+		if err != nil {
+			rowErrors = append(rowErrors, map[string]string{"duration": "Duration must be a number of hours"})
+		}
+		payload.Duration = duration
+	}
+	return payload, rowErrors
+}
+
+// parseImportHours accepts a decimal comma as spreadsheets in id-ID write it (1,5)
+func parseImportHours(value string) (float64, error) {
+	return strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+}
+
+// parseImportTime reads time_start/time_stop as HH:MM[:SS] or YYYY-MM-DDTHH:MM:SS like CreateNewRecordOvertime
+func parseImportTime(value string) (time.Time, error) {
+	if helpers.IsTimeOnlyFormat(value) {
+		return helpers.ParseTimeWithTimezone(value)
+	}
+	return helpers.ParseDateTimeWithTimezone(value)
+}
+
+// validateImportPayload runs the validation rules of payloads.CreateNewRecordOvertime, a column that already failed to parse is reported once
+func validateImportPayload(payload *payloads.CreateNewRecordOvertime, rowErrors []map[string]string) []map[string]string {
+	err := helpers.ValidateStruct(payload)
+	if err == nil {
+		return rowErrors
+	}
+	customErr, ok := err.(helpers.Error)
+	if !ok {
+		return append(rowErrors, map[string]string{"row": err.Error()})
+	}
+	data, _ := customErr.Data.([]map[string]string)
+	reported := map[string]bool{}
+	for _, rowError := range rowErrors {
+		for column := range rowError {
+			reported[column] = true
+		}
+	}
+	for _, validationError := range data {
+		for column := range validationError {
+			if !reported[column] {
+				rowErrors = append(rowErrors, validationError)
+			}
+		}
+	}
+	return rowErrors
+}
+
+// importRow checks one row like CreateNewRecordOvertime and inserts it into tx. Problems with the row are returned
+// keyed by column, err is only set when the database failed.
+func (o *OvertimeService) importRow(row overtimecsv.Row, userID uint, lookups *importLookups, c *fiber.Ctx, tx *gorm.DB) (*entities.Overtime, []map[string]string, error) {
+	payload, rowErrors := importPayload(row)
+	rowErrors = validateImportPayload(&payload, rowErrors)
+	if len(rowErrors) > 0 {
+		return nil, rowErrors, nil
+	}
+
+	telegramUserID, ok := lookups.telegramUsers[payload.TelegramID]
+	if !ok {
+		id, err := o.OvertimeRepository.GetTelegramUserIDByTelegramID(payload.TelegramID, c, tx)
+		if err != nil {
+			if !helpers.IsNotFoundError(err) {
+				return nil, nil, err
+			}
+			id = 0
+		}
+		telegramUserID = id
+		lookups.telegramUsers[payload.TelegramID] = id
+	}
+	if telegramUserID == 0 {
+		rowErrors = append(rowErrors, map[string]string{"telegram_id": "Telegram user not found"})
+	}
+
+	date, err := helpers.ParseDateWithTimezone(payload.Date)
+	if err != nil {
+		rowErrors = append(rowErrors, map[string]string{"date": "Invalid date format. Use YYYY-MM-DD"})
+	}
+	timeStart, err := parseImportTime(payload.TimeStart)
+	if err != nil {
+		rowErrors = append(rowErrors, map[string]string{"time_start": "Invalid time start format. Use YYYY-MM-DDTHH:MM:SS or HH:MM:SS"})
+	}
+	timeStop, err := parseImportTime(payload.TimeStop)
+	if err != nil {
+		rowErrors = append(rowErrors, map[string]string{"time_stop": "Invalid time stop format. Use YYYY-MM-DDTHH:MM:SS or HH:MM:SS"})
+	}
+	if len(rowErrors) > 0 {
+		return nil, rowErrors, nil
+	}
+
+	duration, err := computeDuration(timeStart, timeStop, payload.BreakDuration, payload.Duration)
+	if err != nil {
+		var mismatch *durationMismatchError
+		if errors.As(err, &mismatch) {
+			rowErrors = append(rowErrors, map[string]string{"duration": mismatch.Error()})
+		} else {
+			rowErrors = append(rowErrors, map[string]string{"duration": "Invalid overtime duration: " + err.Error()})
+		}
+	}
+
+	var category *entities.Category
+	if name := strings.ToLower(strings.TrimSpace(payload.Category)); name != "" {
+		found, ok := lookups.categories[name]
+		if !ok {
+			var loaded entities.Category
+			err := o.CategoryRepository.FindByName(name, &loaded, c, tx)
+			if err != nil && !helpers.IsNotFoundError(err) {
+				return nil, nil, err
+			}
+			if err == nil {
+				found = &loaded
+			}
+			lookups.categories[name] = found
+		}
+		switch {
+		case found == nil:
+			rowErrors = append(rowErrors, map[string]string{"category": "Unknown category " + payload.Category})
+		case !found.Active:
+			rowErrors = append(rowErrors, map[string]string{"category": "Category " + found.Name + " is inactive"})
+		default:
+			category = found
+		}
+	}
+	if len(rowErrors) > 0 {
+		return nil, rowErrors, nil
+	}
+
+	overtime := entities.Overtime{
+		TelegramUserID:  telegramUserID,
+		Date:            date,
+		TimeStart:       timeStart,
+		TimeStop:        timeStop,
+		BreakDuration:   payload.BreakDuration,
+		Duration:        duration,
+		Description:     payload.Description,
+		CreatedByUserID: userID,
+	}
+	if category != nil {
+		overtime.CategoryID = &category.ID
+		if !category.RequiresApproval {
+			overtime.Status = entities.OvertimeStatusApproved
+		}
+	}
+
+	// earlier rows of the file are already in tx, so rows overlapping each other are caught as well
+	conflictingIDs, err := o.findOverlaps(&overtime, 0, c, tx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(conflictingIDs) > 0 {
+		ids := make([]string, 0, len(conflictingIDs))
+		for _, id := range conflictingIDs {
+			ids = append(ids, strconv.FormatUint(uint64(id), 10))
+		}
+		return nil, []map[string]string{{"overlap": "Overtime overlaps with records " + strings.Join(ids, ", ")}}, nil
+	}
+
+	violations, policy, err := checkOvertimeLimits(&o.OvertimeRepository, &o.OvertimeLimitRepository, &overtime, 0, c, tx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(violations) > 0 {
+		switch policy.Mode {
+		case limits.ModeBlock:
+			return nil, []map[string]string{{"limit": limitReason(violations)}}, nil
+		case limits.ModeRequireApproval:
+			overtime.Status = entities.OvertimeStatusSubmitted
+		}
+		overtime.Warnings = violations
+	}
+
+	if err := o.OvertimeRepository.CreateNewRecordOvertime(&overtime, c, tx); err != nil {
+		if helpers.IsExclusionViolationError(err) {
+			return nil, []map[string]string{{"overlap": "Overtime overlaps with existing records"}}, nil
+		}
+		return nil, nil, err
+	}
+	if err := o.recordInitialStatus(&overtime, category, violations, userID, c, tx); err != nil {
+		return nil, nil, err
+	}
+	return &overtime, nil, nil
+}
+
+// ImportRecordOvertime creates overtime records from a CSV file in one transaction, every row is checked like POST /v1/overtime.
+// dryRun only reports the result. An invalid row rejects the whole file unless skipInvalid is set, then only the valid rows are saved.
+func (o *OvertimeService) ImportRecordOvertime(file io.Reader, dryRun bool, skipInvalid bool, c *fiber.Ctx, tx *gorm.DB) error {
+	userID := helpers.GetCurrentUserID(c)
+	helpers.MyLogger("debug", "OvertimeManagement", "ImportRecordOvertime", "service", "start import overtime records", map[string]interface{}{
+		"user_id":      userID,
+		"dry_run":      dryRun,
+		"skip_invalid": skipInvalid,
+	}, c)
+
+	rows, skipped, err := overtimecsv.Parse(file)
+	if err != nil {
+		tx.Rollback()
+		helpers.MyLogger("info", "OvertimeManagement", "ImportRecordOvertime", "service", "invalid overtime csv file", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return helpers.Response(c, fiber.StatusUnprocessableEntity, "Invalid csv file: "+err.Error(), nil)
+	}
+
+	response := payloads.ImportOvertimeResponse{
+		DryRun:      dryRun,
+		SkipInvalid: skipInvalid,
+		Total:       len(rows) + len(skipped),
+		IDs:         []uint{},
+		Errors:      []payloads.ImportOvertimeRowError{},
+	}
+	if response.Total == 0 {
+		tx.Rollback()
+		return helpers.Response(c, fiber.StatusUnprocessableEntity, "Invalid csv file: no data rows", nil)
+	}
+	for _, lineErr := range skipped {
+		response.Errors = append(response.Errors, payloads.ImportOvertimeRowError{
+			Line:   lineErr.Line,
+			Errors: []map[string]string{{"row": lineErr.Error}},
+		})
+	}
+
+	lookups := importLookups{telegramUsers: map[int64]uint{}, categories: map[string]*entities.Category{}}
+	for _, row := range rows {
+		if err := tx.SavePoint(importSavePoint).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+		overtime, rowErrors, err := o.importRow(row, userID, &lookups, c, tx)
+		if err != nil {
+			helpers.MyLogger("error", "OvertimeManagement", "ImportRecordOvertime", "service", "error importing overtime row", map[string]interface{}{
+				"line":  row.Line,
+				"error": err.Error(),
+			}, c)
+			tx.Rollback()
+			return err
+		}
+		if len(rowErrors) > 0 {
+			if err := tx.RollbackTo(importSavePoint).Error; err != nil {
+				tx.Rollback()
+				return err
+			}
+			response.Errors = append(response.Errors, payloads.ImportOvertimeRowError{Line: row.Line, Errors: rowErrors})
+			continue
+		}
+		response.IDs = append(response.IDs, overtime.ID)
+	}
+	sort.SliceStable(response.Errors, func(i, j int) bool {
+		return response.Errors[i].Line < response.Errors[j].Line
+	})
+	response.Valid = len(response.IDs)
+	response.Skipped = len(response.Errors)
+
+	logData := map[string]interface{}{
+		"total":   response.Total,
+		"valid":   response.Valid,
+		"invalid": response.Skipped,
+	}
+	if dryRun {
+		tx.Rollback()
+		response.IDs = []uint{}
+		helpers.MyLogger("info", "OvertimeManagement", "ImportRecordOvertime", "service", "overtime import dry run finished", logData, c)
+		return helpers.Response(c, fiber.StatusOK, "Dry run finished, nothing was saved", response)
+	}
+	if response.Valid == 0 || (response.Skipped > 0 && !skipInvalid) {
+		tx.Rollback()
+		response.IDs = []uint{}
+		helpers.MyLogger("info", "OvertimeManagement", "ImportRecordOvertime", "service", "overtime import rejected", logData, c)
+		message := fmt.Sprintf("%d invalid rows, nothing was imported", response.Skipped)
+		if response.Valid == 0 {
+			message = "No valid rows, nothing was imported"
+		}
+		return helpers.Response(c, fiber.StatusUnprocessableEntity, message, response)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		helpers.MyLogger("error", "OvertimeManagement", "ImportRecordOvertime", "service", "error committing transaction", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		tx.Rollback()
+		return err
+	}
+	response.Imported = len(response.IDs)
+
+	helpers.MyLogger("info", "OvertimeManagement", "ImportRecordOvertime", "service", "overtime records imported successfully", logData, c)
+	return helpers.Response(c, fiber.StatusCreated, "Overtime records imported successfully", response)
+}
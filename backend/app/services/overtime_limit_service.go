@@ -0,0 +1,235 @@
+package services
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/entities"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/payloads"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/helpers"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/limits"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/repositories"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type OvertimeLimitService struct {
+	OvertimeRepository      repositories.OvertimeRepository
+	OvertimeLimitRepository repositories.OvertimeLimitRepository
+}
+
+func limitHoursFromEnv(key string, fallback float64) float64 {
+	hours, err := strconv.ParseFloat(helpers.GetEnv(key, strconv.FormatFloat(fallback, 'f', -1, 64)), 64)
+	if err != nil || hours < 0 {
+		return fallback
+	}
+	return hours
+}
+
+// defaultLimitPolicy reads OVERTIME_LIMIT_DAILY, OVERTIME_LIMIT_WEEKLY, OVERTIME_LIMIT_MONTHLY (hours, 0 = no cap)
+// and OVERTIME_LIMIT_MODE, defaults are the PP 35/2021 caps in warn mode
+func defaultLimitPolicy() limits.Policy {
+	mode := strings.ToLower(strings.TrimSpace(helpers.GetEnv("OVERTIME_LIMIT_MODE", limits.ModeWarn)))
+	if !limits.ValidMode(mode) {
+		mode = limits.ModeWarn
+	}
+	return limits.Policy{
+		DailyHours:   limitHoursFromEnv("OVERTIME_LIMIT_DAILY", limits.StatutoryDailyHours),
+		WeeklyHours:  limitHoursFromEnv("OVERTIME_LIMIT_WEEKLY", limits.StatutoryWeeklyHours),
+		MonthlyHours: limitHoursFromEnv("OVERTIME_LIMIT_MONTHLY", 0),
+		Mode:         mode,
+	}
+}
+
+// effectiveLimitPolicy applies the fields set in an employee override on top of the defaults
+func effectiveLimitPolicy(defaults limits.Policy, override *entities.OvertimeLimit) limits.Policy {
+	policy := defaults
+	if override == nil {
+		return policy
+	}
+	if override.DailyHours != nil {
+		policy.DailyHours = *override.DailyHours
+	}
+	if override.WeeklyHours != nil {
+		policy.WeeklyHours = *override.WeeklyHours
+	}
+	if override.MonthlyHours != nil {
+		policy.MonthlyHours = *override.MonthlyHours
+	}
+	if override.Mode != "" {
+		policy.Mode = override.Mode
+	}
+	return policy
+}
+
+// checkOvertimeLimits sums the other records of the same telegram user in the day, week and month of overtime and
+// returns the violated caps together with the policy that applies to that user
+func checkOvertimeLimits(overtimeRepo *repositories.OvertimeRepository, limitRepo *repositories.OvertimeLimitRepository, overtime *entities.Overtime, excludeID uint, c *fiber.Ctx, tx *gorm.DB) ([]limits.Violation, limits.Policy, error) {
+	var override entities.OvertimeLimit
+	policy := defaultLimitPolicy()
+	err := limitRepo.FindByTelegramUserID(overtime.TelegramUserID, &override, c, tx)
+	if err == nil {
+		policy = effectiveLimitPolicy(policy, &override)
+	} else if !helpers.IsNotFoundError(err) {
+		return nil, policy, err
+	}
+
+	weekStart, weekEnd := limits.WeekRange(overtime.Date)
+	monthStart, monthEnd := limits.MonthRange(overtime.Date)
+	var usage repositories.OvertimeUsageRow
+	if err := overtimeRepo.SumDurationsAround(overtime.TelegramUserID, overtime.Date, weekStart, weekEnd, monthStart, monthEnd, excludeID, &usage, c, tx); err != nil {
+		return nil, policy, err
+	}
+
+	return policy.Check(limits.Usage{
+		DayHours:   usage.DayHours + overtime.Duration,
+		WeekHours:  usage.WeekHours + overtime.Duration,
+		MonthHours: usage.MonthHours + overtime.Duration,
+	}), policy, nil
+}
+
+// limitReason joins the violation messages for the status history
+func limitReason(violations []limits.Violation) string {
+	messages := make([]string, 0, len(violations))
+	for _, violation := range violations {
+		messages = append(messages, violation.Message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+func limitBlockedResponse(c *fiber.Ctx, violations []limits.Violation) error {
+	return helpers.Response(c, fiber.StatusUnprocessableEntity, "Overtime exceeds the limit: "+limitReason(violations), map[string]interface{}{
+		"limit_violations": violations,
+	})
+}
+
+func (s *OvertimeLimitService) limitResponse(telegramID int64, override *entities.OvertimeLimit) payloads.OvertimeLimitResponse {
+	defaults := defaultLimitPolicy()
+	return payloads.OvertimeLimitResponse{
+		TelegramID: telegramID,
+		Default:    defaults,
+		Override:   override,
+		Effective:  effectiveLimitPolicy(defaults, override),
+	}
+}
+
+// findTelegramUserID resolves telegram_id, it writes the 404 response itself when found is false
+func (s *OvertimeLimitService) findTelegramUserID(method string, telegramID int64, c *fiber.Ctx, tx *gorm.DB) (uint, bool, error) {
+	telegramUserID, err := s.OvertimeRepository.GetTelegramUserIDByTelegramID(telegramID, c, tx)
+	if err != nil {
+		if helpers.IsNotFoundError(err) {
+			helpers.MyLogger("info", "OvertimeLimit", method, "service", "telegram user not found", map[string]interface{}{
+				"telegram_id": telegramID,
+			}, c)
+			return 0, false, helpers.Response(c, fiber.StatusNotFound, "Telegram user not found", nil)
+		}
+		return 0, false, err
+	}
+	return telegramUserID, true, nil
+}
+
+// GetOvertimeLimit retrieves the default, override and effective limits of a telegram user
+func (s *OvertimeLimitService) GetOvertimeLimit(telegramID int64, c *fiber.Ctx, tx *gorm.DB) error {
+	helpers.MyLogger("debug", "OvertimeLimit", "GetOvertimeLimit", "service", "start get overtime limit", map[string]interface{}{
+		"telegram_id": telegramID,
+	}, c)
+
+	telegramUserID, found, err := s.findTelegramUserID("GetOvertimeLimit", telegramID, c, tx)
+	if !found {
+		return err
+	}
+
+	var override entities.OvertimeLimit
+	if err := s.OvertimeLimitRepository.FindByTelegramUserID(telegramUserID, &override, c, tx); err != nil {
+		if !helpers.IsNotFoundError(err) {
+			helpers.MyLogger("error", "OvertimeLimit", "GetOvertimeLimit", "service", "error getting overtime limit", map[string]interface{}{
+				"error": err.Error(),
+			}, c)
+			return err
+		}
+		return helpers.Response(c, fiber.StatusOK, "Overtime limit retrieved successfully", s.limitResponse(telegramID, nil))
+	}
+	return helpers.Response(c, fiber.StatusOK, "Overtime limit retrieved successfully", s.limitResponse(telegramID, &override))
+}
+
+// UpsertOvertimeLimit creates or replaces the limit override of a telegram user
+func (s *OvertimeLimitService) UpsertOvertimeLimit(telegramID int64, payload *payloads.UpsertOvertimeLimitPayload, c *fiber.Ctx, tx *gorm.DB) error {
+	userID := helpers.GetCurrentUserID(c)
+	helpers.MyLogger("debug", "OvertimeLimit", "UpsertOvertimeLimit", "service", "start upsert overtime limit", map[string]interface{}{
+		"telegram_id": telegramID,
+		"user_id":     userID,
+	}, c)
+
+	telegramUserID, found, err := s.findTelegramUserID("UpsertOvertimeLimit", telegramID, c, tx)
+	if !found {
+		return err
+	}
+
+	override := entities.OvertimeLimit{
+		TelegramUserID:  telegramUserID,
+		DailyHours:      payload.DailyHours,
+		WeeklyHours:     payload.WeeklyHours,
+		MonthlyHours:    payload.MonthlyHours,
+		Mode:            payload.Mode,
+		UpdatedByUserID: userID,
+	}
+	if err := s.OvertimeLimitRepository.Upsert(&override, c, tx); err != nil {
+		helpers.MyLogger("error", "OvertimeLimit", "UpsertOvertimeLimit", "service", "error saving overtime limit", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		tx.Rollback()
+		return err
+	}
+
+	// reload so id and created_at of an existing override are returned
+	if err := s.OvertimeLimitRepository.FindByTelegramUserID(telegramUserID, &override, c, tx); err != nil {
+		return err
+	}
+	if err := tx.Commit().Error; err != nil {
+		helpers.MyLogger("error", "OvertimeLimit", "UpsertOvertimeLimit", "service", "error committing transaction", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		tx.Rollback()
+		return err
+	}
+
+	helpers.MyLogger("info", "OvertimeLimit", "UpsertOvertimeLimit", "service", "overtime limit saved successfully", map[string]interface{}{
+		"telegram_id": telegramID,
+		"user_id":     userID,
+	}, c)
+	return helpers.Response(c, fiber.StatusOK, "Overtime limit saved successfully", s.limitResponse(telegramID, &override))
+}
+
+// DeleteOvertimeLimit removes the limit override of a telegram user, the defaults apply again
+func (s *OvertimeLimitService) DeleteOvertimeLimit(telegramID int64, c *fiber.Ctx, tx *gorm.DB) error {
+	userID := helpers.GetCurrentUserID(c)
+	helpers.MyLogger("debug", "OvertimeLimit", "DeleteOvertimeLimit", "service", "start delete overtime limit", map[string]interface{}{
+		"telegram_id": telegramID,
+		"user_id":     userID,
+	}, c)
+
+	telegramUserID, found, err := s.findTelegramUserID("DeleteOvertimeLimit", telegramID, c, tx)
+	if !found {
+		return err
+	}
+
+	deleted, err := s.OvertimeLimitRepository.DeleteByTelegramUserID(telegramUserID, c, tx)
+	if err != nil {
+		helpers.MyLogger("error", "OvertimeLimit", "DeleteOvertimeLimit", "service", "error deleting overtime limit", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return err
+	}
+	if deleted == 0 {
+		return helpers.Response(c, fiber.StatusNotFound, "Overtime limit override not found", nil)
+	}
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	helpers.MyLogger("info", "OvertimeLimit", "DeleteOvertimeLimit", "service", "overtime limit deleted successfully", map[string]interface{}{
+		"telegram_id": telegramID,
+		"user_id":     userID,
+	}, c)
+	return helpers.Response(c, fiber.StatusOK, "Overtime limit deleted successfully", s.limitResponse(telegramID, nil))
+}
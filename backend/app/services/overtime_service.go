@@ -1,19 +1,118 @@
 package services
 
 import (
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/entities"
 	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/payloads"
 	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/database"
 	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/helpers"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/limits"
 	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/repositories"
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
 )
 
 type OvertimeService struct {
-	OvertimeRepository repositories.OvertimeRepository
+	OvertimeRepository              repositories.OvertimeRepository
+	OvertimeStatusHistoryRepository repositories.OvertimeStatusHistoryRepository
+	HolidayRepository               repositories.HolidayRepository
+	CategoryRepository              repositories.CategoryRepository
+	TelegramRepository              repositories.TelegramRepository
+	OvertimeLimitRepository         repositories.OvertimeLimitRepository
+}
+
+// tagCalendarFlags fills is_holiday and is_weekend of the records returned to the client
+func (o *OvertimeService) tagCalendarFlags(method string, overtimes []entities.Overtime, c *fiber.Ctx, tx *gorm.DB) error {
+	if err := TagCalendarFlags(&o.HolidayRepository, overtimes, c, tx); err != nil {
+		helpers.MyLogger("error", "OvertimeManagement", method, "service", "error tagging holiday and weekend flags", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return err
+	}
+	return nil
+}
+
+func (o *OvertimeService) tagCalendarFlagsOne(method string, overtime *entities.Overtime, c *fiber.Ctx, tx *gorm.DB) error {
+	records := []entities.Overtime{*overtime}
+	if err := o.tagCalendarFlags(method, records, c, tx); err != nil {
+		return err
+	}
+	*overtime = records[0]
+	return nil
+}
+
+// durationMismatchError is returned when a client supplied duration disagrees with the computed one
+type durationMismatchError struct {
+	Provided float64
+	Computed float64
+}
+
+func (e *durationMismatchError) Error() string {
+	return fmt.Sprintf("duration %.2f does not match %.2f hours computed from time_start, time_stop and break_duration", e.Provided, e.Computed)
+}
+
+// computeDuration derives the duration in hours, provided (0 = not sent) must be within the configured tolerance
+func computeDuration(timeStart time.Time, timeStop time.Time, breakDuration float64, provided float64) (float64, error) {
+	duration, err := helpers.ComputeOvertimeDuration(timeStart, timeStop, breakDuration)
+	if err != nil {
+		return 0, err
+	}
+	if provided > 0 && !helpers.DurationMatches(provided, duration) {
+		return 0, &durationMismatchError{Provided: provided, Computed: duration}
+	}
+	return duration, nil
+}
+
+func durationErrorResponse(c *fiber.Ctx, err error) error {
+	var mismatch *durationMismatchError
+	if errors.As(err, &mismatch) {
+		return helpers.Response(c, fiber.StatusUnprocessableEntity, "Duration does not match time_start, time_stop and break_duration", map[string]interface{}{
+			"duration":          mismatch.Provided,
+			"computed_duration": mismatch.Computed,
+			"tolerance_minutes": helpers.GetOvertimeDurationTolerance() * 60,
+		})
+	}
+	return helpers.Response(c, fiber.StatusUnprocessableEntity, "Invalid overtime duration: "+err.Error(), nil)
+}
+
+// findOverlaps returns the IDs of other records of the same telegram user whose time range intersects overtime
+func (o *OvertimeService) findOverlaps(overtime *entities.Overtime, excludeID uint, c *fiber.Ctx, tx *gorm.DB) ([]uint, error) {
+	start, stop := entities.OvertimeRange(overtime.Date, overtime.TimeStart, overtime.TimeStop)
+	ids := []uint{}
+	if err := o.OvertimeRepository.FindOverlappingIDs(overtime.TelegramUserID, start, stop, excludeID, &ids, c, tx); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func overlapResponse(c *fiber.Ctx, conflictingIDs []uint) error {
+	return helpers.Response(c, fiber.StatusConflict, "Overtime overlaps with existing records", map[string]interface{}{
+		"conflicting_ids": conflictingIDs,
+	})
+}
+
+// recordInitialStatus writes the history entry of a new record that skipped the draft status,
+// either because its category needs no approval or because it exceeded a limit in require_approval mode
+func (o *OvertimeService) recordInitialStatus(overtime *entities.Overtime, category *entities.Category, violations []limits.Violation, userID uint, c *fiber.Ctx, tx *gorm.DB) error {
+	if overtime.Status != entities.OvertimeStatusApproved && overtime.Status != entities.OvertimeStatusSubmitted {
+		return nil
+	}
+	history := entities.OvertimeStatusHistory{
+		OvertimeID:      overtime.ID,
+		FromStatus:      entities.OvertimeStatusDraft,
+		ToStatus:        overtime.Status,
+		ChangedByUserID: userID,
+	}
+	if overtime.Status == entities.OvertimeStatusApproved {
+		history.Reason = "category " + category.Name + " does not require approval"
+	} else {
+		history.Reason = limitReason(violations)
+	}
+	return o.OvertimeStatusHistoryRepository.Create(&history, c, tx)
 }
 
 // CreateNewRecordOvertime creates a new overtime record
@@ -121,27 +220,98 @@ func (o *OvertimeService) CreateNewRecordOvertime(payload *payloads.CreateNewRec
 		"time_stop": timeStop,
 	}, c)
 
+	// Duration is always derived from the time range, a client supplied duration is only checked against it
+	duration, err := computeDuration(timeStart, timeStop, payload.BreakDuration, payload.Duration)
+	if err != nil {
+		helpers.MyLogger("info", "OvertimeManagement", "CreateNewRecordOvertime", "service", "duration rejected", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		tx.Rollback()
+		return durationErrorResponse(c, err)
+	}
+
+	category, ok, err := resolveCategory(&o.CategoryRepository, "CreateNewRecordOvertime", payload.Category, payload.CategoryID, c, tx)
+	if !ok || err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	var overtime entities.Overtime
 	overtime.TelegramUserID = telegramUserID
 	overtime.Date = date
 	overtime.TimeStart = timeStart
 	overtime.TimeStop = timeStop
 	overtime.BreakDuration = payload.BreakDuration
-	overtime.Duration = payload.Duration
+	overtime.Duration = duration
 	overtime.Description = payload.Description
-	overtime.Category = payload.Category
 	overtime.CreatedByUserID = userID
+	if category != nil {
+		overtime.CategoryID = &category.ID
+		// categories without approval skip the draft -> submitted -> approved workflow
+		if !category.RequiresApproval {
+			overtime.Status = entities.OvertimeStatusApproved
+		}
+	}
+
+	// Reject records whose time range intersects another record of the same telegram user
+	helpers.MyLogger("debug", "OvertimeManagement", "CreateNewRecordOvertime", "service", "check overlapping records | calling repository FindOverlappingIDs", map[string]interface{}{
+		"telegram_user_id": telegramUserID,
+	}, c)
+	conflictingIDs, err := o.findOverlaps(&overtime, 0, c, tx)
+	if err != nil {
+		helpers.MyLogger("error", "OvertimeManagement", "CreateNewRecordOvertime", "service", "error checking overlapping records", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		tx.Rollback()
+		return err
+	}
+	if len(conflictingIDs) > 0 {
+		helpers.MyLogger("info", "OvertimeManagement", "CreateNewRecordOvertime", "service", "overtime record overlaps existing records", map[string]interface{}{
+			"telegram_id":     payload.TelegramID,
+			"conflicting_ids": conflictingIDs,
+		}, c)
+		tx.Rollback()
+		return overlapResponse(c, conflictingIDs)
+	}
+
+	// Check the daily, weekly and monthly overtime limits of the telegram user
+	violations, policy, err := checkOvertimeLimits(&o.OvertimeRepository, &o.OvertimeLimitRepository, &overtime, 0, c, tx)
+	if err != nil {
+		helpers.MyLogger("error", "OvertimeManagement", "CreateNewRecordOvertime", "service", "error checking overtime limits", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		tx.Rollback()
+		return err
+	}
+	if len(violations) > 0 {
+		helpers.MyLogger("info", "OvertimeManagement", "CreateNewRecordOvertime", "service", "overtime record exceeds limits", map[string]interface{}{
+			"telegram_id": payload.TelegramID,
+			"mode":        policy.Mode,
+			"violations":  violations,
+		}, c)
+		switch policy.Mode {
+		case limits.ModeBlock:
+			tx.Rollback()
+			return limitBlockedResponse(c, violations)
+		case limits.ModeRequireApproval:
+			// an approver has to decide, even when the category skips approval
+			overtime.Status = entities.OvertimeStatusSubmitted
+		}
+		overtime.Warnings = violations
+	}
 
 	helpers.MyLogger("debug", "OvertimeManagement", "CreateNewRecordOvertime", "service", "calling repository to create overtime record", nil, c)
 	err = o.OvertimeRepository.CreateNewRecordOvertime(&overtime, c, tx)
 	if err != nil {
-		if helpers.IsDuplicateKeyError(err) {
-			helpers.MyLogger("info", "OvertimeManagement", "CreateNewRecordOvertime", "service", "overtime record already exists for this date", map[string]interface{}{
-				"telegram_id": payload.TelegramID,
-				"date":        payload.Date,
-			}, c)
+		if helpers.IsExclusionViolationError(err) {
+			// a concurrent request inserted an overlapping record after our check
 			tx.Rollback()
-			return helpers.Response(c, fiber.StatusConflict, "Overtime record already exists for this date", nil)
+			conflictingIDs, _ = o.findOverlaps(&overtime, 0, c, database.ClientPostgres)
+			helpers.MyLogger("info", "OvertimeManagement", "CreateNewRecordOvertime", "service", "overtime record overlaps existing records", map[string]interface{}{
+				"telegram_id":     payload.TelegramID,
+				"conflicting_ids": conflictingIDs,
+			}, c)
+			return overlapResponse(c, conflictingIDs)
 		}
 		helpers.MyLogger("error", "OvertimeManagement", "CreateNewRecordOvertime", "service", "error creating overtime record", map[string]interface{}{
 			"error": err.Error(),
@@ -150,6 +320,14 @@ func (o *OvertimeService) CreateNewRecordOvertime(payload *payloads.CreateNewRec
 		return err
 	}
 
+	if err := o.recordInitialStatus(&overtime, category, violations, userID, c, tx); err != nil {
+		helpers.MyLogger("error", "OvertimeManagement", "CreateNewRecordOvertime", "service", "error recording status history", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		tx.Rollback()
+		return err
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		helpers.MyLogger("error", "OvertimeManagement", "CreateNewRecordOvertime", "service", "error committing transaction", map[string]interface{}{
 			"error": err.Error(),
@@ -158,38 +336,81 @@ func (o *OvertimeService) CreateNewRecordOvertime(payload *payloads.CreateNewRec
 		return err
 	}
 
+	overtime.Category = category
+	if err := o.tagCalendarFlagsOne("CreateNewRecordOvertime", &overtime, c, database.ClientPostgres); err != nil {
+		return err
+	}
+
 	helpers.MyLogger("info", "OvertimeManagement", "CreateNewRecordOvertime", "service", "overtime record created successfully", map[string]interface{}{
 		"overtime_id": overtime.ID,
 	}, c)
 	return helpers.Response(c, fiber.StatusCreated, "Overtime record created successfully", overtime)
 }
 
-// GetAllRecordOvertimeByTelegramID retrieves all overtime records by telegram ID
-func (o *OvertimeService) GetAllRecordOvertimeByTelegramID(telegramID int64, c *fiber.Ctx, tx *gorm.DB) error {
+// GetAllRecordOvertimeByTelegramID lists overtime records of a telegram user one page at a time, newest first by default
+func (o *OvertimeService) GetAllRecordOvertimeByTelegramID(telegramID int64, query *payloads.GetOvertimeListQuery, c *fiber.Ctx, tx *gorm.DB) error {
 	helpers.MyLogger("debug", "OvertimeManagement", "GetAllRecordOvertimeByTelegramID", "service", "start get all overtime records by telegram ID", map[string]interface{}{
 		"telegram_id": telegramID,
+		"query":       query,
 	}, c)
 
+	page, ok, err := parseListPage("OvertimeManagement", "GetAllRecordOvertimeByTelegramID", query.ListQuery, o.OvertimeRepository.OvertimeSortKeys(), "id", c)
+	if !ok {
+		return err
+	}
+
+	filter := repositories.OvertimeListFilter{
+		TelegramID:   telegramID,
+		CategoryName: strings.TrimSpace(query.Category),
+		CategoryID:   query.CategoryID,
+		MinDuration:  query.MinDuration,
+		MaxDuration:  query.MaxDuration,
+		Status:       query.Status,
+	}
+	if query.StartDate != "" {
+		startDate, err := helpers.ParseDateWithTimezone(query.StartDate)
+		if err != nil {
+			return helpers.Response(c, fiber.StatusBadRequest, "Invalid start date format. Use YYYY-MM-DD", nil)
+		}
+		filter.StartDate = &startDate
+	}
+	if query.EndDate != "" {
+		endDate, err := helpers.ParseDateWithTimezone(query.EndDate)
+		if err != nil {
+			return helpers.Response(c, fiber.StatusBadRequest, "Invalid end date format. Use YYYY-MM-DD", nil)
+		}
+		filter.EndDate = &endDate
+	}
+	if filter.StartDate != nil && filter.EndDate != nil && filter.EndDate.Before(*filter.StartDate) {
+		return helpers.Response(c, fiber.StatusBadRequest, "End date must be after start date", nil)
+	}
+	if filter.MinDuration != nil && filter.MaxDuration != nil && *filter.MaxDuration < *filter.MinDuration {
+		return helpers.Response(c, fiber.StatusBadRequest, "Max duration must be greater than or equal to min duration", nil)
+	}
+
 	var overtimes []entities.Overtime
-	err := o.OvertimeRepository.GetAllRecordOvertimeByTelegramID(telegramID, &overtimes, c, tx)
+	var total int64
+	nextCursor, err := o.OvertimeRepository.ListRecordOvertimeByTelegramID(filter, page, &overtimes, &total, c, tx)
 	if err != nil {
-		if helpers.IsNotFoundError(err) {
-			helpers.MyLogger("info", "OvertimeManagement", "GetAllRecordOvertimeByTelegramID", "service", "no overtime records found for telegram user", map[string]interface{}{
-				"telegram_id": telegramID,
-			}, c)
-			return helpers.Response(c, fiber.StatusNotFound, "No overtime records found", nil)
-		}
 		helpers.MyLogger("error", "OvertimeManagement", "GetAllRecordOvertimeByTelegramID", "service", "error getting overtime records", map[string]interface{}{
 			"error": err.Error(),
 		}, c)
 		return err
 	}
 
+	if err := o.tagCalendarFlags("GetAllRecordOvertimeByTelegramID", overtimes, c, tx); err != nil {
+		return err
+	}
+
 	helpers.MyLogger("info", "OvertimeManagement", "GetAllRecordOvertimeByTelegramID", "service", "overtime records retrieved successfully", map[string]interface{}{
 		"telegram_id":   telegramID,
 		"records_count": len(overtimes),
+		"total_count":   total,
 	}, c)
-	return helpers.Response(c, fiber.StatusOK, "Overtime records retrieved successfully", overtimes)
+	if overtimes == nil {
+		overtimes = []entities.Overtime{}
+	}
+	return helpers.ResponsePage(c, fiber.StatusOK, "Overtime records retrieved successfully", overtimes, page.Info(nextCursor, total))
 }
 
 // GetRecordByDateByTelegramID retrieves overtime record by specific date and telegram ID
@@ -215,6 +436,10 @@ func (o *OvertimeService) GetRecordByDateByTelegramID(telegramID int64, date tim
 		return err
 	}
 
+	if err := o.tagCalendarFlags("GetRecordByDateByTelegramID", overtime, c, tx); err != nil {
+		return err
+	}
+
 	helpers.MyLogger("info", "OvertimeManagement", "GetRecordByDateByTelegramID", "service", "overtime record retrieved successfully", map[string]interface{}{
 		"telegram_id": telegramID,
 		"date":        date,
@@ -248,6 +473,10 @@ func (o *OvertimeService) GetRecordBetweenDateByTelegramId(telegramID int64, sta
 		return err
 	}
 
+	if err := o.tagCalendarFlags("GetRecordBetweenDateByTelegramId", overtimes, c, tx); err != nil {
+		return err
+	}
+
 	// Calculate total duration for the period
 	var totalDuration float64
 	for _, overtime := range overtimes {
@@ -296,6 +525,10 @@ func (o *OvertimeService) GetRecordByID(id uint, c *fiber.Ctx, tx *gorm.DB) erro
 		return err
 	}
 
+	if err := o.tagCalendarFlagsOne("GetRecordByID", &overtime, c, tx); err != nil {
+		return err
+	}
+
 	helpers.MyLogger("info", "OvertimeManagement", "GetRecordByID", "service", "overtime record retrieved successfully", map[string]interface{}{
 		"overtime_id": id,
 	}, c)
@@ -322,8 +555,9 @@ func (o *OvertimeService) UpdateRecordOvertime(id uint, payload *payloads.Update
 	helpers.MyLogger("debug", "OvertimeManagement", "UpdateRecordOvertime", "service", "check if record exists | calling repository GetRecordByID", map[string]interface{}{
 		"overtime_id": id,
 	}, c)
+	// Locked so an approval cannot slip in between the status check and the update
 	var existingOvertime entities.Overtime
-	err := o.OvertimeRepository.GetRecordByID(id, &existingOvertime, c, tx)
+	err := o.OvertimeRepository.GetRecordByIDForUpdate(id, &existingOvertime, c, tx)
 	if err != nil {
 		if helpers.IsNotFoundError(err) {
 			helpers.MyLogger("info", "OvertimeManagement", "UpdateRecordOvertime", "service", "overtime record not found", map[string]interface{}{
@@ -336,6 +570,13 @@ func (o *OvertimeService) UpdateRecordOvertime(id uint, payload *payloads.Update
 		}, c)
 		return err
 	}
+	if existingOvertime.Status == entities.OvertimeStatusApproved {
+		helpers.MyLogger("info", "OvertimeManagement", "UpdateRecordOvertime", "service", "overtime record is approved", map[string]interface{}{
+			"overtime_id": id,
+		}, c)
+		tx.Rollback()
+		return lockedResponse(c)
+	}
 
 	// Prepare update data - only update fields that are provided
 	updates := make(map[string]interface{})
@@ -450,27 +691,59 @@ func (o *OvertimeService) UpdateRecordOvertime(id uint, payload *payloads.Update
 		}, c)
 	}
 
-	// Handle Category update
-	if payload.Category != "" {
-		updates["category"] = payload.Category
+	// Handle Category update, category_id 0 removes the category
+	if payload.Category != "" || payload.CategoryID != nil {
+		category, ok, err := resolveCategory(&o.CategoryRepository, "UpdateRecordOvertime", payload.Category, payload.CategoryID, c, tx)
+		if !ok || err != nil {
+			tx.Rollback()
+			return err
+		}
+		if category != nil {
+			updates["category_id"] = category.ID
+		} else {
+			updates["category_id"] = nil
+		}
 		helpers.MyLogger("debug", "OvertimeManagement", "UpdateRecordOvertime", "service", "category will be updated", map[string]interface{}{
-			"category": payload.Category,
+			"category":    payload.Category,
+			"category_id": updates["category_id"],
 		}, c)
 	}
 
-	// Handle Duration update
-	if payload.Duration > 0 {
-		updates["duration"] = payload.Duration
-		helpers.MyLogger("debug", "OvertimeManagement", "UpdateRecordOvertime", "service", "duration will be updated", map[string]interface{}{
-			"duration": payload.Duration,
+	// Handle BreakDuration update
+	if payload.BreakDuration != nil {
+		updates["break_duration"] = *payload.BreakDuration
+		helpers.MyLogger("debug", "OvertimeManagement", "UpdateRecordOvertime", "service", "break_duration will be updated", map[string]interface{}{
+			"break_duration": *payload.BreakDuration,
 		}, c)
 	}
 
-	// Handle BreakDuration update
-	if payload.BreakDuration >= 0 {
-		updates["break_duration"] = payload.BreakDuration
-		helpers.MyLogger("debug", "OvertimeManagement", "UpdateRecordOvertime", "service", "break_duration will be updated", map[string]interface{}{
-			"break_duration": payload.BreakDuration,
+	// Recompute duration whenever the time range or break changes, a supplied duration is only checked
+	if payload.TimeStart != "" || payload.TimeStop != "" || payload.BreakDuration != nil || payload.Duration != nil {
+		timeStart, timeStop, breakDuration := existingOvertime.TimeStart, existingOvertime.TimeStop, existingOvertime.BreakDuration
+		if value, ok := updates["time_start"].(time.Time); ok {
+			timeStart = value
+		}
+		if value, ok := updates["time_stop"].(time.Time); ok {
+			timeStop = value
+		}
+		if payload.BreakDuration != nil {
+			breakDuration = *payload.BreakDuration
+		}
+		var provided float64
+		if payload.Duration != nil {
+			provided = *payload.Duration
+		}
+		duration, err := computeDuration(timeStart, timeStop, breakDuration, provided)
+		if err != nil {
+			helpers.MyLogger("info", "OvertimeManagement", "UpdateRecordOvertime", "service", "duration rejected", map[string]interface{}{
+				"error": err.Error(),
+			}, c)
+			tx.Rollback()
+			return durationErrorResponse(c, err)
+		}
+		updates["duration"] = duration
+		helpers.MyLogger("debug", "OvertimeManagement", "UpdateRecordOvertime", "service", "duration will be updated", map[string]interface{}{
+			"duration": duration,
 		}, c)
 	}
 
@@ -487,11 +760,83 @@ func (o *OvertimeService) UpdateRecordOvertime(id uint, payload *payloads.Update
 		"updates": updates,
 	}, c)
 
+	// Check overlaps against the record as it will look after the update
+	merged := existingOvertime
+	if value, ok := updates["telegram_user_id"].(uint); ok {
+		merged.TelegramUserID = value
+	}
+	if value, ok := updates["date"].(time.Time); ok {
+		merged.Date = value
+	}
+	if value, ok := updates["time_start"].(time.Time); ok {
+		merged.TimeStart = value
+	}
+	if value, ok := updates["time_stop"].(time.Time); ok {
+		merged.TimeStop = value
+	}
+	if value, ok := updates["duration"].(float64); ok {
+		merged.Duration = value
+	}
+	helpers.MyLogger("debug", "OvertimeManagement", "UpdateRecordOvertime", "service", "check overlapping records | calling repository FindOverlappingIDs", map[string]interface{}{
+		"overtime_id":      id,
+		"telegram_user_id": merged.TelegramUserID,
+	}, c)
+	conflictingIDs, err := o.findOverlaps(&merged, id, c, tx)
+	if err != nil {
+		helpers.MyLogger("error", "OvertimeManagement", "UpdateRecordOvertime", "service", "error checking overlapping records", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		tx.Rollback()
+		return err
+	}
+	if len(conflictingIDs) > 0 {
+		helpers.MyLogger("info", "OvertimeManagement", "UpdateRecordOvertime", "service", "overtime record overlaps existing records", map[string]interface{}{
+			"overtime_id":     id,
+			"conflicting_ids": conflictingIDs,
+		}, c)
+		tx.Rollback()
+		return overlapResponse(c, conflictingIDs)
+	}
+
+	// Check the limits against the record as it will look after the update
+	violations, policy, err := checkOvertimeLimits(&o.OvertimeRepository, &o.OvertimeLimitRepository, &merged, id, c, tx)
+	if err != nil {
+		helpers.MyLogger("error", "OvertimeManagement", "UpdateRecordOvertime", "service", "error checking overtime limits", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		tx.Rollback()
+		return err
+	}
+	if len(violations) > 0 {
+		helpers.MyLogger("info", "OvertimeManagement", "UpdateRecordOvertime", "service", "overtime record exceeds limits", map[string]interface{}{
+			"overtime_id": id,
+			"mode":        policy.Mode,
+			"violations":  violations,
+		}, c)
+		if policy.Mode == limits.ModeBlock {
+			tx.Rollback()
+			return limitBlockedResponse(c, violations)
+		}
+		// draft and rejected records go back to the approval queue, submitted ones are already there
+		if policy.Mode == limits.ModeRequireApproval && existingOvertime.Status != entities.OvertimeStatusSubmitted {
+			updates["status"] = entities.OvertimeStatusSubmitted
+		}
+	}
+
 	helpers.MyLogger("debug", "OvertimeManagement", "UpdateRecordOvertime", "service", "calling repository to update overtime record", map[string]interface{}{
 		"overtime_id": id,
 	}, c)
 	err = o.OvertimeRepository.UpdateRecordOvertimePartial(id, updates, c, tx)
 	if err != nil {
+		if helpers.IsExclusionViolationError(err) {
+			tx.Rollback()
+			conflictingIDs, _ = o.findOverlaps(&merged, id, c, database.ClientPostgres)
+			helpers.MyLogger("info", "OvertimeManagement", "UpdateRecordOvertime", "service", "overtime record overlaps existing records", map[string]interface{}{
+				"overtime_id":     id,
+				"conflicting_ids": conflictingIDs,
+			}, c)
+			return overlapResponse(c, conflictingIDs)
+		}
 		helpers.MyLogger("error", "OvertimeManagement", "UpdateRecordOvertime", "service", "error updating overtime record", map[string]interface{}{
 			"error": err.Error(),
 		}, c)
@@ -499,6 +844,23 @@ func (o *OvertimeService) UpdateRecordOvertime(id uint, payload *payloads.Update
 		return err
 	}
 
+	if updates["status"] == entities.OvertimeStatusSubmitted {
+		history := entities.OvertimeStatusHistory{
+			OvertimeID:      id,
+			FromStatus:      existingOvertime.Status,
+			ToStatus:        entities.OvertimeStatusSubmitted,
+			Reason:          limitReason(violations),
+			ChangedByUserID: userID,
+		}
+		if err := o.OvertimeStatusHistoryRepository.Create(&history, c, tx); err != nil {
+			helpers.MyLogger("error", "OvertimeManagement", "UpdateRecordOvertime", "service", "error recording status history", map[string]interface{}{
+				"error": err.Error(),
+			}, c)
+			tx.Rollback()
+			return err
+		}
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		helpers.MyLogger("error", "OvertimeManagement", "UpdateRecordOvertime", "service", "error committing transaction", map[string]interface{}{
 			"error": err.Error(),
@@ -520,6 +882,11 @@ func (o *OvertimeService) UpdateRecordOvertime(id uint, payload *payloads.Update
 		return helpers.Response(c, fiber.StatusInternalServerError, "Record updated but failed to retrieve updated data", nil)
 	}
 
+	if err := o.tagCalendarFlagsOne("UpdateRecordOvertime", &updatedRecord, c, database.ClientPostgres); err != nil {
+		return err
+	}
+	updatedRecord.Warnings = violations
+
 	helpers.MyLogger("info", "OvertimeManagement", "UpdateRecordOvertime", "service", "overtime record updated successfully", map[string]interface{}{
 		"overtime_id":    id,
 		"updated_fields": len(updates),
@@ -540,7 +907,7 @@ func (o *OvertimeService) DeleteRecordOvertime(id uint, c *fiber.Ctx, tx *gorm.D
 		"overtime_id": id,
 	}, c)
 	var overtime entities.Overtime
-	err := o.OvertimeRepository.GetRecordByID(id, &overtime, c, tx)
+	err := o.OvertimeRepository.GetRecordByIDForUpdate(id, &overtime, c, tx)
 	if err != nil {
 		if helpers.IsNotFoundError(err) {
 			helpers.MyLogger("info", "OvertimeManagement", "DeleteRecordOvertime", "service", "overtime record not found", map[string]interface{}{
@@ -553,6 +920,13 @@ func (o *OvertimeService) DeleteRecordOvertime(id uint, c *fiber.Ctx, tx *gorm.D
 		}, c)
 		return err
 	}
+	if overtime.Status == entities.OvertimeStatusApproved {
+		helpers.MyLogger("info", "OvertimeManagement", "DeleteRecordOvertime", "service", "overtime record is approved", map[string]interface{}{
+			"overtime_id": id,
+		}, c)
+		tx.Rollback()
+		return lockedResponse(c)
+	}
 
 	helpers.MyLogger("debug", "OvertimeManagement", "DeleteRecordOvertime", "service", "record found, proceeding with deletion", map[string]interface{}{
 		"overtime_id":      id,
@@ -563,7 +937,7 @@ func (o *OvertimeService) DeleteRecordOvertime(id uint, c *fiber.Ctx, tx *gorm.D
 	helpers.MyLogger("debug", "OvertimeManagement", "DeleteRecordOvertime", "service", "calling repository to delete overtime record", map[string]interface{}{
 		"overtime_id": id,
 	}, c)
-	err = o.OvertimeRepository.DeleteRecordOvertime(id, c, tx)
+	err = o.OvertimeRepository.DeleteRecordOvertime(id, userID, c, tx)
 	if err != nil {
 		helpers.MyLogger("error", "OvertimeManagement", "DeleteRecordOvertime", "service", "error deleting overtime record", map[string]interface{}{
 			"error": err.Error(),
@@ -584,5 +958,5 @@ func (o *OvertimeService) DeleteRecordOvertime(id uint, c *fiber.Ctx, tx *gorm.D
 		"overtime_id": id,
 		"deleted_by":  userID,
 	}, c)
-	return helpers.Response(c, fiber.StatusOK, "Overtime record deleted successfully", nil)
+	return helpers.Response(c, fiber.StatusOK, "Overtime record deleted successfully", trashInfo(id, time.Now()))
 }
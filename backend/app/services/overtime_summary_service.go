@@ -0,0 +1,150 @@
+package services
+
+import (
+	"math"
+	"time"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/payloads"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/calendar"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/helpers"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/repositories"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+func summaryTotals(row repositories.OvertimeSummaryRow) payloads.OvertimeSummaryTotals {
+	round := func(value float64) float64 { return math.Round(value*100) / 100 }
+	return payloads.OvertimeSummaryTotals{
+		RecordsCount:         row.RecordsCount,
+		DaysCount:            row.DaysCount,
+		TotalDuration:        round(row.TotalDuration),
+		TotalBreakDuration:   round(row.TotalBreakDuration),
+		AverageDuration:      round(row.AverageDuration),
+		AverageBreakDuration: round(row.AverageBreakDuration),
+		AverageDailyDuration: round(row.AverageDailyDuration),
+	}
+}
+
+// summaryBucket labels a bucket row, time buckets get the first and last day of their calendar day, week or month
+func summaryBucket(groupBy string, row repositories.OvertimeSummaryRow, holidays holidaySet) payloads.OvertimeSummaryBucket {
+	bucket := payloads.OvertimeSummaryBucket{OvertimeSummaryTotals: summaryTotals(row)}
+	if groupBy == "category" {
+		bucket.CategoryID = row.CategoryID
+		bucket.Category = "Tanpa kategori"
+		if row.CategoryName != nil {
+			bucket.Category = *row.CategoryName
+		}
+		return bucket
+	}
+	if row.BucketStart == nil {
+		return bucket
+	}
+	start, err := helpers.ParseDateWithTimezone(*row.BucketStart)
+	if err != nil {
+		return bucket
+	}
+	end := start
+	switch groupBy {
+	case "week":
+		end = start.AddDate(0, 0, 6)
+	case "month":
+		end = start.AddDate(0, 1, -1)
+	case "day":
+		isHoliday, isWeekend := holidays.has(start), calendar.IsWeekend(start)
+		bucket.IsHoliday, bucket.IsWeekend = &isHoliday, &isWeekend
+	}
+	bucket.StartDate, bucket.EndDate = start.Format("2006-01-02"), end.Format("2006-01-02")
+	return bucket
+}
+
+// GetOvertimeSummary aggregates overtime of one or more telegram users between two dates per day, week, month or category.
+// Sums and averages come from one GROUPING SETS query, telegram users without records get zero totals.
+func (o *OvertimeService) GetOvertimeSummary(telegramIDs []int64, startDate time.Time, endDate time.Time, groupBy string, status string, c *fiber.Ctx, tx *gorm.DB) error {
+	helpers.MyLogger("debug", "OvertimeManagement", "GetOvertimeSummary", "service", "start get overtime summary", map[string]interface{}{
+		"telegram_ids": telegramIDs,
+		"start_date":   startDate,
+		"end_date":     endDate,
+		"group_by":     groupBy,
+		"status":       status,
+	}, c)
+
+	var existing []int64
+	if err := o.TelegramRepository.FindExistingTelegramIDs(telegramIDs, &existing, c, tx); err != nil {
+		helpers.MyLogger("error", "OvertimeManagement", "GetOvertimeSummary", "service", "error finding telegram users", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return err
+	}
+	found := map[int64]bool{}
+	for _, telegramID := range existing {
+		found[telegramID] = true
+	}
+	missing := []int64{}
+	for _, telegramID := range telegramIDs {
+		if !found[telegramID] {
+			missing = append(missing, telegramID)
+		}
+	}
+	if len(missing) > 0 {
+		helpers.MyLogger("info", "OvertimeManagement", "GetOvertimeSummary", "service", "telegram user not found", map[string]interface{}{
+			"telegram_ids": missing,
+		}, c)
+		return helpers.Response(c, fiber.StatusNotFound, "Telegram user not found", map[string]interface{}{
+			"missing_telegram_ids": missing,
+		})
+	}
+
+	var rows []repositories.OvertimeSummaryRow
+	if err := o.OvertimeRepository.Summarize(telegramIDs, startDate, endDate, groupBy, status, &rows, c, tx); err != nil {
+		helpers.MyLogger("error", "OvertimeManagement", "GetOvertimeSummary", "service", "error summarizing overtime records", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return err
+	}
+
+	var holidays holidaySet
+	if groupBy == "day" {
+		var err error
+		if holidays, err = loadHolidaySet(&o.HolidayRepository, startDate, endDate, c, tx); err != nil {
+			return err
+		}
+	}
+
+	response := payloads.OvertimeSummaryResponse{
+		GroupBy:  groupBy,
+		Timezone: helpers.GetTimezone().String(),
+		Period: payloads.OvertimePayPeriod{
+			StartDate: startDate.Format("2006-01-02"),
+			EndDate:   endDate.Format("2006-01-02"),
+		},
+		Status: status,
+		Users:  make([]payloads.OvertimeSummaryUser, 0, len(telegramIDs)),
+	}
+	users := map[int64]*payloads.OvertimeSummaryUser{}
+	for _, telegramID := range telegramIDs {
+		if _, ok := users[telegramID]; ok {
+			continue
+		}
+		response.Users = append(response.Users, payloads.OvertimeSummaryUser{TelegramID: telegramID, Buckets: []payloads.OvertimeSummaryBucket{}})
+		users[telegramID] = &response.Users[len(response.Users)-1]
+	}
+	for _, row := range rows {
+		switch {
+		case row.AllUsers:
+			response.Totals = summaryTotals(row)
+		case row.TelegramID == nil || users[*row.TelegramID] == nil:
+			continue
+		case row.AllBuckets:
+			users[*row.TelegramID].Totals = summaryTotals(row)
+		default:
+			user := users[*row.TelegramID]
+			user.Buckets = append(user.Buckets, summaryBucket(groupBy, row, holidays))
+		}
+	}
+
+	helpers.MyLogger("info", "OvertimeManagement", "GetOvertimeSummary", "service", "overtime summary retrieved successfully", map[string]interface{}{
+		"telegram_ids":  telegramIDs,
+		"records_count": response.Totals.RecordsCount,
+	}, c)
+	return helpers.Response(c, fiber.StatusOK, "Overtime summary retrieved successfully", response)
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"time"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/entities"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/payloads"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/database"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/helpers"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/limits"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// trashInfo returns when a record deleted at deletedAt will be purged by the retention job
+func trashInfo(id uint, deletedAt time.Time) payloads.OvertimeTrashInfo {
+	info := payloads.OvertimeTrashInfo{ID: id, DeletedAt: deletedAt}
+	if days, err := database.OvertimeTrashRetentionDays(); err == nil && days > 0 {
+		purgeAt := deletedAt.AddDate(0, 0, days)
+		info.PurgeAt = &purgeAt
+	}
+	return info
+}
+
+// GetTrashByTelegramID lists the deleted overtime records of a telegram user that can still be restored
+func (o *OvertimeService) GetTrashByTelegramID(query *payloads.GetOvertimeTrashQuery, c *fiber.Ctx, tx *gorm.DB) error {
+	helpers.MyLogger("debug", "OvertimeManagement", "GetTrashByTelegramID", "service", "start get overtime trash", map[string]interface{}{
+		"telegram_id": query.TelegramID,
+	}, c)
+
+	page, ok, err := parseListPage("OvertimeManagement", "GetTrashByTelegramID", query.ListQuery, o.OvertimeRepository.TrashSortKeys(), "deleted_at", c)
+	if !ok {
+		return err
+	}
+
+	var overtimes []entities.Overtime
+	var total int64
+	nextCursor, err := o.OvertimeRepository.ListTrashByTelegramID(query.TelegramID, page, &overtimes, &total, c, tx)
+	if err != nil {
+		helpers.MyLogger("error", "OvertimeManagement", "GetTrashByTelegramID", "service", "error getting overtime trash", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return err
+	}
+
+	helpers.MyLogger("info", "OvertimeManagement", "GetTrashByTelegramID", "service", "overtime trash retrieved successfully", map[string]interface{}{
+		"telegram_id":   query.TelegramID,
+		"records_count": len(overtimes),
+		"total_count":   total,
+	}, c)
+	if overtimes == nil {
+		overtimes = []entities.Overtime{}
+	}
+	return helpers.ResponsePage(c, fiber.StatusOK, "Overtime trash retrieved successfully", overtimes, page.Info(nextCursor, total))
+}
+
+// RestoreRecordOvertime takes an overtime record out of the trash. The overlap and limit checks of create apply,
+// a record created meanwhile in the same time range blocks the restore and require_approval sends it to the approval queue.
+func (o *OvertimeService) RestoreRecordOvertime(id uint, c *fiber.Ctx, tx *gorm.DB) error {
+	userID := helpers.GetCurrentUserID(c)
+	helpers.MyLogger("debug", "OvertimeManagement", "RestoreRecordOvertime", "service", "start restore overtime record", map[string]interface{}{
+		"overtime_id": id,
+		"user_id":     userID,
+	}, c)
+
+	var overtime entities.Overtime
+	if err := o.OvertimeRepository.GetTrashedRecordByIDForUpdate(id, &overtime, c, tx); err != nil {
+		if helpers.IsNotFoundError(err) {
+			helpers.MyLogger("info", "OvertimeManagement", "RestoreRecordOvertime", "service", "overtime record not found in trash", map[string]interface{}{
+				"overtime_id": id,
+			}, c)
+			return helpers.Response(c, fiber.StatusNotFound, "Overtime record not found in trash", nil)
+		}
+		helpers.MyLogger("error", "OvertimeManagement", "RestoreRecordOvertime", "service", "error finding overtime record in trash", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return err
+	}
+
+	conflictingIDs, err := o.findOverlaps(&overtime, id, c, tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if len(conflictingIDs) > 0 {
+		helpers.MyLogger("info", "OvertimeManagement", "RestoreRecordOvertime", "service", "overtime record overlaps existing records", map[string]interface{}{
+			"overtime_id":     id,
+			"conflicting_ids": conflictingIDs,
+		}, c)
+		tx.Rollback()
+		return overlapResponse(c, conflictingIDs)
+	}
+
+	violations, policy, err := checkOvertimeLimits(&o.OvertimeRepository, &o.OvertimeLimitRepository, &overtime, id, c, tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if len(violations) > 0 {
+		helpers.MyLogger("info", "OvertimeManagement", "RestoreRecordOvertime", "service", "overtime record exceeds limits", map[string]interface{}{
+			"overtime_id": id,
+			"mode":        policy.Mode,
+			"violations":  violations,
+		}, c)
+		if policy.Mode == limits.ModeBlock {
+			tx.Rollback()
+			return limitBlockedResponse(c, violations)
+		}
+	}
+
+	if err := o.OvertimeRepository.RestoreRecordOvertime(id, c, tx); err != nil {
+		if helpers.IsExclusionViolationError(err) {
+			tx.Rollback()
+			conflictingIDs, _ = o.findOverlaps(&overtime, id, c, database.ClientPostgres)
+			return overlapResponse(c, conflictingIDs)
+		}
+		helpers.MyLogger("error", "OvertimeManagement", "RestoreRecordOvertime", "service", "error restoring overtime record", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		tx.Rollback()
+		return err
+	}
+
+	// same as update: draft and rejected records go back to the approval queue, submitted ones are already there
+	if len(violations) > 0 && policy.Mode == limits.ModeRequireApproval && overtime.Status != entities.OvertimeStatusSubmitted {
+		if err := o.OvertimeRepository.UpdateRecordOvertimePartial(id, map[string]interface{}{"status": entities.OvertimeStatusSubmitted}, c, tx); err != nil {
+			helpers.MyLogger("error", "OvertimeManagement", "RestoreRecordOvertime", "service", "error submitting restored overtime record", map[string]interface{}{
+				"error": err.Error(),
+			}, c)
+			tx.Rollback()
+			return err
+		}
+		history := entities.OvertimeStatusHistory{
+			OvertimeID:      id,
+			FromStatus:      overtime.Status,
+			ToStatus:        entities.OvertimeStatusSubmitted,
+			Reason:          limitReason(violations),
+			ChangedByUserID: userID,
+		}
+		if err := o.OvertimeStatusHistoryRepository.Create(&history, c, tx); err != nil {
+			helpers.MyLogger("error", "OvertimeManagement", "RestoreRecordOvertime", "service", "error recording status history", map[string]interface{}{
+				"error": err.Error(),
+			}, c)
+			tx.Rollback()
+			return err
+		}
+	}
+
+	var restored entities.Overtime
+	if err := o.OvertimeRepository.GetRecordByID(id, &restored, c, tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit().Error; err != nil {
+		helpers.MyLogger("error", "OvertimeManagement", "RestoreRecordOvertime", "service", "error committing transaction", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		tx.Rollback()
+		return err
+	}
+
+	if err := o.tagCalendarFlagsOne("RestoreRecordOvertime", &restored, c, database.ClientPostgres); err != nil {
+		return err
+	}
+	restored.Warnings = violations
+
+	helpers.MyLogger("info", "OvertimeManagement", "RestoreRecordOvertime", "service", "overtime record restored successfully", map[string]interface{}{
+		"overtime_id": id,
+		"restored_by": userID,
+	}, c)
+	return helpers.Response(c, fiber.StatusOK, "Overtime record restored successfully", restored)
+}
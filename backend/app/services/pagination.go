@@ -0,0 +1,20 @@
+package services
+
+import (
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/payloads"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/helpers"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/pagination"
+	"github.com/gofiber/fiber/v2"
+)
+
+// parseListPage validates the pagination of a list against the sort whitelist, it writes the 400 response itself when ok is false
+func parseListPage(module string, method string, query payloads.ListQuery, allowed []string, defaultSort string, c *fiber.Ctx) (pagination.Request, bool, error) {
+	page, err := pagination.Parse(query.Limit, query.Cursor, query.Sort, query.Order, allowed, defaultSort, pagination.OrderDesc)
+	if err != nil {
+		helpers.MyLogger("info", module, method, "service", "invalid pagination", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return page, false, helpers.Response(c, fiber.StatusBadRequest, "Invalid pagination: "+err.Error(), nil)
+	}
+	return page, true, nil
+}
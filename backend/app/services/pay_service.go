@@ -0,0 +1,251 @@
+package services
+
+import (
+	"encoding/json"
+	"math"
+	"time"
+
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/entities"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/payloads"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/helpers"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/payroll"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/repositories"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type PayService struct {
+	OvertimeRepository   repositories.OvertimeRepository
+	PayProfileRepository repositories.PayProfileRepository
+	HolidayRepository    repositories.HolidayRepository
+	TelegramRepository   repositories.TelegramRepository
+}
+
+// canSeePay reports whether the current user is an approver or admin, they see the pay of every employee including the salary
+func canSeePay(c *fiber.Ctx) bool {
+	user, _ := c.Locals("user").(entities.User)
+	return user.Role == entities.RoleApprover || user.Role == entities.RoleAdmin
+}
+
+// toPayrollProfile builds the engine profile, stored tiers override the statutory ones
+func toPayrollProfile(profile *entities.PayProfile) (payroll.Profile, error) {
+	result := payroll.DefaultProfile(profile.MonthlySalary, profile.WorkWeekDays)
+	if profile.HourlyDivisor > 0 {
+		result.HourlyDivisor = profile.HourlyDivisor
+	}
+	if profile.Tiers != "" {
+		if err := json.Unmarshal([]byte(profile.Tiers), &result.Tiers); err != nil {
+			return result, err
+		}
+	}
+	return result, result.Validate()
+}
+
+func payProfileResponse(telegramID int64, profile *entities.PayProfile, payrollProfile payroll.Profile) payloads.PayProfileResponse {
+	monthlySalary := payrollProfile.MonthlySalary
+	return payloads.PayProfileResponse{
+		TelegramID:    telegramID,
+		MonthlySalary: &monthlySalary,
+		WorkWeekDays:  payrollProfile.WorkWeekDays,
+		HourlyDivisor: payrollProfile.HourlyDivisor,
+		HourlyWage:    math.Round(payrollProfile.HourlyWage()*100) / 100,
+		CustomTiers:   profile.Tiers != "",
+		Tiers:         payrollProfile.Tiers,
+		UpdatedAt:     profile.UpdatedAt,
+	}
+}
+
+// findPayProfile resolves telegram_id and loads its pay profile, it writes the 404 response itself when found is false.
+// Only the linked user, approvers and admins may see it, other users get the same 404 as an unknown telegram ID.
+func (p *PayService) findPayProfile(method string, telegramID int64, profile *entities.PayProfile, c *fiber.Ctx, tx *gorm.DB) (bool, error) {
+	var telegramUser entities.TelegramUser
+	if err := p.TelegramRepository.FindByTelegramID(telegramID, &telegramUser, c, tx); err != nil && !helpers.IsNotFoundError(err) {
+		return false, err
+	} else if err != nil || (telegramUser.UserID != helpers.GetCurrentUserID(c) && !canSeePay(c)) {
+		helpers.MyLogger("info", "OvertimePay", method, "service", "telegram user not found", map[string]interface{}{
+			"telegram_id": telegramID,
+			"user_id":     helpers.GetCurrentUserID(c),
+		}, c)
+		return false, helpers.Response(c, fiber.StatusNotFound, "Telegram user not found", nil)
+	}
+
+	if err := p.PayProfileRepository.FindByTelegramUserID(telegramUser.ID, profile, c, tx); err != nil {
+		if helpers.IsNotFoundError(err) {
+			helpers.MyLogger("info", "OvertimePay", method, "service", "pay profile not found", map[string]interface{}{
+				"telegram_id": telegramID,
+			}, c)
+			return false, helpers.Response(c, fiber.StatusNotFound, "Pay profile not found, set it first with PUT /v1/overtime/pay/profile/{telegram_id}", nil)
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// GetOvertimePay calculates pay of approved overtime records between two dates with a per-day breakdown
+func (p *PayService) GetOvertimePay(telegramID int64, startDate time.Time, endDate time.Time, c *fiber.Ctx, tx *gorm.DB) error {
+	helpers.MyLogger("debug", "OvertimePay", "GetOvertimePay", "service", "start calculate overtime pay", map[string]interface{}{
+		"telegram_id": telegramID,
+		"start_date":  startDate,
+		"end_date":    endDate,
+	}, c)
+
+	var profile entities.PayProfile
+	found, err := p.findPayProfile("GetOvertimePay", telegramID, &profile, c, tx)
+	if !found {
+		return err
+	}
+
+	payrollProfile, err := toPayrollProfile(&profile)
+	if err != nil {
+		helpers.MyLogger("error", "OvertimePay", "GetOvertimePay", "service", "invalid stored pay profile", map[string]interface{}{
+			"error":       err.Error(),
+			"telegram_id": telegramID,
+		}, c)
+		return err
+	}
+
+	var overtimes []entities.Overtime
+	if err := p.OvertimeRepository.GetRecordBetweenDateByTelegramId(telegramID, startDate, endDate, &overtimes, c, tx); err != nil {
+		helpers.MyLogger("error", "OvertimePay", "GetOvertimePay", "service", "error getting overtime records", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return err
+	}
+
+	entries := make([]payroll.Entry, 0, len(overtimes))
+	unapprovedCount := 0
+	unapprovedHours := 0.0
+	for _, overtime := range overtimes {
+		if overtime.Status != entities.OvertimeStatusApproved {
+			unapprovedCount++
+			unapprovedHours += overtime.Duration
+			continue
+		}
+		entry := payroll.Entry{RecordID: overtime.ID, Date: overtime.Date, Hours: overtime.Duration}
+		if overtime.Category != nil && overtime.Category.PayMultiplier != nil {
+			entry.Multiplier = *overtime.Category.PayMultiplier
+		}
+		entries = append(entries, entry)
+	}
+
+	holidays, err := loadHolidaySet(&p.HolidayRepository, startDate, endDate, c, tx)
+	if err != nil {
+		helpers.MyLogger("error", "OvertimePay", "GetOvertimePay", "service", "error getting holidays", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return err
+	}
+
+	result := payrollProfile.Calculate(entries, holidays.has)
+
+	profileResponse := payProfileResponse(telegramID, &profile, payrollProfile)
+	if !canSeePay(c) {
+		// employees see their own pay and hourly wage, the salary itself stays with approvers
+		profileResponse.MonthlySalary = nil
+	}
+
+	helpers.MyLogger("info", "OvertimePay", "GetOvertimePay", "service", "overtime pay calculated successfully", map[string]interface{}{
+		"telegram_id":      telegramID,
+		"approved_records": len(entries),
+		"total_pay":        result.TotalPay,
+	}, c)
+	return helpers.Response(c, fiber.StatusOK, "Overtime pay calculated successfully", payloads.OvertimePayResponse{
+		TelegramID: telegramID,
+		Period: payloads.OvertimePayPeriod{
+			StartDate: startDate.Format("2006-01-02"),
+			EndDate:   endDate.Format("2006-01-02"),
+		},
+		Profile:                profileResponse,
+		Result:                 result,
+		UnapprovedRecordsCount: unapprovedCount,
+		UnapprovedHours:        math.Round(unapprovedHours*100) / 100,
+	})
+}
+
+// GetPayProfile retrieves the pay profile of a telegram user with the effective tiers
+func (p *PayService) GetPayProfile(telegramID int64, c *fiber.Ctx, tx *gorm.DB) error {
+	helpers.MyLogger("debug", "OvertimePay", "GetPayProfile", "service", "start get pay profile", map[string]interface{}{
+		"telegram_id": telegramID,
+	}, c)
+
+	var profile entities.PayProfile
+	found, err := p.findPayProfile("GetPayProfile", telegramID, &profile, c, tx)
+	if !found {
+		return err
+	}
+
+	payrollProfile, err := toPayrollProfile(&profile)
+	if err != nil {
+		return err
+	}
+	return helpers.Response(c, fiber.StatusOK, "Pay profile retrieved successfully", payProfileResponse(telegramID, &profile, payrollProfile))
+}
+
+// UpsertPayProfile creates or replaces the pay profile of a telegram user
+func (p *PayService) UpsertPayProfile(telegramID int64, payload *payloads.UpsertPayProfilePayload, c *fiber.Ctx, tx *gorm.DB) error {
+	userID := helpers.GetCurrentUserID(c)
+	helpers.MyLogger("debug", "OvertimePay", "UpsertPayProfile", "service", "start upsert pay profile", map[string]interface{}{
+		"telegram_id": telegramID,
+		"user_id":     userID,
+	}, c)
+
+	telegramUserID, err := p.OvertimeRepository.GetTelegramUserIDByTelegramID(telegramID, c, tx)
+	if err != nil {
+		if helpers.IsNotFoundError(err) {
+			return helpers.Response(c, fiber.StatusNotFound, "Telegram user not found", nil)
+		}
+		return err
+	}
+
+	profile := entities.PayProfile{
+		TelegramUserID:  telegramUserID,
+		MonthlySalary:   payload.MonthlySalary,
+		WorkWeekDays:    payload.WorkWeekDays,
+		HourlyDivisor:   payload.HourlyDivisor,
+		UpdatedByUserID: userID,
+	}
+	if profile.WorkWeekDays == 0 {
+		profile.WorkWeekDays = 5
+	}
+	if profile.HourlyDivisor == 0 {
+		profile.HourlyDivisor = payroll.DefaultHourlyDivisor
+	}
+	if payload.Tiers != nil {
+		tiers, err := json.Marshal(payload.Tiers)
+		if err != nil {
+			return err
+		}
+		profile.Tiers = string(tiers)
+	}
+
+	payrollProfile, err := toPayrollProfile(&profile)
+	if err != nil {
+		helpers.MyLogger("info", "OvertimePay", "UpsertPayProfile", "service", "invalid pay profile", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return helpers.Response(c, fiber.StatusUnprocessableEntity, "Invalid pay profile: "+err.Error(), nil)
+	}
+
+	if err := p.PayProfileRepository.Upsert(&profile, c, tx); err != nil {
+		helpers.MyLogger("error", "OvertimePay", "UpsertPayProfile", "service", "error saving pay profile", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		helpers.MyLogger("error", "OvertimePay", "UpsertPayProfile", "service", "error committing transaction", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		tx.Rollback()
+		return err
+	}
+
+	helpers.MyLogger("info", "OvertimePay", "UpsertPayProfile", "service", "pay profile saved successfully", map[string]interface{}{
+		"telegram_id":  telegramID,
+		"custom_tiers": profile.Tiers != "",
+		"updated_by":   userID,
+	}, c)
+	return helpers.Response(c, fiber.StatusOK, "Pay profile saved successfully", payProfileResponse(telegramID, &profile, payrollProfile))
+}
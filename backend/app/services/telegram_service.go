@@ -1,7 +1,14 @@
 package services
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/entities"
 	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/payloads"
@@ -12,43 +19,153 @@ import (
 )
 
 type TelegramService struct {
-	TelegramRepository repositories.TelegramRepository
+	TelegramRepository         repositories.TelegramRepository
+	TelegramLinkCodeRepository repositories.TelegramLinkCodeRepository
 }
 
-func (t *TelegramService) CreateNewUserForNowUserActive(payload *payloads.CreateNewTelegramPayload, c *fiber.Ctx, tx *gorm.DB) error {
+// linkCodeTTL is how long a link code can be used (TELEGRAM_LINK_CODE_TTL in minutes, default 10)
+func linkCodeTTL() time.Duration {
+	minutes, err := strconv.Atoi(helpers.GetEnv("TELEGRAM_LINK_CODE_TTL", "10"))
+	if err != nil || minutes <= 0 {
+		minutes = 10
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// hashLinkCode is the value stored in the database, the plain code is only shown once to the user
+func hashLinkCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateLinkCode creates a single-use code for the current user, opened as t.me/<bot>?start=<code>
+func (t *TelegramService) CreateLinkCode(c *fiber.Ctx, tx *gorm.DB) error {
 	userID := helpers.GetCurrentUserID(c)
-	helpers.LogBusiness("telegram_create_new_user_telegram", strconv.Itoa(int(userID)), map[string]interface{}{
-		"user_id":     userID,
+	helpers.MyLogger("debug", "TelegramAccountLink", "CreateLinkCode", "service", "start create link code", map[string]interface{}{
+		"user_id": userID,
+	}, c)
+
+	// 18 random bytes = 24 base64url characters, allowed by the start parameter (A-Z, a-z, 0-9, _ and -)
+	raw := make([]byte, 18)
+	if _, err := rand.Read(raw); err != nil {
+		return err
+	}
+	code := base64.RawURLEncoding.EncodeToString(raw)
+
+	linkCode := entities.TelegramLinkCode{
+		UserID:    userID,
+		CodeHash:  hashLinkCode(code),
+		ExpiresAt: helpers.NowWithTimezone().Add(linkCodeTTL()),
+	}
+	if err := t.TelegramLinkCodeRepository.Create(&linkCode, c, tx); err != nil {
+		helpers.MyLogger("error", "TelegramAccountLink", "CreateLinkCode", "service", "error create link code", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit().Error; err != nil {
+		helpers.MyLogger("error", "TelegramAccountLink", "CreateLinkCode", "service", "error committing transaction", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		tx.Rollback()
+		return err
+	}
+
+	response := payloads.TelegramLinkCodeResponse{
+		Code:      code,
+		Link:      fmt.Sprintf("https://t.me/%s?start=%s", strings.TrimPrefix(helpers.GetEnv("TELEGRAM_BOT_USERNAME", ""), "@"), code),
+		ExpiresAt: linkCode.ExpiresAt,
+	}
+	helpers.MyLogger("info", "TelegramAccountLink", "CreateLinkCode", "service", "success create link code", map[string]interface{}{
+		"user_id":      userID,
+		"link_code_id": linkCode.ID,
+		"expires_at":   linkCode.ExpiresAt,
+	}, c)
+	return helpers.Response(c, fiber.StatusCreated, "Link code created successfully", response)
+}
+
+// LinkWithCode links the verified Telegram sender to the owner of the code. Expired or used codes are rejected.
+func (t *TelegramService) LinkWithCode(payload *payloads.LinkTelegramAccountPayload, c *fiber.Ctx, tx *gorm.DB) error {
+	helpers.MyLogger("debug", "TelegramAccountLink", "LinkWithCode", "service", "start link telegram account with code", map[string]interface{}{
 		"telegram_id": payload.TelegramID,
-		"username":    payload.Username,
-		"first_name":  payload.FirstName,
-		"last_name":   payload.LastName,
-	})
-	helpers.Logger.Debug().Msg("TelegramService: CreateNewUserForNowUserActive Calling Repository for create new telegram user")
-	var telegramUser entities.TelegramUser
-	telegramUser.UserID = userID
-	telegramUser.TelegramID = payload.TelegramID
-	telegramUser.Username = payload.Username
-	telegramUser.FirstName = payload.FirstName
-	telegramUser.LastName = payload.LastName
-	err := t.TelegramRepository.Create(&telegramUser, c, tx)
+	}, c)
+
+	var linkCode entities.TelegramLinkCode
+	if err := t.TelegramLinkCodeRepository.FindByCodeHashForUpdate(hashLinkCode(payload.Code), &linkCode, c, tx); err != nil {
+		if helpers.IsNotFoundError(err) {
+			helpers.MyLogger("info", "TelegramAccountLink", "LinkWithCode", "service", "link code not found", map[string]interface{}{
+				"telegram_id": payload.TelegramID,
+			}, c)
+			return helpers.Response(c, fiber.StatusNotFound, "Link code not found", nil)
+		}
+		helpers.MyLogger("error", "TelegramAccountLink", "LinkWithCode", "service", "error find link code", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
+		return err
+	}
+	if linkCode.UsedAt != nil {
+		helpers.MyLogger("info", "TelegramAccountLink", "LinkWithCode", "service", "link code already used", map[string]interface{}{
+			"link_code_id": linkCode.ID,
+			"telegram_id":  payload.TelegramID,
+		}, c)
+		return helpers.Response(c, fiber.StatusGone, "Link code has already been used", nil)
+	}
+	now := helpers.NowWithTimezone()
+	if !linkCode.ExpiresAt.After(now) {
+		helpers.MyLogger("info", "TelegramAccountLink", "LinkWithCode", "service", "link code expired", map[string]interface{}{
+			"link_code_id": linkCode.ID,
+			"telegram_id":  payload.TelegramID,
+		}, c)
+		return helpers.Response(c, fiber.StatusGone, "Link code has expired", nil)
+	}
+
+	var existing entities.TelegramUser
+	if err := t.TelegramRepository.FindByTelegramID(payload.TelegramID, &existing, c, tx); err == nil {
+		return helpers.Response(c, fiber.StatusConflict, "Telegram account is already linked", nil)
+	} else if !helpers.IsNotFoundError(err) {
+		return err
+	}
+
+	marked, err := t.TelegramLinkCodeRepository.MarkUsed(linkCode.ID, payload.TelegramID, now, c, tx)
 	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if !marked {
+		return helpers.Response(c, fiber.StatusGone, "Link code has already been used", nil)
+	}
+
+	telegramUser := entities.TelegramUser{
+		UserID:     linkCode.UserID,
+		TelegramID: payload.TelegramID,
+		Username:   payload.Username,
+		FirstName:  payload.FirstName,
+		LastName:   payload.LastName,
+	}
+	if err := t.TelegramRepository.Create(&telegramUser, c, tx); err != nil {
+		tx.Rollback()
 		if helpers.IsDuplicateKeyError(err) {
-			helpers.Logger.Info().Msg("TelegramService: CreateNewUserForNowUserActive Telegram user already exists")
-			tx.Rollback()
-			return helpers.Response(c, fiber.StatusOK, "Telegram user already exists", nil)
+			return helpers.Response(c, fiber.StatusConflict, "Telegram account is already linked", nil)
 		}
-		helpers.Logger.Error().Err(err).Msg("TelegramService: CreateNewUserForNowUserActive Error creating telegram user")
-		tx.Rollback()
+		helpers.MyLogger("error", "TelegramAccountLink", "LinkWithCode", "service", "error create telegram user", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
 		return err
 	}
 	if err := tx.Commit().Error; err != nil {
-		helpers.Logger.Error().Err(err).Msg("TelegramService: CreateNewUserForNowUserActive Error committing transaction")
+		helpers.MyLogger("error", "TelegramAccountLink", "LinkWithCode", "service", "error committing transaction", map[string]interface{}{
+			"error": err.Error(),
+		}, c)
 		tx.Rollback()
 		return err
 	}
-	helpers.Logger.Info().Interface("telegramUser", telegramUser).Msg("TelegramService: CreateNewUserForNowUserActive Telegram user created successfully")
-	return helpers.Response(c, fiber.StatusCreated, "Telegram user created successfully", telegramUser)
+	helpers.LogBusiness("telegram_account_linked", strconv.Itoa(int(linkCode.UserID)), map[string]interface{}{
+		"user_id":      linkCode.UserID,
+		"telegram_id":  payload.TelegramID,
+		"link_code_id": linkCode.ID,
+	})
+	return helpers.Response(c, fiber.StatusCreated, "Telegram account linked successfully", telegramUser)
 }
 
 func (t *TelegramService) DeleteByTelegramID(telegramID int64, c *fiber.Ctx, tx *gorm.DB) error {
@@ -85,27 +202,31 @@ func (t *TelegramService) DeleteByTelegramID(telegramID int64, c *fiber.Ctx, tx
 	return helpers.Response(c, fiber.StatusOK, "Telegram user deleted successfully", nil)
 }
 
-// find by user id
-func (t *TelegramService) FindByUserID(userID uint, c *fiber.Ctx, tx *gorm.DB) error {
+// FindByUserID lists the telegram accounts linked to a user one cursor page at a time, an empty page is not an error
+func (t *TelegramService) FindByUserID(userID uint, query *payloads.GetTelegramUsersQuery, c *fiber.Ctx, tx *gorm.DB) error {
 	helpers.MyLogger("debug", "TelegramAccountLink", "FindByUserId", "service", "start find telegram user by user ID", nil, c)
-	var telegramUser []entities.TelegramUser
-	if err := t.TelegramRepository.FindByUserID(userID, &telegramUser, c, tx); err != nil {
-		if helpers.IsNotFoundError(err) {
-			helpers.MyLogger("info", "TelegramAccountLink", "FindByUserId", "service", "not found telegram user by user ID", map[string]interface{}{
-				"user_id_throw": userID,
-			}, c)
-			// tx.Rollback()
-			return helpers.Response(c, fiber.StatusNotFound, "Telegram user not found", nil)
-		}
+	page, ok, err := parseListPage("TelegramAccountLink", "FindByUserId", query.ListQuery, t.TelegramRepository.TelegramUserSortKeys(), "id", c)
+	if !ok {
+		return err
+	}
+
+	var telegramUsers []entities.TelegramUser
+	var total int64
+	nextCursor, err := t.TelegramRepository.ListByUserID(userID, strings.TrimSpace(query.Search), page, &telegramUsers, &total, c, tx)
+	if err != nil {
 		helpers.MyLogger("error", "TelegramAccountLink", "FindByUserId", "service", "error find telegram user by user ID", map[string]interface{}{
 			"error": err.Error(),
 		}, c)
 		return err
 	}
 	helpers.MyLogger("info", "TelegramAccountLink", "FindByUserId", "service", "success find telegram user by user ID", map[string]interface{}{
-		"telegram_user": telegramUser,
+		"records_count": len(telegramUsers),
+		"total_count":   total,
 	}, c)
-	return helpers.Response(c, fiber.StatusOK, "Telegram user found successfully", telegramUser)
+	if telegramUsers == nil {
+		telegramUsers = []entities.TelegramUser{}
+	}
+	return helpers.ResponsePage(c, fiber.StatusOK, "Telegram user found successfully", telegramUsers, page.Info(nextCursor, total))
 }
 
 func (t *TelegramService) FindByTelegramID(telegramID int64, c *fiber.Ctx, tx *gorm.DB) error {
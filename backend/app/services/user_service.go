@@ -3,6 +3,7 @@ package services
 import (
 	"errors"
 	"strconv"
+	"strings"
 
 	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/entities"
 	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/payloads"
@@ -38,12 +39,28 @@ func (u *UserService) GetUserById(c *fiber.Ctx, tx *gorm.DB) error {
 	return helpers.Response(c, fiber.StatusOK, "User retrieved successfully", user)
 }
 
-func (u *UserService) GetAllUsers(c *fiber.Ctx, tx *gorm.DB) error {
+// GetAllUsers lists users one cursor page at a time, filtered by role and username/email search
+func (u *UserService) GetAllUsers(query *payloads.GetUsersQuery, c *fiber.Ctx, tx *gorm.DB) error {
+	page, ok, err := parseListPage("UserManagement", "GetAllUsers", query.ListQuery, u.UserRepository.UserSortKeys(), "id", c)
+	if !ok {
+		return err
+	}
+
+	filter := repositories.UserListFilter{
+		Role:   query.Role,
+		Search: strings.TrimSpace(query.Search),
+	}
 	var users []entities.User
-	if err := u.UserRepository.FindAll(&users, tx); err != nil {
+	var total int64
+	nextCursor, err := u.UserRepository.List(filter, page, &users, &total, tx, c)
+	if err != nil {
+		helpers.LogError(err, "GetAllUsers", "UserService: error listing users", nil, c)
 		return err
 	}
-	return helpers.Response(c, fiber.StatusOK, "Users retrieved successfully", users)
+	if users == nil {
+		users = []entities.User{}
+	}
+	return helpers.ResponsePage(c, fiber.StatusOK, "Users retrieved successfully", users, page.Info(nextCursor, total))
 }
 
 func (u *UserService) GetApiKeyFromUserActive(c *fiber.Ctx, tx *gorm.DB) error {
@@ -181,3 +198,44 @@ func (u *UserService) CreateApiKey(payload *payloads.CreateApiKeyPayload, c *fib
 
 	return helpers.Response(c, fiber.StatusOK, "API key created successfully", response)
 }
+
+// UpdateUserRole mengubah role user (user, approver, admin), hanya untuk admin
+func (u *UserService) UpdateUserRole(payload *payloads.UpdateUserRolePayload, c *fiber.Ctx, tx *gorm.DB) error {
+	userID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		helpers.LogError(err, "UpdateUserRole", "UserService: error when parsing ID", nil, c)
+		return helpers.ResponseErrorBadRequest(c, "Invalid ID", nil)
+	}
+
+	var user entities.User
+	if err := u.UserRepository.FindByID(uint(userID), &user, tx); err != nil {
+		if helpers.IsNotFoundError(err) {
+			helpers.LogInfo("UpdateUserRole", "UserService: user not found", nil, c)
+			return helpers.ResponseErrorNotFound(c, nil)
+		}
+		helpers.LogError(err, "UpdateUserRole", "UserService: error finding user by ID", nil, c)
+		return err
+	}
+
+	helpers.LogDebug("UpdateUserRole", "UserService: calling repository UpdateRole", map[string]interface{}{
+		"userID":  userID,
+		"oldRole": user.Role,
+		"newRole": payload.Role,
+	}, c)
+	if err := u.UserRepository.UpdateRole(user.ID, payload.Role, tx, c); err != nil {
+		helpers.LogError(err, "UpdateUserRole", "UserService: error updating role", nil, c)
+		return err
+	}
+	if err := tx.Commit().Error; err != nil {
+		helpers.LogError(err, "UpdateUserRole", "UserService: error committing transaction", nil, c)
+		return err
+	}
+
+	user.Role = payload.Role
+	helpers.LogInfo("UpdateUserRole", "UserService: role updated successfully", map[string]interface{}{
+		"userID":    userID,
+		"role":      payload.Role,
+		"changedBy": helpers.GetCurrentUserID(c),
+	}, c)
+	return helpers.Response(c, fiber.StatusOK, "User role updated successfully", user)
+}
@@ -123,6 +123,81 @@ const docTemplate = `{
             }
         },
         "/v1/overtime/": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the overtime records of a telegram user on one date (date) or between two dates (start_date and end_date, inclusive). Replaces POST /v1/overtime/by-date and POST /v1/overtime/between-dates",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Overtime"
+                ],
+                "summary": "Get Overtime Records by Date or Date Range",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Telegram ID",
+                        "name": "telegram_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date (YYYY-MM-DD), not combined with start_date and end_date",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD), required with end_date",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date (YYYY-MM-DD), required with start_date",
+                        "name": "end_date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Overtime records retrieved successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Overtime record not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
             "post": {
                 "security": [
                     {
@@ -189,7 +264,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get overtime record for a specific date and telegram ID",
+                "description": "Get overtime record for a specific date and telegram ID. Deprecated, use GET /v1/overtime/?telegram_id=\u0026date=",
                 "consumes": [
                     "application/json"
                 ],
@@ -200,6 +275,7 @@ const docTemplate = `{
                     "Overtime"
                 ],
                 "summary": "Get Overtime Record by Date",
+                "deprecated": true,
                 "parameters": [
                     {
                         "description": "Date and telegram ID",
@@ -311,7 +387,6 @@ const docTemplate = `{
             "type": "object",
             "required": [
                 "date",
-                "duration",
                 "telegram_id",
                 "time_start",
                 "time_stop"
@@ -323,8 +398,10 @@ const docTemplate = `{
                 },
                 "category": {
                     "type": "string",
-                    "maxLength": 255,
-                    "minLength": 3
+                    "maxLength": 100
+                },
+                "category_id": {
+                    "type": "integer"
                 },
                 "date": {
                     "description": "Format: \"2006-01-02\" or \"2006-01-02T15:04:05\"",
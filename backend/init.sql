@@ -10,5 +10,8 @@
 -- Create extensions if needed
 -- CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
 
+-- Dipakai oleh exclusion constraint overtimes_no_overlap (lembur yang beririsan)
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
 -- Initial setup completed
 SELECT 'Database initialized successfully' as status;
@@ -1,12 +1,20 @@
 package main
 
 import (
+	"context"
 	"log"
+	"time"
 
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/bot"
 	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/controllers"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/entities"
 	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/middlewares"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/blobstore"
 	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/database"
 	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/helpers"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/pkg/telegram"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/scheduler"
+	"github.com/Nyuuk/mini-app-bot-telegram/backend/app/services"
 	"github.com/gofiber/fiber/v2"
 	"github.com/joho/godotenv"
 	fiberSwagger "github.com/swaggo/fiber-swagger"
@@ -50,6 +58,11 @@ func main() {
 		log.Fatal("Error connecting to database")
 	}
 
+	// Storage lampiran lembur: filesystem lokal (default) atau S3-compatible
+	if err := blobstore.Open(); err != nil {
+		log.Fatal("Error opening blob storage: ", err)
+	}
+
 	// run auto migration
 	// if err := database.ClientPostgres.AutoMigrate(
 	// 	&entities.User{},
@@ -61,7 +74,10 @@ func main() {
 	// }
 
 	log.Println("Starting server...")
-	app := fiber.New()
+	app := fiber.New(fiber.Config{
+		// cukup untuk beberapa lampiran lembur sekaligus, lihat ATTACHMENT_MAX_SIZE_MB
+		BodyLimit: services.AttachmentBodyLimit(),
+	})
 
 	// Apply logging middlewares
 	app.Use(middlewares.LoggingMiddleware())
@@ -92,12 +108,59 @@ func main() {
 	userController := controllers.UserController{}
 	telegramController := controllers.TelegramController{}
 	overtimeController := controllers.OvertimeController{}
+	payController := controllers.PayController{}
+	overtimeLimitController := controllers.OvertimeLimitController{}
+	holidayController := controllers.HolidayController{}
+	categoryController := controllers.CategoryController{}
+	overtimeFeedController := controllers.OvertimeFeedController{}
+	overtimeAttachmentController := controllers.OvertimeAttachmentController{}
+
+	// Telegram bot update dispatcher
+	dispatcher := bot.NewDispatcher()
+	telegramClient := telegram.NewClient()
+	telegramBot := bot.New(telegramClient)
+	telegramBot.Register(dispatcher)
+	telegramWebhookController := controllers.TelegramWebhookController{Dispatcher: dispatcher}
+
+	botMode := helpers.GetEnv("TELEGRAM_BOT_MODE", "webhook")
+	if telegramClient.Token != "" {
+		if err := telegramBot.Setup(context.Background(), botMode); err != nil {
+			log.Println("Failed to setup telegram bot:", err)
+		}
+	}
+
+	// Background jobs (pengingat harian, pembersihan trash lembur dan update_id telegram), advisory lock memastikan hanya satu replica yang mengirim
+	jobs, err := telegramBot.Jobs()
+	if err != nil {
+		log.Fatal("Invalid scheduler configuration: ", err)
+	}
+	trashJobs, err := database.TrashJobs()
+	if err != nil {
+		log.Fatal("Invalid scheduler configuration: ", err)
+	}
+	jobScheduler := scheduler.New(database.ClientPostgres)
+	jobScheduler.Add(jobs...)
+	jobScheduler.Add(trashJobs...)
+	jobScheduler.Start(context.Background())
+
+	// TELEGRAM_BOT_MODE=polling menjalankan getUpdates loop (untuk development atau server di belakang NAT)
+	if botMode == "polling" {
+		log.Println("Starting telegram long polling...")
+		poller := bot.NewPoller(telegramClient, dispatcher)
+		go poller.Run(context.Background())
+	}
 
 	// Public routes (tidak perlu auth)
 	auth := app.Group("/v1/auth").Name("auth")
 	auth.Post("/login", authController.Login)         // Login untuk dapat JWT
 	auth.Post("/register", userController.CreateUser) // Register user baru
 
+	// Telegram webhook (auth via X-Telegram-Bot-Api-Secret-Token), harus didaftarkan sebelum group protected
+	app.Post("/v1/telegram/webhook", telegramWebhookController.ReceiveUpdate)
+
+	// Calendar feed lembur (auth via token di path), untuk subscribe di aplikasi kalender
+	app.Get("/v1/overtime/feed/:token.ics", overtimeFeedController.GetOvertimeFeed)
+
 	// Protected routes (perlu auth via API Key atau JWT)
 	protected := app.Group("/v1", middlewares.AuthMiddleware()).Name("protected")
 
@@ -112,24 +175,93 @@ func main() {
 	user.Get("/:id", userController.GetUserById)       // Get user by ID (admin only)
 	user.Delete("/:id", userController.DeleteUserById) // Delete user by ID (admin only)
 
+	// Change user role (user, approver, admin)
+	user.Put("/:id/role", middlewares.RequireRole(entities.RoleAdmin), userController.UpdateUserRole)
+
 	telegram := protected.Group("/telegram").Name("telegram")
-	telegram.Post("/", telegramController.CreateNewUserForNowUserActive) // Create new user for now user active
+	telegram.Post("/", telegramController.CreateNewUserForNowUserActive) // Deprecated, responds 410 Gone
+	telegram.Post("/link-code", telegramController.CreateLinkCode)       // Create one-time link code for t.me/<bot>?start=<code>
 	telegram.Get("/", telegramController.FindByUserID)                   // Get all user telegram
 	telegram.Get("/:id", telegramController.FindByTelegramID)            // Get user telegram by ID
 	telegram.Delete("/:id", telegramController.DeleteByTelegramID)       // Delete user telegram by ID
 	telegram.Put("/:id", telegramController.UpdateByTelegramID)          // Update user telegram by ID
 
 	// Overtime routes
+	approverOnly := middlewares.RequireRole(entities.RoleApprover, entities.RoleAdmin)
+
+	// Overtime pay (Kepmenakertrans 102/2004 by default), harus didaftarkan sebelum /overtime/:id
+	pay := protected.Group("/overtime/pay").Name("overtime-pay")
+	pay.Get("/", payController.GetOvertimePay)                                     // Pay of approved records with per-day breakdown
+	pay.Get("/profile/:telegram_id", approverOnly, payController.GetPayProfile)    // Salary and rate tiers of an employee
+	pay.Put("/profile/:telegram_id", approverOnly, payController.UpsertPayProfile) // Create or replace salary and rate tiers
+
+	// Overtime limits per employee (default PP 35/2021: 4 jam sehari, 18 jam seminggu), harus didaftarkan sebelum /overtime/:id
+	overtimeLimit := protected.Group("/overtime/limits").Name("overtime-limits")
+	overtimeLimit.Get("/:telegram_id", approverOnly, overtimeLimitController.GetOvertimeLimit)       // Default, override and effective limits
+	overtimeLimit.Put("/:telegram_id", approverOnly, overtimeLimitController.UpsertOvertimeLimit)    // Create or replace the override
+	overtimeLimit.Delete("/:telegram_id", approverOnly, overtimeLimitController.DeleteOvertimeLimit) // Back to the default limits
+
+	// Calendar feed token per employee, harus didaftarkan sebelum /overtime/:id
+	overtimeFeed := protected.Group("/overtime/feed").Name("overtime-feed")
+	overtimeFeed.Get("/:telegram_id", overtimeFeedController.GetFeedToken)             // Whether the feed is active, never shows the token
+	overtimeFeed.Post("/:telegram_id/token", overtimeFeedController.RotateFeedToken)   // Create or rotate the token, returns the subscribe URL once
+	overtimeFeed.Delete("/:telegram_id/token", overtimeFeedController.RevokeFeedToken) // Revoke, the old URL answers 404
+
 	overtime := protected.Group("/overtime").Name("overtime")
 	overtime.Post("/", overtimeController.CreateNewRecordOvertime)                              // Create new overtime record
+	overtime.Post("/import", overtimeController.ImportRecordOvertime)                           // Bulk create from a CSV file, ?dry_run=true only validates
 	overtime.Get("/telegram/:telegram_id", overtimeController.GetAllRecordOvertimeByTelegramID) // Get all overtime records by telegram ID
-	overtime.Post("/by-date", overtimeController.GetRecordByDateByTelegramID)                   // Get overtime record by specific date
-	overtime.Post("/between-dates", overtimeController.GetRecordBetweenDateByTelegramId)        // Get overtime records between dates
+	overtime.Get("/", overtimeController.GetOvertimeRecords)                                    // Get overtime records by ?date= or ?start_date=&end_date=
 	overtime.Put("/", overtimeController.UpdateRecordOvertime)                                  // Update overtime record
+	overtime.Get("/pending", approverOnly, overtimeController.GetPendingApprovals)              // Submitted records waiting for approval
+	overtime.Get("/summary", overtimeController.GetOvertimeSummary)                             // Totals and averages per day, week, month or category
+	overtime.Get("/trash", overtimeController.GetTrashByTelegramID)                             // Deleted records that can still be restored
+	overtime.Get("/export", overtimeController.ExportTimesheet)                                 // Timesheet file, ?format=csv|xlsx|pdf
 	overtime.Get("/:id", overtimeController.GetRecordByID)                                      // Get overtime record by ID
 	overtime.Put("/:id", overtimeController.UpdateRecordOvertime)                               // Update overtime record
 	overtime.Delete("/", overtimeController.DeleteRecordOvertime)                               // Delete overtime record (flexible ID)
-	overtime.Delete("/:id", overtimeController.DeleteRecordOvertime)                            // Delete overtime record
+	overtime.Delete("/:id", overtimeController.DeleteRecordOvertime)                            // Move overtime record to the trash
+	overtime.Post("/:id/restore", overtimeController.RestoreRecordOvertime)                     // Take overtime record out of the trash
+
+	// Deprecated: digantikan GET /v1/overtime/, tetap dilayani dengan header Deprecation dan Sunset sampai tanggal sunset
+	deprecatedOvertimeQuery := middlewares.Deprecated(
+		time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC),
+		time.Date(2027, time.April, 30, 0, 0, 0, 0, time.UTC),
+		"/v1/overtime/",
+	)
+	overtime.Post("/by-date", deprecatedOvertimeQuery, overtimeController.GetRecordByDateByTelegramID)
+	overtime.Post("/between-dates", deprecatedOvertimeQuery, overtimeController.GetRecordBetweenDateByTelegramId)
+
+	// Overtime approval workflow: draft -> submitted -> approved/rejected, approved records are locked until reopened
+	overtime.Post("/:id/submit", overtimeController.SubmitRecordOvertime)
+	overtime.Post("/:id/approve", approverOnly, overtimeController.ApproveRecordOvertime)
+	overtime.Post("/:id/reject", approverOnly, overtimeController.RejectRecordOvertime)
+	overtime.Post("/:id/reopen", approverOnly, overtimeController.ReopenRecordOvertime)
+	overtime.Get("/:id/history", overtimeController.GetStatusHistory)
+
+	// Evidence attachments: owner or admin upload and delete, owner, approver and admin download
+	overtime.Post("/:id/attachments", overtimeAttachmentController.UploadAttachments)                 // Multipart field "file", repeatable
+	overtime.Get("/:id/attachments", overtimeAttachmentController.ListAttachments)                    // Attachment metadata
+	overtime.Get("/:id/attachments/:attachment_id", overtimeAttachmentController.DownloadAttachment)  // Download the stored file
+	overtime.Delete("/:id/attachments/:attachment_id", overtimeAttachmentController.DeleteAttachment) // Delete metadata and stored file
+
+	// Holiday routes (libur nasional dan cuti bersama), perubahan hanya untuk admin
+	adminOnly := middlewares.RequireRole(entities.RoleAdmin)
+	holiday := protected.Group("/holiday").Name("holiday")
+	holiday.Get("/", holidayController.GetHolidays)                      // List holidays by year or date range
+	holiday.Post("/", adminOnly, holidayController.CreateHoliday)        // Create holiday
+	holiday.Post("/import", adminOnly, holidayController.ImportHolidays) // Import .ics or .csv file
+	holiday.Get("/:id", holidayController.GetHolidayByID)                // Get holiday by ID
+	holiday.Put("/:id", adminOnly, holidayController.UpdateHoliday)      // Update holiday
+	holiday.Delete("/:id", adminOnly, holidayController.DeleteHoliday)   // Delete holiday
+
+	// Category routes (kategori lembur), perubahan hanya untuk admin
+	category := protected.Group("/category").Name("category")
+	category.Get("/", categoryController.GetCategories)                   // List categories
+	category.Post("/", adminOnly, categoryController.CreateCategory)      // Create category
+	category.Get("/:id", categoryController.GetCategoryByID)              // Get category by ID
+	category.Put("/:id", adminOnly, categoryController.UpdateCategory)    // Update category
+	category.Delete("/:id", adminOnly, categoryController.DeleteCategory) // Delete unused category
 
 	// API Key routes
 	// apikey := protected.Group("/apikey").Name("apikey")